@@ -14,6 +14,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/signal"
 	"github.com/kyverno/kyverno/pkg/utils"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	rest "k8s.io/client-go/rest"
 	clientcmd "k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
@@ -33,6 +34,9 @@ const (
 	clusterReportChangeRequestKind string = "ClusterReportChangeRequest"
 	policyViolation                string = "PolicyViolation"
 	clusterPolicyViolation         string = "ClusterPolicyViolation"
+
+	// reportNameLabel links the shards of a policy report
+	reportNameLabel string = "kyverno.io/report.name"
 )
 
 func main() {
@@ -254,6 +258,20 @@ func removePolicyReport(client *client.Client, kind string) error {
 			fmt.Sprintf("polr-ns-%s", ns.GetName()),
 		}
 
+		// the additional shards of the report are linked by the report name label
+		shards, err := client.ListResource("", kind, ns.GetName(), &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: reportNameLabel, Operator: metav1.LabelSelectorOpExists}},
+		})
+		if err != nil {
+			logger.Error(err, "failed to list policy report shards", "namespace", ns.GetName())
+		} else {
+			for _, shard := range shards.Items {
+				if shard.GetName() != fmt.Sprintf("polr-ns-%s", ns.GetName()) {
+					reportNames = append(reportNames, shard.GetName())
+				}
+			}
+		}
+
 		var wg sync.WaitGroup
 		wg.Add(len(reportNames))
 		for _, reportName := range reportNames {
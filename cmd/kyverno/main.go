@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
@@ -12,18 +13,22 @@ import (
 
 	"github.com/kyverno/kyverno/pkg/cosign"
 
+	prom "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	log "sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/kyverno/kyverno/pkg/auditlog"
 	backwardcompatibility "github.com/kyverno/kyverno/pkg/backward_compatibility"
 	kyvernoclient "github.com/kyverno/kyverno/pkg/client/clientset/versioned"
 	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions"
 	"github.com/kyverno/kyverno/pkg/common"
 	"github.com/kyverno/kyverno/pkg/config"
 	dclient "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/engine"
 	event "github.com/kyverno/kyverno/pkg/event"
 	"github.com/kyverno/kyverno/pkg/generate"
 	generatecleanup "github.com/kyverno/kyverno/pkg/generate/cleanup"
@@ -33,9 +38,11 @@ import (
 	"github.com/kyverno/kyverno/pkg/policy"
 	"github.com/kyverno/kyverno/pkg/policycache"
 	"github.com/kyverno/kyverno/pkg/policyreport"
+	reportquery "github.com/kyverno/kyverno/pkg/policyreport/query"
 	"github.com/kyverno/kyverno/pkg/resourcecache"
 	"github.com/kyverno/kyverno/pkg/signal"
 	ktls "github.com/kyverno/kyverno/pkg/tls"
+	"github.com/kyverno/kyverno/pkg/tracing"
 	"github.com/kyverno/kyverno/pkg/utils"
 	"github.com/kyverno/kyverno/pkg/version"
 	"github.com/kyverno/kyverno/pkg/webhookconfig"
@@ -57,10 +64,26 @@ var (
 	metricsPort                  string
 	webhookTimeout               int
 	genWorkers                   int
+	validationWorkers            int
 	profile                      bool
 	disableMetricsExport         bool
 	policyControllerResyncPeriod time.Duration
 	imagePullSecrets             string
+	imageVerifyCacheSize         int
+	imageVerifyCacheTTL          time.Duration
+	enableTracing                bool
+	tracingAddress               string
+	auditLogSink                 string
+	auditLogPath                 string
+	auditLogURL                  string
+	auditLogMaxSize              int
+	auditLogMaxBackups           int
+	auditLogBatchSize            int
+	auditLogQueueSize            int
+	auditLogFlushInterval        time.Duration
+	enableReportAPI              bool
+	reportAPIPort                string
+	tlsSecret                    string
 	setupLog                     = log.Log.WithName("setup")
 )
 
@@ -72,6 +95,7 @@ func main() {
 	flag.StringVar(&excludeUsername, "excludeUsername", "", "")
 	flag.IntVar(&webhookTimeout, "webhooktimeout", 3, "Timeout for webhook configurations")
 	flag.IntVar(&genWorkers, "gen-workers", 10, "Workers for generate controller")
+	flag.IntVar(&validationWorkers, "validation-workers", 10, "Maximum number of policies evaluated concurrently by the validating webhook")
 	flag.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
 	flag.StringVar(&serverIP, "serverIP", "", "IP address where Kyverno controller runs. Only required if out-of-cluster.")
 	flag.BoolVar(&profile, "profile", false, "Set this flag to 'true', to enable profiling.")
@@ -80,6 +104,21 @@ func main() {
 	flag.StringVar(&metricsPort, "metrics-port", "8000", "Expose prometheus metrics at the given port, default to 8000.")
 	flag.DurationVar(&policyControllerResyncPeriod, "background-scan", time.Hour, "Perform background scan every given interval, e.g., 30s, 15m, 1h.")
 	flag.StringVar(&imagePullSecrets, "imagePullSecrets", "", "Secret resource names for image registry access credentials")
+	flag.IntVar(&imageVerifyCacheSize, "image-verify-cache-size", 1000, "Maximum number of image verification results cached, set to 0 to disable the cache.")
+	flag.DurationVar(&imageVerifyCacheTTL, "image-verify-cache-ttl", 30*time.Minute, "Duration an image verification result is cached, e.g., 30s, 15m, 1h.")
+	flag.BoolVar(&enableTracing, "enable-tracing", false, "Set this flag to 'true', to export traces to an OpenTelemetry collector.")
+	flag.StringVar(&tracingAddress, "tracing-address", "127.0.0.1:4317", "Address of the OpenTelemetry collector receiving traces with OTLP over gRPC, defaults to 127.0.0.1:4317.")
+	flag.StringVar(&auditLogSink, "audit-log-sink", "", "Sink of the admission decision audit log, one of stdout, file or http. The audit log is disabled if not set.")
+	flag.StringVar(&auditLogPath, "audit-log-path", "/var/log/kyverno/audit.log", "Path of the audit log file for the file sink.")
+	flag.IntVar(&auditLogMaxSize, "audit-log-max-size", 100, "Size in megabytes after which the audit log file is rotated, set to 0 to disable the rotation.")
+	flag.IntVar(&auditLogMaxBackups, "audit-log-max-backups", 5, "Number of rotated audit log files kept.")
+	flag.StringVar(&auditLogURL, "audit-log-url", "", "URL the audit records are posted to by the http sink.")
+	flag.IntVar(&auditLogBatchSize, "audit-log-batch-size", 100, "Maximum number of audit records written at once.")
+	flag.IntVar(&auditLogQueueSize, "audit-log-queue-size", 10000, "Maximum number of audit records waiting to be written, records are dropped when the queue is full.")
+	flag.DurationVar(&auditLogFlushInterval, "audit-log-flush-interval", 5*time.Second, "Maximum time an audit record waits before it is written, e.g., 1s, 30s.")
+	flag.BoolVar(&enableReportAPI, "enable-report-api", false, "Set this flag to 'true', to serve the results of the policy reports over HTTP.")
+	flag.StringVar(&reportAPIPort, "report-api-port", "8001", "Serve the policy report query API at the given port, defaults to 8001.")
+	flag.StringVar(&tlsSecret, "tls-secret", "", "Name of an externally managed secret in the Kyverno namespace holding the webhook TLS pair (tls.crt, tls.key) and CA (ca.crt), e.g., issued by cert-manager. The self-signed certificates are not generated when set.")
 
 	if err := flag.Set("v", "2"); err != nil {
 		setupLog.Error(err, "failed to set log level")
@@ -127,6 +166,20 @@ func main() {
 		}()
 	}
 
+	if enableTracing {
+		shutdownTracing, err := tracing.Init(tracingAddress, log.Log.WithName("Tracing"))
+		if err != nil {
+			setupLog.Error(err, "failed to enable tracing", "address", tracingAddress)
+			os.Exit(1)
+		}
+
+		defer func() {
+			if err := shutdownTracing(context.Background()); err != nil {
+				setupLog.Error(err, "failed to flush traces")
+			}
+		}()
+	}
+
 	// KYVERNO CRD CLIENT
 	// access CRD resources
 	//		- ClusterPolicy, Policy
@@ -178,6 +231,20 @@ func main() {
 		}
 	}
 
+	var imageVerifyCacheRequests *prom.CounterVec
+	var policyCacheSize, certificateExpiry, leaderElectionStatus *prom.GaugeVec
+	if promConfig != nil {
+		imageVerifyCacheRequests = promConfig.Metrics.ImageVerifyCache
+		policyCacheSize = promConfig.Metrics.PolicyCacheSize
+		certificateExpiry = promConfig.Metrics.CertificateExpiry
+		leaderElectionStatus = promConfig.Metrics.LeaderElectionStatus
+
+		// the workqueue metrics provider must be set before the controllers create their workqueues
+		workqueue.SetProvider(metrics.NewWorkqueueMetricsProvider(promConfig))
+		engine.InitializeContextMetrics(promConfig.Metrics.ContextLoadDuration, promConfig.Metrics.ContextLoadErrors)
+	}
+	cosign.InitializeCache(imageVerifyCacheSize, imageVerifyCacheTTL, imageVerifyCacheRequests)
+
 	// KYVERNO CRD INFORMER
 	// watches CRD resources:
 	//		- ClusterPolicy, Policy
@@ -222,13 +289,41 @@ func main() {
 		os.Exit(1)
 	}
 
+	if enableReportAPI {
+		reportAPIHandler := reportquery.NewHandler(
+			pInformer.Wgpolicyk8s().V1alpha1().PolicyReports().Lister(),
+			pInformer.Wgpolicyk8s().V1alpha1().ClusterPolicyReports().Lister(),
+			log.Log.WithName("ReportAPI"),
+		)
+
+		// the API is not authenticated and serves the results of all namespaces, it only listens on the loopback interface
+		reportAPIServer := &http.Server{
+			Addr:              net.JoinHostPort("127.0.0.1", reportAPIPort),
+			Handler:           reportAPIHandler.ServeMux(),
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      30 * time.Second,
+		}
+		go func() {
+			setupLog.Info("enabling policy report query API", "address", reportAPIServer.Addr, "path", reportquery.ResultsPath)
+			if err := reportAPIServer.ListenAndServe(); err != nil {
+				setupLog.Error(err, "failed to enable policy report query API", "address", reportAPIServer.Addr)
+				os.Exit(1)
+			}
+		}()
+	}
+
 	debug := serverIP != ""
+	certRenewer := ktls.NewCertRenewer(client, clientConfig, ktls.CertRenewalInterval, ktls.CertValidityDuration, serverIP, tlsSecret, certificateExpiry, log.Log.WithName("CertRenewer"))
 	webhookCfg := webhookconfig.NewRegister(
 		clientConfig,
 		client,
 		rCache,
+		pInformer.Kyverno().V1().ClusterPolicies(),
+		pInformer.Kyverno().V1().Policies(),
 		serverIP,
 		int32(webhookTimeout),
+		certRenewer,
 		debug,
 		log.Log)
 
@@ -253,6 +348,11 @@ func main() {
 		log.Log.WithName("ConfigData"),
 	)
 
+	// the KyvernoConfig takes precedence over the ConfigMap when its CRD is installed
+	if utils.KyvernoConfigInstalled(client.DiscoveryClient) {
+		configData.WatchKyvernoConfig(pclient, pInformer.Kyverno().V1alpha1().KyvernoConfigs())
+	}
+
 	// POLICY CONTROLLER
 	// - reconciliation policy and policy violation
 	// - process policy on existing resources
@@ -321,6 +421,8 @@ func main() {
 	pCacheController := policycache.NewPolicyCacheController(
 		pInformer.Kyverno().V1().ClusterPolicies(),
 		pInformer.Kyverno().V1().Policies(),
+		policyCacheSize,
+		configData,
 		log.Log.WithName("PolicyCacheController"),
 	)
 
@@ -338,11 +440,11 @@ func main() {
 		promConfig,
 	)
 
-	certRenewer := ktls.NewCertRenewer(client, clientConfig, ktls.CertRenewalInterval, ktls.CertValidityDuration, serverIP, log.Log.WithName("CertRenewer"))
 	certManager, err := webhookconfig.NewCertManager(
 		kubeInformer.Core().V1().Secrets(),
 		kubeClient,
 		certRenewer,
+		webhookCfg,
 		log.Log.WithName("CertManager"),
 		stopCh,
 	)
@@ -367,7 +469,6 @@ func main() {
 			setupLog.Error(err, "Timeout registering admission control webhooks")
 			os.Exit(1)
 		}
-		webhookCfg.UpdateWebhookChan <- true
 	}
 
 	// leader election context
@@ -380,20 +481,52 @@ func main() {
 		cancel()
 	}()
 
-	// webhookconfigurations are registered by the leader only
-	webhookRegisterLeader, err := leaderelection.New("webhook-register", config.KyvernoNamespace, kubeClient, registerWebhookConfigurations, nil, log.Log.WithName("webhookRegister/LeaderElection"))
+	// the controllers owning cluster state run on the leader only:
+	// - TLS pair initialization, certificate renewal and webhook registration
+	// - background scan, generate and generate request cleanup
+	// - report change request aggregation into policy reports
+	// - backward compatibility updates of existing resources
+	// the admission webhooks, the policy cache and the report change request and generate
+	// request generators run across all instances, they only create new requests
+	run := func() {
+		registerWebhookConfigurations()
+		go certManager.Run(stopCh)
+		go policyCtrl.Run(2, prgen.ReconcileCh, stopCh)
+		go prgen.Run(1, configData, stopCh)
+		go grc.Run(genWorkers, stopCh)
+		go grcc.Run(1, stopCh)
+		go backwardcompatibility.AddLabels(pclient, pInformer.Kyverno().V1().GenerateRequests())
+		go backwardcompatibility.AddCloneLabel(client, pInformer.Kyverno().V1().ClusterPolicies())
+	}
+
+	// leaderStopped is closed when this instance stops leading, or leaves the leader election
+	// on shutdown. The webhook server is then stopped and the process exits, the leader-only
+	// controllers are never stopped in a running instance and a new instance rejoins the election.
+	leaderStopped := make(chan struct{})
+	stop := func() {
+		close(leaderStopped)
+	}
+
+	kubeClientLeaderElection, err := utils.NewKubeClient(clientConfig)
+	if err != nil {
+		setupLog.Error(err, "Failed to create kubernetes client")
+		os.Exit(1)
+	}
+
+	le, err := leaderelection.New("kyverno", config.KyvernoNamespace, kubeClientLeaderElection, run, stop, leaderElectionStatus, log.Log.WithName("kyverno/LeaderElection"))
 	if err != nil {
-		setupLog.Error(err, "failed to elector leader")
+		setupLog.Error(err, "failed to elect a leader")
 		os.Exit(1)
 	}
 
-	go webhookRegisterLeader.Run(ctx)
+	// the leader initializes the TLS pair read below by all instances
+	go le.Run(ctx)
 
 	// the webhook server runs across all instances
 	openAPIController := startOpenAPIController(client, stopCh)
 
-	var tlsPair *ktls.PemPair
-	tlsPair, err = certManager.GetTLSPemPair()
+	// the TLS pair is served once read, and reloaded from the secret informer afterwards
+	_, err = certManager.GetTLSPemPair()
 	if err != nil {
 		setupLog.Error(err, "Failed to get TLS key/certificate pair")
 		os.Exit(1)
@@ -405,10 +538,31 @@ func main() {
 	// -- annotations on resources with update details on mutation JSON patches
 	// -- generate policy violation resource
 	// -- generate events on policy and resource
+	var admissionLog auditlog.Interface
+	if auditLogSink != "" {
+		al, err := auditlog.New(auditlog.Config{
+			Sink:          auditLogSink,
+			Path:          auditLogPath,
+			MaxSize:       int64(auditLogMaxSize) * 1024 * 1024,
+			MaxBackups:    auditLogMaxBackups,
+			URL:           auditLogURL,
+			BatchSize:     auditLogBatchSize,
+			FlushInterval: auditLogFlushInterval,
+			QueueSize:     auditLogQueueSize,
+		}, log.Log.WithName("AuditLog"))
+		if err != nil {
+			setupLog.Error(err, "Failed to create the audit log")
+			os.Exit(1)
+		}
+
+		go al.Run(stopCh)
+		admissionLog = al
+	}
+
 	server, err := webhooks.NewWebhookServer(
 		pclient,
 		client,
-		tlsPair,
+		certManager.GetCertificate,
 		pInformer.Kyverno().V1().GenerateRequests(),
 		pInformer.Kyverno().V1().ClusterPolicies(),
 		kubeInformer.Rbac().V1().RoleBindings(),
@@ -430,6 +584,8 @@ func main() {
 		rCache,
 		grc,
 		promConfig,
+		validationWorkers,
+		admissionLog,
 	)
 
 	if err != nil {
@@ -437,41 +593,18 @@ func main() {
 		os.Exit(1)
 	}
 
-	// wrap all controllers that need leaderelection
-	// start them once by the leader
-	run := func() {
-		go certManager.Run(stopCh)
-		go policyCtrl.Run(2, prgen.ReconcileCh, stopCh)
-		go prgen.Run(1, stopCh)
-		go grc.Run(genWorkers, stopCh)
-		go grcc.Run(1, stopCh)
-	}
-
-	kubeClientLeaderElection, err := utils.NewKubeClient(clientConfig)
-	if err != nil {
-		setupLog.Error(err, "Failed to create kubernetes client")
-		os.Exit(1)
-	}
-
 	// cleanup Kyverno managed resources followed by webhook shutdown
 	// No need to exit here, as server.Stop(ctx) closes the cleanUp
 	// chan, thus the main process exits.
-	stop := func() {
+	go func() {
+		<-leaderStopped
 		c, cancel := context.WithCancel(context.Background())
 		defer cancel()
 		server.Stop(c)
-	}
-
-	le, err := leaderelection.New("kyverno", config.KyvernoNamespace, kubeClientLeaderElection, run, stop, log.Log.WithName("kyverno/LeaderElection"))
-	if err != nil {
-		setupLog.Error(err, "failed to elect a leader")
-		os.Exit(1)
-	}
+	}()
 
 	// init events handlers
 	// start Kyverno controllers
-	go le.Run(ctx)
-
 	go reportReqGen.Run(2, stopCh)
 	go configData.Run(stopCh)
 	go eventGenerator.Run(3, stopCh)
@@ -479,12 +612,9 @@ func main() {
 	go pCacheController.Run(1, stopCh)
 	go auditHandler.Run(10, stopCh)
 	if !debug {
-		go webhookMonitor.Run(webhookCfg, certRenewer, eventGenerator, stopCh)
+		go webhookMonitor.Run(webhookCfg, certRenewer, eventGenerator, le, stopCh)
 	}
 
-	go backwardcompatibility.AddLabels(pclient, pInformer.Kyverno().V1().GenerateRequests())
-	go backwardcompatibility.AddCloneLabel(client, pInformer.Kyverno().V1().ClusterPolicies())
-
 	pInformer.Start(stopCh)
 	kubeInformer.Start(stopCh)
 	kubedynamicInformer.Start(stopCh)
@@ -492,7 +622,11 @@ func main() {
 	// verifies if the admission control is enabled and active
 	server.RunAsync(stopCh)
 
-	<-stopCh
+	select {
+	case <-stopCh:
+	case <-leaderStopped:
+		setupLog.Info("stopped leading, shutting down to rejoin the leader election")
+	}
 
 	// resource cleanup
 	// remove webhook configurations
@@ -49,11 +49,52 @@ type Spec struct {
 	// +optional
 	ValidationFailureAction string `json:"validationFailureAction,omitempty" yaml:"validationFailureAction,omitempty"`
 
+	// ValidationFailureActionOverrides overrides the ValidationFailureAction for resources in the
+	// selected namespaces. The first override that matches the namespace of a resource is used.
+	// +optional
+	ValidationFailureActionOverrides []ValidationFailureActionOverride `json:"validationFailureActionOverrides,omitempty" yaml:"validationFailureActionOverrides,omitempty"`
+
 	// Background controls if rules are applied to existing resources during a background scan.
 	// Optional. Default value is "true". The value must be set to "false" if the policy rule
 	// uses variables that are only available in the admission review request (e.g. user name).
 	// +optional
 	Background *bool `json:"background,omitempty" yaml:"background,omitempty"`
+
+	// EmitWarning returns the messages of failed audit validation rules and of applied
+	// mutation rules as warnings in the admission response. Optional. The default value is "false".
+	// +optional
+	EmitWarning bool `json:"emitWarning,omitempty" yaml:"emitWarning,omitempty"`
+
+	// FailurePolicy defines how an error or a timeout of the admission webhook is handled for the
+	// rules of this policy, either Ignore or Fail. Optional. The default value is the defaultFailurePolicy
+	// of the Kyverno configuration.
+	// +kubebuilder:validation:Enum=Ignore;Fail
+	// +optional
+	FailurePolicy *string `json:"failurePolicy,omitempty" yaml:"failurePolicy,omitempty"`
+
+	// WebhookTimeoutSeconds is the maximum time in seconds allowed to apply the rules of this policy
+	// to an admission request, between 1 and 30. Optional. The default value is the --webhooktimeout flag of Kyverno.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=30
+	// +optional
+	WebhookTimeoutSeconds *int32 `json:"webhookTimeoutSeconds,omitempty" yaml:"webhookTimeoutSeconds,omitempty"`
+}
+
+// ValidationFailureActionOverride sets the ValidationFailureAction for a set of namespaces.
+type ValidationFailureActionOverride struct {
+
+	// Action is the ValidationFailureAction (enforce or audit) applied in the selected namespaces.
+	// +kubebuilder:validation:Enum=audit;enforce
+	Action string `json:"action,omitempty" yaml:"action,omitempty"`
+
+	// Namespaces is a list of namespaces names. Each name supports wildcard characters
+	// "*" (matches zero or many characters) and "?" (matches one character).
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+
+	// NamespaceSelector is a label selector for the namespaces.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty" yaml:"namespaceSelector,omitempty"`
 }
 
 // Rule defines a validation, mutation, or generation control for matching resources.
@@ -189,7 +230,7 @@ type Condition struct {
 }
 
 // ConditionOperator is the operation performed on condition key and value.
-// +kubebuilder:validation:Enum=Equals;NotEquals;In;NotIn
+// +kubebuilder:validation:Enum=Equal;Equals;NotEqual;NotEquals;In;AnyIn;AllIn;NotIn;AnyNotIn;AllNotIn;GreaterThanOrEquals;GreaterThan;LessThanOrEquals;LessThan;DurationGreaterThanOrEquals;DurationGreaterThan;DurationLessThanOrEquals;DurationLessThan
 type ConditionOperator string
 
 const (
@@ -207,13 +248,21 @@ const (
 	In ConditionOperator = "In"
 	// NotIn evaluates if the key is not contained in the set of values.
 	NotIn ConditionOperator = "NotIn"
-	// GreaterThanOrEquals evaluates if the key (numeric) is greater than or equal to the value (numeric).
+	// AnyIn evaluates if at least one of the values in the key (scalar or list) is contained in the set of values.
+	AnyIn ConditionOperator = "AnyIn"
+	// AllIn evaluates if all of the values in the key (scalar or list) are contained in the set of values.
+	AllIn ConditionOperator = "AllIn"
+	// AnyNotIn evaluates if at least one of the values in the key (scalar or list) is not contained in the set of values.
+	AnyNotIn ConditionOperator = "AnyNotIn"
+	// AllNotIn evaluates if none of the values in the key (scalar or list) are contained in the set of values.
+	AllNotIn ConditionOperator = "AllNotIn"
+	// GreaterThanOrEquals evaluates if the key (numeric, duration or quantity) is greater than or equal to the value (numeric, duration or quantity).
 	GreaterThanOrEquals ConditionOperator = "GreaterThanOrEquals"
-	// GreaterThan evaluates if the key (numeric) is greater than the value (numeric).
+	// GreaterThan evaluates if the key (numeric, duration or quantity) is greater than the value (numeric, duration or quantity).
 	GreaterThan ConditionOperator = "GreaterThan"
-	// LessThanOrEquals evaluates if the key (numeric) is less than or equal to the value (numeric).
+	// LessThanOrEquals evaluates if the key (numeric, duration or quantity) is less than or equal to the value (numeric, duration or quantity).
 	LessThanOrEquals ConditionOperator = "LessThanOrEquals"
-	// LessThan evaluates if the key (numeric) is less than the value (numeric).
+	// LessThan evaluates if the key (numeric, duration or quantity) is less than the value (numeric, duration or quantity).
 	LessThan ConditionOperator = "LessThan"
 	// DurationGreaterThanOrEquals evaluates if the key (duration) is greater than or equal to the value (duration)
 	DurationGreaterThanOrEquals ConditionOperator = "DurationGreaterThanOrEquals"
@@ -225,6 +274,28 @@ const (
 	DurationLessThan ConditionOperator = "DurationLessThan"
 )
 
+// ConditionOperators stores all the valid ConditionOperator types as key-value pairs.
+var ConditionOperators = map[string]ConditionOperator{
+	"Equal":                       Equal,
+	"Equals":                      Equals,
+	"NotEqual":                    NotEqual,
+	"NotEquals":                   NotEquals,
+	"In":                          In,
+	"AnyIn":                       AnyIn,
+	"AllIn":                       AllIn,
+	"NotIn":                       NotIn,
+	"AnyNotIn":                    AnyNotIn,
+	"AllNotIn":                    AllNotIn,
+	"GreaterThanOrEquals":         GreaterThanOrEquals,
+	"GreaterThan":                 GreaterThan,
+	"LessThanOrEquals":            LessThanOrEquals,
+	"LessThan":                    LessThan,
+	"DurationGreaterThanOrEquals": DurationGreaterThanOrEquals,
+	"DurationGreaterThan":         DurationGreaterThan,
+	"DurationLessThanOrEquals":    DurationLessThanOrEquals,
+	"DurationLessThan":            DurationLessThan,
+}
+
 // MatchResources is used to specify resource and admission review request data for
 // which a policy rule is applicable.
 type MatchResources struct {
@@ -295,7 +366,8 @@ type UserInfo struct {
 
 // ResourceDescription contains criteria used to match resources.
 type ResourceDescription struct {
-	// Kinds is a list of resource kinds.
+	// Kinds is a list of resource kinds. A subresource is selected with Kind/subresource,
+	// for example Pod/exec or Deployment/scale.
 	// +optional
 	Kinds []string `json:"kinds,omitempty" yaml:"kinds,omitempty"`
 
@@ -430,6 +502,11 @@ type ImageVerification struct {
 
 	// Key is the PEM encoded public key that the image is signed with.
 	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+
+	// MutateDigest controls if the image tag is replaced with the digest of the verified image.
+	// Optional. The default value is "true".
+	// +optional
+	MutateDigest *bool `json:"mutateDigest,omitempty" yaml:"mutateDigest,omitempty"`
 }
 
 // Generation defines how new resources should be created and managed.
@@ -472,9 +549,13 @@ type CloneFrom struct {
 }
 
 // PolicyStatus mostly contains runtime information related to policy execution.
-// Deprecated. Policy metrics are now available via the "/metrics" endpoint.
+// The execution statistics are deprecated. Policy metrics are now available via the "/metrics" endpoint.
 // See: https://kyverno.io/docs/monitoring-kyverno-with-prometheus-metrics/
 type PolicyStatus struct {
+	// Autogen contains the rules auto-generated for the pod controllers.
+	// +optional
+	Autogen AutogenStatus `json:"autogen,omitempty" yaml:"autogen,omitempty"`
+
 	// AvgExecutionTime is the average time taken to process the policy rules on a resource.
 	// +optional
 	AvgExecutionTime string `json:"averageExecutionTime,omitempty" yaml:"averageExecutionTime,omitempty"`
@@ -508,6 +589,16 @@ type PolicyStatus struct {
 	Rules []RuleStats `json:"ruleStatus,omitempty" yaml:"ruleStatus,omitempty"`
 }
 
+// AutogenStatus contains the rules auto-generated for the pod controllers.
+type AutogenStatus struct {
+	// Rules is the list of rules generated from the rules matching Pods, they apply the rules
+	// to the pod templates of the pod controllers. The rules are computed by Kyverno and read-only.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Rules []Rule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
 // RuleStats provides statistics for an individual rule within a policy.
 // Deprecated. Policy metrics are now available via the "/metrics" endpoint.
 // See: https://kyverno.io/docs/monitoring-kyverno-with-prometheus-metrics/
@@ -38,6 +38,17 @@ func (p *ClusterPolicy) HasMutate() bool {
 	return false
 }
 
+//HasValidate checks for validate rule types
+func (p *ClusterPolicy) HasValidate() bool {
+	for _, rule := range p.Spec.Rules {
+		if rule.HasValidate() {
+			return true
+		}
+	}
+
+	return false
+}
+
 //HasVerifyImages checks for image verification rule types
 func (p *ClusterPolicy) HasVerifyImages() bool {
 	for _, rule := range p.Spec.Rules {
@@ -58,6 +69,15 @@ func (p *ClusterPolicy) BackgroundProcessingEnabled() bool {
 	return *p.Spec.Background
 }
 
+// MutateDigestEnabled checks if the image tag is replaced with the digest of the verified image
+func (iv *ImageVerification) MutateDigestEnabled() bool {
+	if iv.MutateDigest == nil {
+		return true
+	}
+
+	return *iv.MutateDigest
+}
+
 // HasMutate checks for mutate rule
 func (r Rule) HasMutate() bool {
 	return !reflect.DeepEqual(r.Mutation, Mutation{})
@@ -201,4 +221,8 @@ type ViolatedRule struct {
 
 	// +optional
 	Check string `json:"check" yaml:"check"`
+
+	// Properties provides additional information on the violation, such as the paths at which the patterns failed.
+	// +optional
+	Properties map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
 }
@@ -72,6 +72,29 @@ func (in *AnyAllConditions) DeepCopy() *AnyAllConditions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutogenStatus) DeepCopyInto(out *AutogenStatus) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]Rule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutogenStatus.
+func (in *AutogenStatus) DeepCopy() *AutogenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutogenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloneFrom) DeepCopyInto(out *CloneFrom) {
 	*out = *in
@@ -359,6 +382,11 @@ func (in *Generation) DeepCopy() *Generation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageVerification) DeepCopyInto(out *ImageVerification) {
 	*out = *in
+	if in.MutateDigest != nil {
+		in, out := &in.MutateDigest, &out.MutateDigest
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -464,6 +492,7 @@ func (in *PolicyList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyStatus) DeepCopyInto(out *PolicyStatus) {
 	*out = *in
+	in.Autogen.DeepCopyInto(&out.Autogen)
 	if in.Rules != nil {
 		in, out := &in.Rules, &out.Rules
 		*out = make([]RuleStats, len(*in))
@@ -609,11 +638,28 @@ func (in *Spec) DeepCopyInto(out *Spec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ValidationFailureActionOverrides != nil {
+		in, out := &in.ValidationFailureActionOverrides, &out.ValidationFailureActionOverrides
+		*out = make([]ValidationFailureActionOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Background != nil {
 		in, out := &in.Background, &out.Background
 		*out = new(bool)
 		**out = **in
 	}
+	if in.FailurePolicy != nil {
+		in, out := &in.FailurePolicy, &out.FailurePolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.WebhookTimeoutSeconds != nil {
+		in, out := &in.WebhookTimeoutSeconds, &out.WebhookTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -668,9 +714,42 @@ func (in *Validation) DeepCopy() *Validation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationFailureActionOverride) DeepCopyInto(out *ValidationFailureActionOverride) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationFailureActionOverride.
+func (in *ValidationFailureActionOverride) DeepCopy() *ValidationFailureActionOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationFailureActionOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ViolatedRule) DeepCopyInto(out *ViolatedRule) {
 	*out = *in
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
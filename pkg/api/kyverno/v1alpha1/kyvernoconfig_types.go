@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KyvernoConfig is the Schema for the KyvernoConfigs API. Kyverno uses the KyvernoConfig
+// named kyverno, and falls back to the init ConfigMap when it does not exist.
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=kyvernoconfigs,scope="Cluster",shortName=kcfg
+// +kubebuilder:printcolumn:name="Applied",type="boolean",JSONPath=".status.applied"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type KyvernoConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the Kyverno configuration.
+	Spec KyvernoConfigSpec `json:"spec"`
+
+	// Status contains the result of applying the configuration.
+	// +optional
+	Status KyvernoConfigStatus `json:"status,omitempty"`
+}
+
+// KyvernoConfigSpec defines the Kyverno configuration.
+type KyvernoConfigSpec struct {
+	// ResourceFilters are the resources that are not processed by the admission webhooks
+	// and by the background scan.
+	// +optional
+	ResourceFilters []ResourceFilter `json:"resourceFilters,omitempty"`
+
+	// ExcludeGroupRoles are the groups whose admission requests are not processed. The groups
+	// system:serviceaccounts:kube-system, system:nodes and system:kube-scheduler are always excluded.
+	// +optional
+	ExcludeGroupRoles []string `json:"excludeGroupRoles,omitempty"`
+
+	// ExcludeUsernames are the usernames whose admission requests are not processed.
+	// +optional
+	ExcludeUsernames []string `json:"excludeUsernames,omitempty"`
+
+	// Webhooks configures the resource webhooks.
+	// +kubebuilder:validation:MaxItems=1
+	// +optional
+	Webhooks []WebhookConfiguration `json:"webhooks,omitempty"`
+
+	// DefaultFailurePolicy is the failure policy of the resource webhooks, either Ignore or Fail.
+	// The default value is "Ignore".
+	// +kubebuilder:validation:Enum=Ignore;Fail
+	// +optional
+	DefaultFailurePolicy *string `json:"defaultFailurePolicy,omitempty"`
+
+	// GenerateSuccessEvents controls whether events are generated for successful policy applications.
+	// The default value is "false".
+	// +optional
+	GenerateSuccessEvents *bool `json:"generateSuccessEvents,omitempty"`
+
+	// MaxAdmissionWarnings is the maximum number of warnings returned in an admission response.
+	// The default value is 10.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxAdmissionWarnings *int `json:"maxAdmissionWarnings,omitempty"`
+
+	// MaxAdmissionWarningSize is the maximum length of an admission warning, longer warnings are truncated.
+	// The default value is 256.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxAdmissionWarningSize *int `json:"maxAdmissionWarningSize,omitempty"`
+
+	// BackgroundScanInterval is the interval between background scans, e.g. 30m or 1h.
+	// It overrides the --background-scan flag.
+	// +optional
+	BackgroundScanInterval *metav1.Duration `json:"backgroundScanInterval,omitempty"`
+
+	// ReportLimits configures the limits of the policy reports.
+	// +optional
+	ReportLimits *ReportLimits `json:"reportLimits,omitempty"`
+
+	// AutogenControllers are the pod controllers rules matching Pods are auto-generated for, in policies
+	// that do not set the pod-policies.kyverno.io/autogen-controllers annotation. The default value is
+	// DaemonSet, Deployment, Job, StatefulSet and CronJob.
+	// +optional
+	AutogenControllers []AutogenController `json:"autogenControllers,omitempty"`
+}
+
+// ResourceFilter selects resources by kind, namespace and name. Wildcards are supported.
+type ResourceFilter struct {
+	// Kind is the kind of the resources.
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the resources. The default value is "*".
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the resources. The default value is "*".
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// WebhookConfiguration configures the resource webhooks.
+type WebhookConfiguration struct {
+	// NamespaceSelector selects the namespaces of the resources sent to the webhooks.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// ReportLimits configures the limits of the policy reports.
+type ReportLimits struct {
+	// MaxResultsPerReport is the maximum number of results in a policy report.
+	// Reports holding more results are split into shards, the additional shards are
+	// named after the report with an index suffix. The default value is 1000.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxResultsPerReport *int `json:"maxResultsPerReport,omitempty"`
+}
+
+// AutogenController is a workload kind that creates Pods from a pod template.
+type AutogenController struct {
+	// Kind is the kind of the workload.
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+
+	// TemplatePath is the dot-separated path of the pod template in the workload.
+	// The default value is "spec.template".
+	// +optional
+	TemplatePath string `json:"templatePath,omitempty"`
+}
+
+// KyvernoConfigStatus contains the result of applying the configuration.
+type KyvernoConfigStatus struct {
+	// Applied is true when the configuration is used by Kyverno.
+	Applied bool `json:"applied"`
+
+	// ObservedGeneration is the generation of the configuration that was last processed.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastAppliedTime is the time the configuration was last applied.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// Errors describes the settings that could not be applied, the other settings are applied.
+	// +optional
+	Errors []string `json:"errors,omitempty"`
+}
+
+// KyvernoConfigList contains a list of KyvernoConfig
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KyvernoConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KyvernoConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KyvernoConfig{}, &KyvernoConfigList{})
+}
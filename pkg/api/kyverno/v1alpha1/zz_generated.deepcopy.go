@@ -27,6 +27,22 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutogenController) DeepCopyInto(out *AutogenController) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutogenController.
+func (in *AutogenController) DeepCopy() *AutogenController {
+	if in == nil {
+		return nil
+	}
+	out := new(AutogenController)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterReportChangeRequest) DeepCopyInto(out *ClusterReportChangeRequest) {
 	*out = *in
@@ -108,6 +124,165 @@ func (in *ClusterReportChangeRequestList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KyvernoConfig) DeepCopyInto(out *KyvernoConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KyvernoConfig.
+func (in *KyvernoConfig) DeepCopy() *KyvernoConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KyvernoConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KyvernoConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KyvernoConfigList) DeepCopyInto(out *KyvernoConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KyvernoConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KyvernoConfigList.
+func (in *KyvernoConfigList) DeepCopy() *KyvernoConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(KyvernoConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KyvernoConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KyvernoConfigSpec) DeepCopyInto(out *KyvernoConfigSpec) {
+	*out = *in
+	if in.ResourceFilters != nil {
+		in, out := &in.ResourceFilters, &out.ResourceFilters
+		*out = make([]ResourceFilter, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeGroupRoles != nil {
+		in, out := &in.ExcludeGroupRoles, &out.ExcludeGroupRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeUsernames != nil {
+		in, out := &in.ExcludeUsernames, &out.ExcludeUsernames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]WebhookConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultFailurePolicy != nil {
+		in, out := &in.DefaultFailurePolicy, &out.DefaultFailurePolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.GenerateSuccessEvents != nil {
+		in, out := &in.GenerateSuccessEvents, &out.GenerateSuccessEvents
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxAdmissionWarnings != nil {
+		in, out := &in.MaxAdmissionWarnings, &out.MaxAdmissionWarnings
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxAdmissionWarningSize != nil {
+		in, out := &in.MaxAdmissionWarningSize, &out.MaxAdmissionWarningSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.BackgroundScanInterval != nil {
+		in, out := &in.BackgroundScanInterval, &out.BackgroundScanInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ReportLimits != nil {
+		in, out := &in.ReportLimits, &out.ReportLimits
+		*out = new(ReportLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutogenControllers != nil {
+		in, out := &in.AutogenControllers, &out.AutogenControllers
+		*out = make([]AutogenController, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KyvernoConfigSpec.
+func (in *KyvernoConfigSpec) DeepCopy() *KyvernoConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KyvernoConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KyvernoConfigStatus) DeepCopyInto(out *KyvernoConfigStatus) {
+	*out = *in
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KyvernoConfigStatus.
+func (in *KyvernoConfigStatus) DeepCopy() *KyvernoConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KyvernoConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReportChangeRequest) DeepCopyInto(out *ReportChangeRequest) {
 	*out = *in
@@ -188,3 +363,61 @@ func (in *ReportChangeRequestList) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportLimits) DeepCopyInto(out *ReportLimits) {
+	*out = *in
+	if in.MaxResultsPerReport != nil {
+		in, out := &in.MaxResultsPerReport, &out.MaxResultsPerReport
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportLimits.
+func (in *ReportLimits) DeepCopy() *ReportLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceFilter) DeepCopyInto(out *ResourceFilter) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFilter.
+func (in *ResourceFilter) DeepCopy() *ResourceFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookConfiguration) DeepCopyInto(out *WebhookConfiguration) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookConfiguration.
+func (in *WebhookConfiguration) DeepCopy() *WebhookConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -111,6 +111,23 @@ type PolicyReportResult struct {
 	// Data provides additional information for the policy rule
 	Data map[string]string `json:"data,omitempty"`
 
+	// Properties provides structured information on the result, such as the paths at which the validation patterns failed
+	// +optional
+	Properties map[string]string `json:"properties,omitempty"`
+
+	// Source is the name of the tool that produced the result
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// Timestamp is the time at which the status, the message or the rule of the result last changed
+	// +optional
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+
+	// FirstSeenTimestamp is the time at which the result was first reported for the resource,
+	// it is reset when the status of the result changes or when the resource is recreated
+	// +optional
+	FirstSeenTimestamp *metav1.Time `json:"firstSeenTimestamp,omitempty"`
+
 	// Category indicates policy category
 	// +optional
 	Category string `json:"category,omitempty"`
@@ -214,6 +214,21 @@ func (in *PolicyReportResult) DeepCopyInto(out *PolicyReportResult) {
 			(*out)[key] = val
 		}
 	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.FirstSeenTimestamp != nil {
+		in, out := &in.FirstSeenTimestamp, &out.FirstSeenTimestamp
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -0,0 +1,269 @@
+package auditlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	v1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+)
+
+// Supported sinks
+const (
+	StdoutSink = "stdout"
+	FileSink   = "file"
+	HTTPSink   = "http"
+)
+
+// Record describes an admission decision made by Kyverno
+type Record struct {
+	Timestamp   time.Time                 `json:"timestamp"`
+	RequestUID  string                    `json:"requestUID"`
+	Operation   string                    `json:"operation"`
+	Webhook     string                    `json:"webhook"`
+	UserInfo    authenticationv1.UserInfo `json:"userInfo"`
+	Resource    Resource                  `json:"resource"`
+	Allowed     bool                      `json:"allowed"`
+	Message     string                    `json:"message,omitempty"`
+	Policies    []PolicyResult            `json:"policies"`
+	Patches     json.RawMessage           `json:"patches,omitempty"`
+	LatencyMsec int64                     `json:"latencyMsec"`
+}
+
+// Resource identifies the resource of the admission request
+type Resource struct {
+	Group       string `json:"group"`
+	Version     string `json:"version"`
+	Kind        string `json:"kind"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name"`
+	SubResource string `json:"subresource,omitempty"`
+}
+
+// PolicyResult contains the results of the rules of a policy
+type PolicyResult struct {
+	Name                    string       `json:"name"`
+	Namespace               string       `json:"namespace,omitempty"`
+	ValidationFailureAction string       `json:"validationFailureAction,omitempty"`
+	Rules                   []RuleResult `json:"rules"`
+}
+
+// RuleResult is the result of a rule applied to the resource
+type RuleResult struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// NewRecord builds the record of an admission decision from the engine responses of the evaluated policies,
+// the patches are taken from the admission response.
+func NewRecord(webhook string, request *v1beta1.AdmissionRequest, admissionResponse *v1beta1.AdmissionResponse, engineResponses []*response.EngineResponse, startTime time.Time) Record {
+	record := Record{
+		Timestamp:   startTime.UTC(),
+		RequestUID:  string(request.UID),
+		Operation:   string(request.Operation),
+		Webhook:     webhook,
+		UserInfo:    request.UserInfo,
+		Allowed:     admissionResponse.Allowed,
+		Policies:    []PolicyResult{},
+		LatencyMsec: time.Since(startTime).Milliseconds(),
+		Resource: Resource{
+			Group:       request.Kind.Group,
+			Version:     request.Kind.Version,
+			Kind:        request.Kind.Kind,
+			Namespace:   request.Namespace,
+			Name:        request.Name,
+			SubResource: request.SubResource,
+		},
+	}
+
+	if admissionResponse.Result != nil {
+		record.Message = admissionResponse.Result.Message
+	}
+
+	if len(admissionResponse.Patch) != 0 && json.Valid(admissionResponse.Patch) {
+		record.Patches = json.RawMessage(admissionResponse.Patch)
+	}
+
+	for _, er := range engineResponses {
+		if er == nil || len(er.PolicyResponse.Rules) == 0 {
+			continue
+		}
+
+		policy := PolicyResult{
+			Name:                    er.PolicyResponse.Policy.Name,
+			Namespace:               er.PolicyResponse.Policy.Namespace,
+			ValidationFailureAction: er.PolicyResponse.ValidationFailureAction,
+		}
+
+		for _, rule := range er.PolicyResponse.Rules {
+			status := "pass"
+			if !rule.Success {
+				status = "fail"
+			}
+
+			policy.Rules = append(policy.Rules, RuleResult{
+				Name:    rule.Name,
+				Type:    rule.Type,
+				Status:  status,
+				Message: rule.Message,
+			})
+		}
+
+		record.Policies = append(record.Policies, policy)
+	}
+
+	return record
+}
+
+// Interface records admission decisions
+type Interface interface {
+	// Add queues the record, it never blocks. Records are dropped when the queue is full.
+	Add(record Record)
+}
+
+// Config configures the audit log
+type Config struct {
+	// Sink is one of stdout, file or http
+	Sink string
+
+	// Path of the log file for the file sink
+	Path string
+
+	// MaxSize is the size in bytes after which the log file is rotated
+	MaxSize int64
+
+	// MaxBackups is the number of rotated log files that are kept
+	MaxBackups int
+
+	// URL the records are posted to by the http sink
+	URL string
+
+	// BatchSize is the maximum number of records written at once
+	BatchSize int
+
+	// FlushInterval is the maximum time a record waits in the queue before it is written
+	FlushInterval time.Duration
+
+	// QueueSize is the maximum number of records waiting to be written
+	QueueSize int
+}
+
+// AuditLog writes the admission decisions to the configured sink.
+// Records are queued and written in batches by a single worker, a slow or failing sink
+// fills the queue and the records added while the queue is full are dropped, so that
+// admission requests are never delayed by the audit log.
+type AuditLog struct {
+	queue         chan Record
+	writer        writer
+	batchSize     int
+	flushInterval time.Duration
+	dropped       uint64
+	log           logr.Logger
+}
+
+// New creates an audit log writing to the sink in config
+func New(config Config, log logr.Logger) (*AuditLog, error) {
+	var w writer
+	switch config.Sink {
+	case StdoutSink:
+		w = newStreamWriter(stdout)
+	case FileSink:
+		fw, err := newFileWriter(config.Path, config.MaxSize, config.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		w = fw
+	case HTTPSink:
+		hw, err := newHTTPWriter(config.URL, log)
+		if err != nil {
+			return nil, err
+		}
+		w = hw
+	default:
+		return nil, fmt.Errorf("unsupported audit log sink %q, must be one of %s, %s or %s", config.Sink, StdoutSink, FileSink, HTTPSink)
+	}
+
+	if config.BatchSize <= 0 {
+		return nil, fmt.Errorf("audit log batch size must be a positive integer")
+	}
+
+	if config.FlushInterval <= 0 {
+		return nil, fmt.Errorf("audit log flush interval must be a positive duration")
+	}
+
+	if config.QueueSize < config.BatchSize {
+		return nil, fmt.Errorf("audit log queue size must be greater than or equal to the batch size")
+	}
+
+	return &AuditLog{
+		queue:         make(chan Record, config.QueueSize),
+		writer:        w,
+		batchSize:     config.BatchSize,
+		flushInterval: config.FlushInterval,
+		log:           log,
+	}, nil
+}
+
+// Add queues the record
+func (a *AuditLog) Add(record Record) {
+	select {
+	case a.queue <- record:
+	default:
+		dropped := atomic.AddUint64(&a.dropped, 1)
+		if dropped == 1 || dropped%1000 == 0 {
+			a.log.Info("audit log queue is full, dropping records", "dropped", dropped)
+		}
+	}
+}
+
+// Run writes the queued records until stopCh is closed, the remaining records are written before returning
+func (a *AuditLog) Run(stopCh <-chan struct{}) {
+	a.log.Info("start")
+	defer a.log.Info("shutting down")
+	defer a.writer.close()
+
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Record, 0, a.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := a.writer.write(batch); err != nil {
+			a.log.Error(err, "failed to write audit records", "count", len(batch))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case record := <-a.queue:
+			batch = append(batch, record)
+			if len(batch) >= a.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-stopCh:
+			for {
+				select {
+				case record := <-a.queue:
+					batch = append(batch, record)
+					if len(batch) >= a.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package auditlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"gotest.tools/assert"
+	v1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func newTestRequest() *v1beta1.AdmissionRequest {
+	return &v1beta1.AdmissionRequest{
+		UID:       "7b4c1f2e",
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		Namespace: "default",
+		Name:      "nginx",
+		Operation: v1beta1.Create,
+		UserInfo:  authenticationv1.UserInfo{Username: "alice", Groups: []string{"dev"}},
+	}
+}
+
+func Test_NewRecord(t *testing.T) {
+	engineResponses := []*response.EngineResponse{
+		{
+			PolicyResponse: response.PolicyResponse{
+				Policy:                  response.PolicySpec{Name: "require-labels"},
+				ValidationFailureAction: "enforce",
+				Rules: []response.RuleResponse{
+					{Name: "check-team", Type: "Validation", Message: "label team is required", Success: false},
+					{Name: "check-app", Type: "Validation", Success: true},
+				},
+			},
+		},
+		{
+			PolicyResponse: response.PolicyResponse{
+				Policy: response.PolicySpec{Name: "no-rules-applied"},
+			},
+		},
+	}
+
+	admissionResponse := &v1beta1.AdmissionResponse{
+		Allowed: false,
+		Result:  &metav1.Status{Message: "resource blocked"},
+	}
+
+	record := NewRecord("validate", newTestRequest(), admissionResponse, engineResponses, time.Now())
+	assert.Equal(t, record.RequestUID, "7b4c1f2e")
+	assert.Equal(t, record.Webhook, "validate")
+	assert.Equal(t, record.Operation, "CREATE")
+	assert.Equal(t, record.UserInfo.Username, "alice")
+	assert.Equal(t, record.Resource.Kind, "Pod")
+	assert.Equal(t, record.Resource.Name, "nginx")
+	assert.Assert(t, !record.Allowed)
+	assert.Equal(t, record.Message, "resource blocked")
+	assert.Equal(t, len(record.Policies), 1)
+	assert.DeepEqual(t, record.Policies[0], PolicyResult{
+		Name:                    "require-labels",
+		ValidationFailureAction: "enforce",
+		Rules: []RuleResult{
+			{Name: "check-team", Type: "Validation", Status: "fail", Message: "label team is required"},
+			{Name: "check-app", Type: "Validation", Status: "pass"},
+		},
+	})
+}
+
+func Test_NewRecord_Patches(t *testing.T) {
+	admissionResponse := &v1beta1.AdmissionResponse{
+		Allowed: true,
+		Patch:   []byte(`[{"op":"add","path":"/metadata/labels/team","value":"dev"}]`),
+	}
+
+	record := NewRecord("mutate", newTestRequest(), admissionResponse, nil, time.Now())
+	raw, err := json.Marshal(record)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(raw), `"patches":[{"op":"add","path":"/metadata/labels/team","value":"dev"}]`))
+	assert.Assert(t, strings.Contains(string(raw), `"policies":[]`))
+}
+
+func Test_StreamWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newStreamWriter(&buf)
+	assert.NilError(t, w.write([]Record{{RequestUID: "1"}, {RequestUID: "2"}}))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, len(lines), 2)
+
+	var record Record
+	assert.NilError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, record.RequestUID, "2")
+}
+
+func Test_FileWriter_Rotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auditlog")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "audit", "audit.log")
+	w, err := newFileWriter(path, 100, 2)
+	assert.NilError(t, err)
+	defer w.close()
+
+	for i := 0; i < 4; i++ {
+		assert.NilError(t, w.write([]Record{{RequestUID: strings.Repeat("x", 50)}}))
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		assert.NilError(t, err)
+		assert.Assert(t, info.Size() <= 300)
+	}
+
+	_, err = os.Stat(path + ".3")
+	assert.Assert(t, os.IsNotExist(err))
+}
+
+func Test_HTTPWriter_Retry(t *testing.T) {
+	var mux sync.Mutex
+	var requests int
+	var received []Record
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.Lock()
+		defer mux.Unlock()
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		var records []Record
+		if err := json.NewDecoder(r.Body).Decode(&records); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, records...)
+	}))
+	defer server.Close()
+
+	w, err := newHTTPWriter(server.URL, log.Log)
+	assert.NilError(t, err)
+	w.backoff = time.Millisecond
+
+	assert.NilError(t, w.write([]Record{{RequestUID: "1"}, {RequestUID: "2"}}))
+	assert.Equal(t, requests, 2)
+	assert.Equal(t, len(received), 2)
+}
+
+func Test_AuditLog_DropsWhenFull(t *testing.T) {
+	var buf bytes.Buffer
+	a := &AuditLog{
+		queue:         make(chan Record, 2),
+		writer:        newStreamWriter(&buf),
+		batchSize:     2,
+		flushInterval: time.Hour,
+		log:           log.Log,
+	}
+
+	for i := 0; i < 5; i++ {
+		a.Add(Record{})
+	}
+	assert.Equal(t, a.dropped, uint64(3))
+
+	stopCh := make(chan struct{})
+	close(stopCh)
+	a.Run(stopCh)
+	assert.Equal(t, strings.Count(buf.String(), "\n"), 2)
+}
+
+func Test_New_InvalidConfig(t *testing.T) {
+	_, err := New(Config{Sink: "syslog", BatchSize: 1, QueueSize: 1, FlushInterval: time.Second}, log.Log)
+	assert.ErrorContains(t, err, "unsupported audit log sink")
+
+	_, err = New(Config{Sink: HTTPSink, BatchSize: 1, QueueSize: 1, FlushInterval: time.Second}, log.Log)
+	assert.ErrorContains(t, err, "URL is required")
+
+	_, err = New(Config{Sink: StdoutSink, BatchSize: 10, QueueSize: 1, FlushInterval: time.Second}, log.Log)
+	assert.ErrorContains(t, err, "queue size")
+}
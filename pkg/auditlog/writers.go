@@ -0,0 +1,223 @@
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+var stdout io.Writer = os.Stdout
+
+// writer writes batches of records to a sink
+type writer interface {
+	write(records []Record) error
+	close()
+}
+
+// streamWriter writes the records as JSON lines
+type streamWriter struct {
+	out io.Writer
+}
+
+func newStreamWriter(out io.Writer) *streamWriter {
+	return &streamWriter{out: out}
+}
+
+func (w *streamWriter) write(records []Record) error {
+	buf := bufio.NewWriter(w.out)
+	if err := encodeLines(buf, records); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+func (w *streamWriter) close() {}
+
+func encodeLines(out io.Writer, records []Record) error {
+	encoder := json.NewEncoder(out)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileWriter writes the records as JSON lines to a file that is rotated once it reaches
+// maxSize bytes, the rotated files are named <path>.1 to <path>.<maxBackups>, the oldest
+// file being removed
+type fileWriter struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func newFileWriter(path string, maxSize int64, maxBackups int) (*fileWriter, error) {
+	if path == "" {
+		return nil, fmt.Errorf("audit log path is required for the file sink")
+	}
+
+	if maxBackups < 0 {
+		return nil, fmt.Errorf("audit log max backups must be a non-negative integer")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	w := &fileWriter{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *fileWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+func (w *fileWriter) write(records []Record) error {
+	var buf bytes.Buffer
+	if err := encodeLines(&buf, records); err != nil {
+		return err
+	}
+
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(buf.Len()) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := w.file.Write(buf.Bytes())
+	w.size += int64(n)
+	return err
+}
+
+func (w *fileWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+
+	if w.maxBackups == 0 {
+		if err := os.Remove(w.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return w.open()
+	}
+
+	for i := w.maxBackups - 1; i > 0; i-- {
+		if err := os.Rename(backupName(w.path, i), backupName(w.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := os.Rename(w.path, backupName(w.path, 1)); err != nil {
+		return err
+	}
+
+	return w.open()
+}
+
+func (w *fileWriter) close() {
+	w.file.Close()
+}
+
+func backupName(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
+
+// httpWriter posts the records as a JSON array, the requests that fail or are
+// throttled by the endpoint are retried with an exponential backoff
+type httpWriter struct {
+	url        string
+	client     *http.Client
+	maxRetries int
+	backoff    time.Duration
+	log        logr.Logger
+}
+
+func newHTTPWriter(endpoint string, log logr.Logger) (*httpWriter, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("audit log URL is required for the http sink")
+	}
+
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid audit log URL %s: %v", endpoint, err)
+	}
+
+	return &httpWriter{
+		url:        endpoint,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		maxRetries: 5,
+		backoff:    time.Second,
+		log:        log,
+	}, nil
+}
+
+func (w *httpWriter) write(records []Record) error {
+	body, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	backoff := w.backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(body)
+		if err == nil {
+			return nil
+		}
+
+		if !retry || attempt == w.maxRetries {
+			return err
+		}
+
+		w.log.V(4).Info("retrying to post audit records", "url", w.url, "backoff", backoff.String(), "reason", err.Error())
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (w *httpWriter) post(body []byte) (retry bool, err error) {
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return true, err
+	}
+
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("audit log endpoint %s returned %s", w.url, resp.Status)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+func (w *httpWriter) close() {}
@@ -32,6 +32,10 @@ func (c *FakeKyvernoV1alpha1) ClusterReportChangeRequests() v1alpha1.ClusterRepo
 	return &FakeClusterReportChangeRequests{c}
 }
 
+func (c *FakeKyvernoV1alpha1) KyvernoConfigs() v1alpha1.KyvernoConfigInterface {
+	return &FakeKyvernoConfigs{c}
+}
+
 func (c *FakeKyvernoV1alpha1) ReportChangeRequests(namespace string) v1alpha1.ReportChangeRequestInterface {
 	return &FakeReportChangeRequests{c, namespace}
 }
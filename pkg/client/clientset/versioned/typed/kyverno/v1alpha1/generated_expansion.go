@@ -20,4 +20,6 @@ package v1alpha1
 
 type ClusterReportChangeRequestExpansion interface{}
 
+type KyvernoConfigExpansion interface{}
+
 type ReportChangeRequestExpansion interface{}
@@ -27,6 +27,7 @@ import (
 type KyvernoV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	ClusterReportChangeRequestsGetter
+	KyvernoConfigsGetter
 	ReportChangeRequestsGetter
 }
 
@@ -39,6 +40,10 @@ func (c *KyvernoV1alpha1Client) ClusterReportChangeRequests() ClusterReportChang
 	return newClusterReportChangeRequests(c)
 }
 
+func (c *KyvernoV1alpha1Client) KyvernoConfigs() KyvernoConfigInterface {
+	return newKyvernoConfigs(c)
+}
+
 func (c *KyvernoV1alpha1Client) ReportChangeRequests(namespace string) ReportChangeRequestInterface {
 	return newReportChangeRequests(c, namespace)
 }
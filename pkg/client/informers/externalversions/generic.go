@@ -65,6 +65,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		// Group=kyverno.io, Version=v1alpha1
 	case v1alpha1.SchemeGroupVersion.WithResource("clusterreportchangerequests"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kyverno().V1alpha1().ClusterReportChangeRequests().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("kyvernoconfigs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kyverno().V1alpha1().KyvernoConfigs().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("reportchangerequests"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kyverno().V1alpha1().ReportChangeRequests().Informer()}, nil
 
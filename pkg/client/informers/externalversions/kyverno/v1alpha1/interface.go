@@ -26,6 +26,8 @@ import (
 type Interface interface {
 	// ClusterReportChangeRequests returns a ClusterReportChangeRequestInformer.
 	ClusterReportChangeRequests() ClusterReportChangeRequestInformer
+	// KyvernoConfigs returns a KyvernoConfigInformer.
+	KyvernoConfigs() KyvernoConfigInformer
 	// ReportChangeRequests returns a ReportChangeRequestInformer.
 	ReportChangeRequests() ReportChangeRequestInformer
 }
@@ -46,6 +48,11 @@ func (v *version) ClusterReportChangeRequests() ClusterReportChangeRequestInform
 	return &clusterReportChangeRequestInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// KyvernoConfigs returns a KyvernoConfigInformer.
+func (v *version) KyvernoConfigs() KyvernoConfigInformer {
+	return &kyvernoConfigInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ReportChangeRequests returns a ReportChangeRequestInformer.
 func (v *version) ReportChangeRequests() ReportChangeRequestInformer {
 	return &reportChangeRequestInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -22,6 +22,10 @@ package v1alpha1
 // ClusterReportChangeRequestLister.
 type ClusterReportChangeRequestListerExpansion interface{}
 
+// KyvernoConfigListerExpansion allows custom methods to be added to
+// KyvernoConfigLister.
+type KyvernoConfigListerExpansion interface{}
+
 // ReportChangeRequestListerExpansion allows custom methods to be added to
 // ReportChangeRequestLister.
 type ReportChangeRequestListerExpansion interface{}
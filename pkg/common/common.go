@@ -11,6 +11,7 @@ import (
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	dclient "github.com/kyverno/kyverno/pkg/dclient"
 	enginutils "github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/utils"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -72,16 +73,20 @@ func GetNamespaceLabels(namespaceObj *v1.Namespace, logger logr.Logger) map[stri
 	return namespaceUnstructured.GetLabels()
 }
 
-// GetKindFromGVK - get kind and APIVersion from GVK
+// GetKindFromGVK - get kind and APIVersion from GVK, the kind of a subresource is returned as Kind/subresource
 func GetKindFromGVK(str string) (apiVersion string, kind string) {
-	if strings.Count(str, "/") == 0 {
-		return "", str
+	group, version, kind, subresource := utils.ParseKindSelector(str)
+	if group != "" {
+		apiVersion = group + "/" + version
+	} else {
+		apiVersion = version
 	}
-	splitString := strings.Split(str, "/")
-	if strings.Count(str, "/") == 1 {
-		return splitString[0], splitString[1]
+
+	if subresource != "" {
+		kind = kind + "/" + subresource
 	}
-	return splitString[0] + "/" + splitString[1], splitString[2]
+
+	return apiVersion, kind
 }
 
 func VariableToJSON(key, value string) []byte {
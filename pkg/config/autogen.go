@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultPodControllers is the comma-separated list of the pod controllers Pod policies are auto-generated for
+	DefaultPodControllers = "DaemonSet,Deployment,Job,StatefulSet,CronJob"
+
+	// DefaultPodTemplatePath is the path of the pod template in the spec of most pod controllers
+	DefaultPodTemplatePath = "spec.template"
+
+	// CronJobPodTemplatePath is the path of the pod template in a CronJob
+	CronJobPodTemplatePath = "spec.jobTemplate.spec.template"
+)
+
+// PodController is a workload kind that creates Pods from a pod template,
+// Pod policies are auto-generated to apply to the pod template of the workload
+type PodController struct {
+	// Kind is the kind of the workload
+	Kind string
+
+	// TemplatePath is the dot-separated path of the pod template in the workload
+	TemplatePath string
+}
+
+// String formats the pod controller the way it is parsed, the default template path is omitted
+func (pc PodController) String() string {
+	if pc.TemplatePath == "" || pc.TemplatePath == DefaultPodTemplatePath || pc.Kind == "CronJob" {
+		return pc.Kind
+	}
+
+	return pc.Kind + "=" + pc.TemplatePath
+}
+
+// ParsePodControllers parses a comma-separated list of pod controllers. Each entry is a kind, optionally
+// followed by "=" and the path of its pod template, e.g. "Deployment,Rollout,CloneSet=spec.template".
+// The template path defaults to spec.template, "all" stands for the default pod controllers and
+// "none" for no pod controller. The pod template path of CronJob is fixed.
+func ParsePodControllers(controllers string) ([]PodController, error) {
+	switch strings.TrimSpace(controllers) {
+	case "all":
+		controllers = DefaultPodControllers
+	case "none":
+		return nil, nil
+	}
+
+	var podControllers []PodController
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(controllers, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kind, path := entry, DefaultPodTemplatePath
+		if i := strings.Index(entry, "="); i >= 0 {
+			kind, path = strings.TrimSpace(entry[:i]), strings.Trim(strings.TrimSpace(entry[i+1:]), ".")
+		}
+
+		if kind == "" || path == "" || strings.ContainsAny(kind, "/ ") {
+			return nil, fmt.Errorf("invalid pod controller %q, expected <kind> or <kind>=<pod template path>", entry)
+		}
+
+		if kind == "CronJob" {
+			if path != DefaultPodTemplatePath && path != CronJobPodTemplatePath {
+				return nil, fmt.Errorf("invalid pod controller %q, the pod template path of CronJob cannot be changed", entry)
+			}
+			path = CronJobPodTemplatePath
+		}
+
+		if seen[kind] {
+			return nil, fmt.Errorf("duplicate pod controller %s", kind)
+		}
+		seen[kind] = true
+
+		podControllers = append(podControllers, PodController{Kind: kind, TemplatePath: path})
+	}
+
+	return podControllers, nil
+}
+
+// FormatPodControllers formats pod controllers as a comma-separated list, see ParsePodControllers
+func FormatPodControllers(podControllers []PodController) string {
+	if len(podControllers) == 0 {
+		return "none"
+	}
+
+	entries := make([]string, 0, len(podControllers))
+	for _, pc := range podControllers {
+		entries = append(entries, pc.String())
+	}
+
+	return strings.Join(entries, ",")
+}
+
+// PodControllerKinds returns the kinds of a comma-separated list of pod controllers, invalid entries are skipped
+func PodControllerKinds(controllers string) []string {
+	switch strings.TrimSpace(controllers) {
+	case "all":
+		controllers = DefaultPodControllers
+	case "none":
+		return nil
+	}
+
+	var kinds []string
+	for _, entry := range strings.Split(controllers, ",") {
+		if i := strings.Index(entry, "="); i >= 0 {
+			entry = entry[:i]
+		}
+
+		if entry = strings.TrimSpace(entry); entry != "" {
+			kinds = append(kinds, entry)
+		}
+	}
+
+	return kinds
+}
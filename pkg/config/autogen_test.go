@@ -0,0 +1,81 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_ParsePodControllers(t *testing.T) {
+	testCases := []struct {
+		controllers string
+		expected    []PodController
+		formatted   string
+		err         string
+	}{
+		{
+			controllers: "all",
+			expected: []PodController{
+				{Kind: "DaemonSet", TemplatePath: DefaultPodTemplatePath},
+				{Kind: "Deployment", TemplatePath: DefaultPodTemplatePath},
+				{Kind: "Job", TemplatePath: DefaultPodTemplatePath},
+				{Kind: "StatefulSet", TemplatePath: DefaultPodTemplatePath},
+				{Kind: "CronJob", TemplatePath: CronJobPodTemplatePath},
+			},
+			formatted: DefaultPodControllers,
+		},
+		{
+			controllers: "none",
+			formatted:   "none",
+		},
+		{
+			controllers: " Deployment, Rollout ,CloneSet=spec.template., Foo=.spec.podTemplate",
+			expected: []PodController{
+				{Kind: "Deployment", TemplatePath: DefaultPodTemplatePath},
+				{Kind: "Rollout", TemplatePath: DefaultPodTemplatePath},
+				{Kind: "CloneSet", TemplatePath: DefaultPodTemplatePath},
+				{Kind: "Foo", TemplatePath: "spec.podTemplate"},
+			},
+			formatted: "Deployment,Rollout,CloneSet,Foo=spec.podTemplate",
+		},
+		{
+			controllers: "CronJob=spec.template",
+			expected:    []PodController{{Kind: "CronJob", TemplatePath: CronJobPodTemplatePath}},
+			formatted:   "CronJob",
+		},
+		{
+			controllers: "CronJob=spec.podTemplate",
+			err:         "the pod template path of CronJob cannot be changed",
+		},
+		{
+			controllers: "Deployment,=spec.template",
+			err:         "invalid pod controller",
+		},
+		{
+			controllers: "Foo=",
+			err:         "invalid pod controller",
+		},
+		{
+			controllers: "Deployment,Deployment=spec.template",
+			err:         "duplicate pod controller Deployment",
+		},
+	}
+
+	for _, tc := range testCases {
+		podControllers, err := ParsePodControllers(tc.controllers)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err, tc.controllers)
+			continue
+		}
+
+		assert.NilError(t, err, tc.controllers)
+		assert.DeepEqual(t, podControllers, tc.expected)
+		assert.Equal(t, FormatPodControllers(podControllers), tc.formatted)
+	}
+}
+
+func Test_PodControllerKinds(t *testing.T) {
+	assert.DeepEqual(t, PodControllerKinds("Deployment, CloneSet=spec.template,Foo=spec.podTemplate"), []string{"Deployment", "CloneSet", "Foo"})
+	assert.DeepEqual(t, PodControllerKinds("all"), []string{"DaemonSet", "Deployment", "Job", "StatefulSet", "CronJob"})
+	assert.Assert(t, PodControllerKinds("none") == nil)
+}
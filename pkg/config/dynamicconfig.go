@@ -8,13 +8,16 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	kyvernoclient "github.com/kyverno/kyverno/pkg/client/clientset/versioned"
 	"github.com/minio/pkg/wildcard"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
+	listerv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -24,6 +27,17 @@ const cmNameEnv string = "INIT_CONFIG"
 
 var defaultExcludeGroupRole []string = []string{"system:serviceaccounts:kube-system", "system:nodes", "system:kube-scheduler"}
 
+const (
+	// defaultMaxAdmissionWarnings is the default number of warnings returned in an admission response
+	defaultMaxAdmissionWarnings = 10
+	// defaultMaxAdmissionWarningSize is the default length of a single admission warning, longer warnings are truncated
+	defaultMaxAdmissionWarningSize = 256
+	// defaultFailurePolicy is the default failure policy of the resource webhooks
+	defaultFailurePolicy = "Ignore"
+	// defaultMaxReportResults is the default number of results in a policy report
+	defaultMaxReportResults = 1000
+)
+
 type WebhookConfig struct {
 	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty" protobuf:"bytes,5,opt,name=namespaceSelector"`
 }
@@ -31,7 +45,9 @@ type WebhookConfig struct {
 // ConfigData stores the configuration
 type ConfigData struct {
 	client                      kubernetes.Interface
+	kyvernoClient               kyvernoclient.Interface
 	cmName                      string
+	cmLister                    listerv1.ConfigMapLister
 	mux                         sync.RWMutex
 	filters                     []k8Resource
 	excludeGroupRole            []string
@@ -39,7 +55,16 @@ type ConfigData struct {
 	restrictDevelopmentUsername []string
 	webhooks                    []WebhookConfig
 	generateSuccessEvents       bool
+	maxAdmissionWarnings        int
+	maxAdmissionWarningSize     int
+	failurePolicy               string
+	backgroundScanInterval      time.Duration
+	maxReportResults            int
+	autogenControllers          string
+	// kyvernoConfigApplied is true when the KyvernoConfig is used instead of the ConfigMap
+	kyvernoConfigApplied        bool
 	cmSycned                    cache.InformerSynced
+	kyvernoConfigSynced         cache.InformerSynced
 	reconcilePolicyReport       chan<- bool
 	updateWebhookConfigurations chan<- bool
 	log                         logr.Logger
@@ -92,6 +117,48 @@ func (cd *ConfigData) GetGenerateSuccessEvents() bool {
 	return cd.generateSuccessEvents
 }
 
+// GetMaxAdmissionWarnings return the maximum number of warnings returned in an admission response
+func (cd *ConfigData) GetMaxAdmissionWarnings() int {
+	cd.mux.RLock()
+	defer cd.mux.RUnlock()
+	return cd.maxAdmissionWarnings
+}
+
+// GetMaxAdmissionWarningSize return the maximum length of a single admission warning
+func (cd *ConfigData) GetMaxAdmissionWarningSize() int {
+	cd.mux.RLock()
+	defer cd.mux.RUnlock()
+	return cd.maxAdmissionWarningSize
+}
+
+// GetDefaultFailurePolicy return the failure policy of the resource webhooks
+func (cd *ConfigData) GetDefaultFailurePolicy() string {
+	cd.mux.RLock()
+	defer cd.mux.RUnlock()
+	return cd.failurePolicy
+}
+
+// GetBackgroundScanInterval return the interval between background scans, zero if it is not configured
+func (cd *ConfigData) GetBackgroundScanInterval() time.Duration {
+	cd.mux.RLock()
+	defer cd.mux.RUnlock()
+	return cd.backgroundScanInterval
+}
+
+// GetMaxReportResults return the maximum number of results in a policy report
+func (cd *ConfigData) GetMaxReportResults() int {
+	cd.mux.RLock()
+	defer cd.mux.RUnlock()
+	return cd.maxReportResults
+}
+
+// GetAutogenControllers return the comma-separated list of the pod controllers Pod policies are auto-generated for
+func (cd *ConfigData) GetAutogenControllers() string {
+	cd.mux.RLock()
+	defer cd.mux.RUnlock()
+	return cd.autogenControllers
+}
+
 // FilterNamespaces filters exclude namespace
 func (cd *ConfigData) FilterNamespaces(namespaces []string) []string {
 	var results []string
@@ -120,6 +187,12 @@ type Interface interface {
 	GetExcludeGroupRole() []string
 	GetExcludeUsername() []string
 	GetGenerateSuccessEvents() bool
+	GetMaxAdmissionWarnings() int
+	GetMaxAdmissionWarningSize() int
+	GetDefaultFailurePolicy() string
+	GetBackgroundScanInterval() time.Duration
+	GetMaxReportResults() int
+	GetAutogenControllers() string
 	RestrictDevelopmentUsername() []string
 	FilterNamespaces(namespaces []string) []string
 	GetWebhooks() []WebhookConfig
@@ -136,9 +209,15 @@ func NewConfigData(rclient kubernetes.Interface, cmInformer informers.ConfigMapI
 	cd := ConfigData{
 		client:                      rclient,
 		cmName:                      os.Getenv(cmNameEnv),
+		cmLister:                    cmInformer.Lister(),
 		cmSycned:                    cmInformer.Informer().HasSynced,
 		reconcilePolicyReport:       reconcilePolicyReport,
 		updateWebhookConfigurations: updateWebhookConfigurations,
+		maxAdmissionWarnings:        defaultMaxAdmissionWarnings,
+		maxAdmissionWarningSize:     defaultMaxAdmissionWarningSize,
+		failurePolicy:               defaultFailurePolicy,
+		maxReportResults:            defaultMaxReportResults,
+		autogenControllers:          DefaultPodControllers,
 		log:                         log,
 	}
 
@@ -176,14 +255,18 @@ func NewConfigData(rclient kubernetes.Interface, cmInformer informers.ConfigMapI
 func (cd *ConfigData) Run(stopCh <-chan struct{}) {
 	logger := cd.log
 	// wait for cache to populate first time
-	if !cache.WaitForCacheSync(stopCh, cd.cmSycned) {
+	synced := []cache.InformerSynced{cd.cmSycned}
+	if cd.kyvernoConfigSynced != nil {
+		synced = append(synced, cd.kyvernoConfigSynced)
+	}
+	if !cache.WaitForCacheSync(stopCh, synced...) {
 		logger.Info("configuration: failed to sync informer cache")
 	}
 }
 
 func (cd *ConfigData) addCM(obj interface{}) {
 	cm := obj.(*v1.ConfigMap)
-	if cm.Name != cd.cmName {
+	if cm.Name != cd.cmName || cd.usesKyvernoConfig() {
 		return
 	}
 	cd.load(*cm)
@@ -191,19 +274,31 @@ func (cd *ConfigData) addCM(obj interface{}) {
 
 func (cd *ConfigData) updateCM(old, cur interface{}) {
 	cm := cur.(*v1.ConfigMap)
-	if cm.Name != cd.cmName {
+	if cm.Name != cd.cmName || cd.usesKyvernoConfig() {
 		return
 	}
 	// if data has not changed then dont load configmap
 	reconcilePolicyReport, updateWebook := cd.load(*cm)
+	cd.notify(reconcilePolicyReport, updateWebook)
+}
+
+// notify signals the policy controller and the webhook registration about configuration changes,
+// the signals are not sent when one is already pending, e.g. while the receivers wait for the leadership
+func (cd *ConfigData) notify(reconcilePolicyReport, updateWebook bool) {
 	if reconcilePolicyReport {
 		cd.log.Info("resource filters changed, sending reconcile signal to the policy controller")
-		cd.reconcilePolicyReport <- true
+		select {
+		case cd.reconcilePolicyReport <- true:
+		default:
+		}
 	}
 
 	if updateWebook {
 		cd.log.Info("webhook configurations changed, updating webhook configurations")
-		cd.updateWebhookConfigurations <- true
+		select {
+		case cd.updateWebhookConfigurations <- true:
+		default:
+		}
 	}
 }
 
@@ -223,7 +318,7 @@ func (cd *ConfigData) deleteCM(obj interface{}) {
 		}
 	}
 
-	if cm.Name != cd.cmName {
+	if cm.Name != cd.cmName || cd.usesKyvernoConfig() {
 		return
 	}
 	// remove the configuration parameters
@@ -317,6 +412,46 @@ func (cd *ConfigData) load(cm v1.ConfigMap) (reconcilePolicyReport, updateWebhoo
 		}
 	}
 
+	maxAdmissionWarnings, ok := cm.Data["maxAdmissionWarnings"]
+	if !ok {
+		logger.V(4).Info("configuration: No maxAdmissionWarnings defined in ConfigMap")
+	} else {
+		maxAdmissionWarnings, err := strconv.Atoi(maxAdmissionWarnings)
+		if err != nil || maxAdmissionWarnings < 0 {
+			logger.V(4).Info("configuration: maxAdmissionWarnings must be a non-negative integer")
+		} else if maxAdmissionWarnings != cd.maxAdmissionWarnings {
+			logger.V(2).Info("Updated maxAdmissionWarnings", "oldMaxAdmissionWarnings", cd.maxAdmissionWarnings, "newMaxAdmissionWarnings", maxAdmissionWarnings)
+			cd.maxAdmissionWarnings = maxAdmissionWarnings
+		}
+	}
+
+	maxAdmissionWarningSize, ok := cm.Data["maxAdmissionWarningSize"]
+	if !ok {
+		logger.V(4).Info("configuration: No maxAdmissionWarningSize defined in ConfigMap")
+	} else {
+		maxAdmissionWarningSize, err := strconv.Atoi(maxAdmissionWarningSize)
+		if err != nil || maxAdmissionWarningSize <= 0 {
+			logger.V(4).Info("configuration: maxAdmissionWarningSize must be a positive integer")
+		} else if maxAdmissionWarningSize != cd.maxAdmissionWarningSize {
+			logger.V(2).Info("Updated maxAdmissionWarningSize", "oldMaxAdmissionWarningSize", cd.maxAdmissionWarningSize, "newMaxAdmissionWarningSize", maxAdmissionWarningSize)
+			cd.maxAdmissionWarningSize = maxAdmissionWarningSize
+		}
+	}
+
+	autogenControllers, ok := cm.Data["autogenControllers"]
+	if !ok {
+		logger.V(4).Info("configuration: No autogenControllers defined in ConfigMap")
+	} else {
+		podControllers, err := ParsePodControllers(autogenControllers)
+		if err != nil {
+			logger.Error(err, "configuration: invalid autogenControllers")
+		} else if controllers := FormatPodControllers(podControllers); controllers != cd.autogenControllers {
+			logger.V(2).Info("Updated autogenControllers", "oldAutogenControllers", cd.autogenControllers, "newAutogenControllers", controllers)
+			cd.autogenControllers = controllers
+			reconcilePolicyReport = true
+		}
+	}
+
 	return
 }
 
@@ -361,6 +496,9 @@ func (cd *ConfigData) unload(cm v1.ConfigMap) {
 	cd.excludeGroupRole = append(cd.excludeGroupRole, defaultExcludeGroupRole...)
 	cd.excludeUsername = []string{}
 	cd.generateSuccessEvents = false
+	cd.maxAdmissionWarnings = defaultMaxAdmissionWarnings
+	cd.maxAdmissionWarningSize = defaultMaxAdmissionWarningSize
+	cd.autogenControllers = DefaultPodControllers
 }
 
 type k8Resource struct {
@@ -0,0 +1,280 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	kyvernov1alpha1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1alpha1"
+	kyvernoclient "github.com/kyverno/kyverno/pkg/client/clientset/versioned"
+	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// KyvernoConfigName is the name of the KyvernoConfig used by Kyverno, other KyvernoConfigs are ignored
+const KyvernoConfigName = "kyverno"
+
+// WatchKyvernoConfig loads the configuration from the KyvernoConfig named kyverno.
+// The KyvernoConfig takes precedence over the init ConfigMap, which is used again
+// once the KyvernoConfig is deleted. It must be called before Run.
+func (cd *ConfigData) WatchKyvernoConfig(kclient kyvernoclient.Interface, kcInformer kyvernoinformer.KyvernoConfigInformer) {
+	cd.kyvernoClient = kclient
+	cd.kyvernoConfigSynced = kcInformer.Informer().HasSynced
+
+	kcInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    cd.addKyvernoConfig,
+		UpdateFunc: cd.updateKyvernoConfig,
+		DeleteFunc: cd.deleteKyvernoConfig,
+	})
+}
+
+func (cd *ConfigData) usesKyvernoConfig() bool {
+	cd.mux.RLock()
+	defer cd.mux.RUnlock()
+	return cd.kyvernoConfigApplied
+}
+
+func (cd *ConfigData) addKyvernoConfig(obj interface{}) {
+	kc := obj.(*kyvernov1alpha1.KyvernoConfig)
+	// the initial configuration is picked up by the webhook registration and the first background scan
+	cd.applyKyvernoConfig(kc, cd.kyvernoConfigSynced())
+}
+
+func (cd *ConfigData) updateKyvernoConfig(old, cur interface{}) {
+	oldKc := old.(*kyvernov1alpha1.KyvernoConfig)
+	curKc := cur.(*kyvernov1alpha1.KyvernoConfig)
+	// status updates and resyncs do not change the spec
+	if oldKc.Generation == curKc.Generation && curKc.Status.ObservedGeneration == curKc.Generation {
+		return
+	}
+	cd.applyKyvernoConfig(curKc, true)
+}
+
+func (cd *ConfigData) deleteKyvernoConfig(obj interface{}) {
+	logger := cd.log
+	kc, ok := obj.(*kyvernov1alpha1.KyvernoConfig)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			logger.Info("failed to get object from tombstone")
+			return
+		}
+		kc, ok = tombstone.Obj.(*kyvernov1alpha1.KyvernoConfig)
+		if !ok {
+			logger.Info("Tombstone contained object that is not a KyvernoConfig", "object", obj)
+			return
+		}
+	}
+
+	if kc.Name != KyvernoConfigName {
+		return
+	}
+
+	logger.Info("KyvernoConfig deleted, falling back to the ConfigMap", "name", kc.Name, "configmap", cd.cmName)
+	cd.unloadKyvernoConfig()
+	cm, err := cd.cmLister.ConfigMaps(KyvernoNamespace).Get(cd.cmName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "failed to get ConfigMap", "name", cd.cmName)
+		}
+	} else {
+		cd.load(*cm)
+	}
+	cd.notify(true, true)
+}
+
+func (cd *ConfigData) applyKyvernoConfig(kc *kyvernov1alpha1.KyvernoConfig, notify bool) {
+	logger := cd.log.WithValues("name", kc.Name)
+	if kc.Name != KyvernoConfigName {
+		logger.Info("ignoring KyvernoConfig", "expectedName", KyvernoConfigName)
+		cd.updateKyvernoConfigStatus(kc, false, []string{fmt.Sprintf("only the KyvernoConfig named %s is used", KyvernoConfigName)})
+		return
+	}
+
+	reconcilePolicyReport, updateWebhook, errs := cd.loadKyvernoConfig(kc)
+	if len(errs) != 0 {
+		logger.Info("failed to apply some settings of the KyvernoConfig", "errors", errs)
+	}
+	cd.updateKyvernoConfigStatus(kc, true, errs)
+	if notify {
+		cd.notify(reconcilePolicyReport, updateWebhook)
+	}
+}
+
+// loadKyvernoConfig replaces the configuration with the KyvernoConfig spec, the settings
+// that are not set are reset to their defaults. Invalid settings are returned as errors
+// and keep their current value.
+func (cd *ConfigData) loadKyvernoConfig(kc *kyvernov1alpha1.KyvernoConfig) (reconcilePolicyReport, updateWebhook bool, errs []string) {
+	logger := cd.log.WithValues("name", kc.Name)
+	spec := kc.Spec
+
+	cd.mux.Lock()
+	defer cd.mux.Unlock()
+	cd.kyvernoConfigApplied = true
+
+	filters := make([]k8Resource, 0, len(spec.ResourceFilters))
+	for _, f := range spec.ResourceFilters {
+		filters = append(filters, k8Resource{Kind: f.Kind, Namespace: wildcardIfEmpty(f.Namespace), Name: wildcardIfEmpty(f.Name)})
+	}
+	if !reflect.DeepEqual(filters, cd.filters) {
+		logger.V(2).Info("Updated resource filters", "oldFilters", cd.filters, "newFilters", filters)
+		cd.filters = filters
+		reconcilePolicyReport = true
+	}
+
+	excludeGroupRole := append(append([]string{}, spec.ExcludeGroupRoles...), defaultExcludeGroupRole...)
+	if !reflect.DeepEqual(excludeGroupRole, cd.excludeGroupRole) {
+		logger.V(2).Info("Updated resource excludeGroupRoles", "oldExcludeGroupRole", cd.excludeGroupRole, "newExcludeGroupRole", excludeGroupRole)
+		cd.excludeGroupRole = excludeGroupRole
+		reconcilePolicyReport = true
+	}
+
+	excludeUsername := append([]string{}, spec.ExcludeUsernames...)
+	if !reflect.DeepEqual(excludeUsername, cd.excludeUsername) {
+		logger.V(2).Info("Updated resource excludeUsernames", "oldExcludeUsername", cd.excludeUsername, "newExcludeUsername", excludeUsername)
+		cd.excludeUsername = excludeUsername
+		reconcilePolicyReport = true
+	}
+
+	var webhooks []WebhookConfig
+	validWebhooks := true
+	for i, w := range spec.Webhooks {
+		if _, err := metav1.LabelSelectorAsSelector(w.NamespaceSelector); err != nil {
+			errs = append(errs, fmt.Sprintf("spec.webhooks[%d].namespaceSelector: %v", i, err))
+			validWebhooks = false
+			continue
+		}
+		webhooks = append(webhooks, WebhookConfig{NamespaceSelector: w.NamespaceSelector})
+	}
+	if validWebhooks && !reflect.DeepEqual(webhooks, cd.webhooks) {
+		logger.Info("Updated webhooks configurations", "oldWebhooks", cd.webhooks, "newWebhooks", webhooks)
+		cd.webhooks = webhooks
+		updateWebhook = true
+	}
+
+	failurePolicy := defaultFailurePolicy
+	if spec.DefaultFailurePolicy != nil {
+		failurePolicy = *spec.DefaultFailurePolicy
+	}
+	if failurePolicy != "Ignore" && failurePolicy != "Fail" {
+		errs = append(errs, fmt.Sprintf("spec.defaultFailurePolicy: must be either Ignore or Fail, got %s", failurePolicy))
+	} else if failurePolicy != cd.failurePolicy {
+		logger.V(2).Info("Updated defaultFailurePolicy", "oldFailurePolicy", cd.failurePolicy, "newFailurePolicy", failurePolicy)
+		cd.failurePolicy = failurePolicy
+		updateWebhook = true
+	}
+
+	generateSuccessEvents := spec.GenerateSuccessEvents != nil && *spec.GenerateSuccessEvents
+	if generateSuccessEvents != cd.generateSuccessEvents {
+		logger.V(2).Info("Updated generateSuccessEvents", "oldGenerateSuccessEvents", cd.generateSuccessEvents, "newGenerateSuccessEvents", generateSuccessEvents)
+		cd.generateSuccessEvents = generateSuccessEvents
+		reconcilePolicyReport = true
+	}
+
+	maxAdmissionWarnings := defaultMaxAdmissionWarnings
+	if spec.MaxAdmissionWarnings != nil {
+		maxAdmissionWarnings = *spec.MaxAdmissionWarnings
+	}
+	if maxAdmissionWarnings < 0 {
+		errs = append(errs, "spec.maxAdmissionWarnings: must be a non-negative integer")
+	} else {
+		cd.maxAdmissionWarnings = maxAdmissionWarnings
+	}
+
+	maxAdmissionWarningSize := defaultMaxAdmissionWarningSize
+	if spec.MaxAdmissionWarningSize != nil {
+		maxAdmissionWarningSize = *spec.MaxAdmissionWarningSize
+	}
+	if maxAdmissionWarningSize <= 0 {
+		errs = append(errs, "spec.maxAdmissionWarningSize: must be a positive integer")
+	} else {
+		cd.maxAdmissionWarningSize = maxAdmissionWarningSize
+	}
+
+	var backgroundScanInterval time.Duration
+	if spec.BackgroundScanInterval != nil {
+		backgroundScanInterval = spec.BackgroundScanInterval.Duration
+	}
+	if backgroundScanInterval < 0 || (spec.BackgroundScanInterval != nil && backgroundScanInterval == 0) {
+		errs = append(errs, "spec.backgroundScanInterval: must be a positive duration")
+	} else if backgroundScanInterval != cd.backgroundScanInterval {
+		logger.V(2).Info("Updated backgroundScanInterval", "oldBackgroundScanInterval", cd.backgroundScanInterval, "newBackgroundScanInterval", backgroundScanInterval)
+		cd.backgroundScanInterval = backgroundScanInterval
+	}
+
+	maxReportResults := defaultMaxReportResults
+	if spec.ReportLimits != nil && spec.ReportLimits.MaxResultsPerReport != nil {
+		maxReportResults = *spec.ReportLimits.MaxResultsPerReport
+	}
+	if maxReportResults <= 0 {
+		errs = append(errs, "spec.reportLimits.maxResultsPerReport: must be a positive integer")
+	} else {
+		cd.maxReportResults = maxReportResults
+	}
+
+	autogenControllers := DefaultPodControllers
+	if len(spec.AutogenControllers) != 0 {
+		entries := make([]string, 0, len(spec.AutogenControllers))
+		for _, c := range spec.AutogenControllers {
+			entries = append(entries, PodController{Kind: c.Kind, TemplatePath: c.TemplatePath}.String())
+		}
+		autogenControllers = strings.Join(entries, ",")
+	}
+	if podControllers, err := ParsePodControllers(autogenControllers); err != nil {
+		errs = append(errs, fmt.Sprintf("spec.autogenControllers: %v", err))
+	} else if controllers := FormatPodControllers(podControllers); controllers != cd.autogenControllers {
+		logger.V(2).Info("Updated autogenControllers", "oldAutogenControllers", cd.autogenControllers, "newAutogenControllers", controllers)
+		cd.autogenControllers = controllers
+		reconcilePolicyReport = true
+	}
+
+	return
+}
+
+// unloadKyvernoConfig resets the configuration to the defaults
+func (cd *ConfigData) unloadKyvernoConfig() {
+	cd.mux.Lock()
+	defer cd.mux.Unlock()
+	cd.kyvernoConfigApplied = false
+	cd.filters = []k8Resource{}
+	cd.excludeGroupRole = append([]string{}, defaultExcludeGroupRole...)
+	cd.excludeUsername = []string{}
+	cd.webhooks = nil
+	cd.generateSuccessEvents = false
+	cd.maxAdmissionWarnings = defaultMaxAdmissionWarnings
+	cd.maxAdmissionWarningSize = defaultMaxAdmissionWarningSize
+	cd.failurePolicy = defaultFailurePolicy
+	cd.backgroundScanInterval = 0
+	cd.maxReportResults = defaultMaxReportResults
+	cd.autogenControllers = DefaultPodControllers
+}
+
+func (cd *ConfigData) updateKyvernoConfigStatus(kc *kyvernov1alpha1.KyvernoConfig, applied bool, errs []string) {
+	if kc.Status.Applied == applied && kc.Status.ObservedGeneration == kc.Generation && reflect.DeepEqual(kc.Status.Errors, errs) {
+		return
+	}
+
+	kc = kc.DeepCopy()
+	kc.Status.Applied = applied
+	kc.Status.ObservedGeneration = kc.Generation
+	kc.Status.Errors = errs
+	if applied {
+		now := metav1.Now()
+		kc.Status.LastAppliedTime = &now
+	}
+
+	if _, err := cd.kyvernoClient.KyvernoV1alpha1().KyvernoConfigs().UpdateStatus(context.TODO(), kc, metav1.UpdateOptions{}); err != nil {
+		cd.log.Error(err, "failed to update KyvernoConfig status", "name", kc.Name)
+	}
+}
+
+func wildcardIfEmpty(s string) string {
+	if s == "" {
+		return "*"
+	}
+	return s
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kyvernov1alpha1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1alpha1"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned/fake"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func newTestConfigData(objects ...*kyvernov1alpha1.KyvernoConfig) *ConfigData {
+	client := fake.NewSimpleClientset()
+	for _, obj := range objects {
+		_, _ = client.KyvernoV1alpha1().KyvernoConfigs().Create(context.TODO(), obj, metav1.CreateOptions{})
+	}
+
+	return &ConfigData{
+		kyvernoClient:           client,
+		excludeGroupRole:        append([]string{}, defaultExcludeGroupRole...),
+		maxAdmissionWarnings:    defaultMaxAdmissionWarnings,
+		maxAdmissionWarningSize: defaultMaxAdmissionWarningSize,
+		failurePolicy:           defaultFailurePolicy,
+		maxReportResults:        defaultMaxReportResults,
+		autogenControllers:      DefaultPodControllers,
+		log:                     log.Log,
+	}
+}
+
+func Test_LoadKyvernoConfig(t *testing.T) {
+	failurePolicy := "Fail"
+	generateSuccessEvents := true
+	maxResults := 50
+	kc := &kyvernov1alpha1.KyvernoConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: KyvernoConfigName},
+		Spec: kyvernov1alpha1.KyvernoConfigSpec{
+			ResourceFilters: []kyvernov1alpha1.ResourceFilter{
+				{Kind: "Event"},
+				{Kind: "*", Namespace: "kube-system"},
+			},
+			ExcludeGroupRoles: []string{"system:masters"},
+			ExcludeUsernames:  []string{"admin"},
+			Webhooks: []kyvernov1alpha1.WebhookConfiguration{{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kyverno": "enabled"}},
+			}},
+			DefaultFailurePolicy:   &failurePolicy,
+			GenerateSuccessEvents:  &generateSuccessEvents,
+			BackgroundScanInterval: &metav1.Duration{Duration: 30 * time.Minute},
+			ReportLimits:           &kyvernov1alpha1.ReportLimits{MaxResultsPerReport: &maxResults},
+			AutogenControllers: []kyvernov1alpha1.AutogenController{
+				{Kind: "Deployment"},
+				{Kind: "Rollout", TemplatePath: "spec.template"},
+				{Kind: "Foo", TemplatePath: "spec.podTemplate"},
+			},
+		},
+	}
+
+	cd := newTestConfigData()
+	reconcilePolicyReport, updateWebhook, errs := cd.loadKyvernoConfig(kc)
+	assert.Assert(t, reconcilePolicyReport)
+	assert.Assert(t, updateWebhook)
+	assert.Equal(t, len(errs), 0)
+	assert.Assert(t, cd.usesKyvernoConfig())
+
+	assert.Assert(t, cd.ToFilter("Event", "default", "event-1"))
+	assert.Assert(t, cd.ToFilter("Pod", "kube-system", "coredns"))
+	assert.Assert(t, !cd.ToFilter("Pod", "default", "nginx"))
+	assert.DeepEqual(t, cd.GetExcludeGroupRole(), append([]string{"system:masters"}, defaultExcludeGroupRole...))
+	assert.DeepEqual(t, cd.GetExcludeUsername(), []string{"admin"})
+	assert.Equal(t, cd.GetWebhooks()[0].NamespaceSelector.MatchLabels["kyverno"], "enabled")
+	assert.Equal(t, cd.GetDefaultFailurePolicy(), "Fail")
+	assert.Assert(t, cd.GetGenerateSuccessEvents())
+	assert.Equal(t, cd.GetBackgroundScanInterval(), 30*time.Minute)
+	assert.Equal(t, cd.GetMaxReportResults(), 50)
+	assert.Equal(t, cd.GetAutogenControllers(), "Deployment,Rollout,Foo=spec.podTemplate")
+	assert.Equal(t, cd.GetMaxAdmissionWarnings(), defaultMaxAdmissionWarnings)
+
+	// loading the same spec again does not trigger any update
+	reconcilePolicyReport, updateWebhook, _ = cd.loadKyvernoConfig(kc)
+	assert.Assert(t, !reconcilePolicyReport)
+	assert.Assert(t, !updateWebhook)
+
+	cd.unloadKyvernoConfig()
+	assert.Assert(t, !cd.usesKyvernoConfig())
+	assert.Assert(t, !cd.ToFilter("Event", "default", "event-1"))
+	assert.Equal(t, cd.GetDefaultFailurePolicy(), defaultFailurePolicy)
+	assert.Equal(t, cd.GetBackgroundScanInterval(), time.Duration(0))
+	assert.Equal(t, cd.GetMaxReportResults(), defaultMaxReportResults)
+	assert.Equal(t, cd.GetAutogenControllers(), DefaultPodControllers)
+}
+
+func Test_LoadKyvernoConfig_InvalidSettings(t *testing.T) {
+	failurePolicy := "Maybe"
+	kc := &kyvernov1alpha1.KyvernoConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: KyvernoConfigName},
+		Spec: kyvernov1alpha1.KyvernoConfigSpec{
+			Webhooks: []kyvernov1alpha1.WebhookConfiguration{{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "kyverno", Operator: "Unknown"}},
+				},
+			}},
+			DefaultFailurePolicy:   &failurePolicy,
+			BackgroundScanInterval: &metav1.Duration{},
+		},
+	}
+
+	cd := newTestConfigData()
+	_, updateWebhook, errs := cd.loadKyvernoConfig(kc)
+	assert.Assert(t, !updateWebhook)
+	assert.Equal(t, len(errs), 3)
+	assert.Assert(t, cd.GetWebhooks() == nil)
+	assert.Equal(t, cd.GetDefaultFailurePolicy(), defaultFailurePolicy)
+	assert.Equal(t, cd.GetBackgroundScanInterval(), time.Duration(0))
+}
+
+func Test_LoadKyvernoConfig_AutogenControllers(t *testing.T) {
+	kc := &kyvernov1alpha1.KyvernoConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: KyvernoConfigName},
+		Spec: kyvernov1alpha1.KyvernoConfigSpec{
+			AutogenControllers: []kyvernov1alpha1.AutogenController{{Kind: "Deployment"}},
+		},
+	}
+
+	cd := newTestConfigData()
+	_, _, errs := cd.loadKyvernoConfig(kc)
+	assert.Equal(t, len(errs), 0)
+
+	reconcilePolicyReport, _, _ := cd.loadKyvernoConfig(kc)
+	assert.Assert(t, !reconcilePolicyReport)
+
+	// the policies are reconciled to update their auto-generated rules
+	kc.Spec.AutogenControllers = append(kc.Spec.AutogenControllers, kyvernov1alpha1.AutogenController{Kind: "StatefulSet"})
+	reconcilePolicyReport, _, _ = cd.loadKyvernoConfig(kc)
+	assert.Assert(t, reconcilePolicyReport)
+	assert.Equal(t, cd.GetAutogenControllers(), "Deployment,StatefulSet")
+}
+
+func Test_ApplyKyvernoConfig_Status(t *testing.T) {
+	kc := &kyvernov1alpha1.KyvernoConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: KyvernoConfigName, Generation: 2},
+		Spec: kyvernov1alpha1.KyvernoConfigSpec{
+			ResourceFilters: []kyvernov1alpha1.ResourceFilter{{Kind: "Event"}},
+		},
+	}
+	other := &kyvernov1alpha1.KyvernoConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Generation: 1},
+	}
+
+	cd := newTestConfigData(kc, other)
+	cd.applyKyvernoConfig(kc, false)
+	cd.applyKyvernoConfig(other, false)
+
+	applied, err := cd.kyvernoClient.KyvernoV1alpha1().KyvernoConfigs().Get(context.TODO(), KyvernoConfigName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, applied.Status.Applied)
+	assert.Equal(t, applied.Status.ObservedGeneration, int64(2))
+	assert.Assert(t, applied.Status.LastAppliedTime != nil)
+	assert.Equal(t, len(applied.Status.Errors), 0)
+
+	ignored, err := cd.kyvernoClient.KyvernoV1alpha1().KyvernoConfigs().Get(context.TODO(), "other", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, !ignored.Status.Applied)
+	assert.Equal(t, len(ignored.Status.Errors), 1)
+	assert.Assert(t, cd.ToFilter("Event", "default", "event-1"))
+}
+
+func Test_Notify_DoesNotBlock(t *testing.T) {
+	cd := newTestConfigData()
+	cd.reconcilePolicyReport = make(chan bool, 1)
+	cd.updateWebhookConfigurations = make(chan bool, 1)
+
+	// the signals are not received while the controllers wait for the leadership
+	done := make(chan struct{})
+	go func() {
+		cd.notify(true, true)
+		cd.notify(true, true)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("notify blocked on pending signals")
+	}
+
+	assert.Equal(t, len(cd.reconcilePolicyReport), 1)
+	assert.Equal(t, len(cd.updateWebhookConfigurations), 1)
+}
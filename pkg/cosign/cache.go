@@ -0,0 +1,126 @@
+package cosign
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Cache stores the digests of verified images, so that an image is verified against the registry
+// once per policy and key until the entry expires. Only successful verifications are cached, and
+// the images are referenced by digest as a tag can be moved to another image.
+type Cache struct {
+	mutex   sync.Mutex
+	maxSize int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	// lru holds the cache entries, the most recently used entry first
+	lru *list.List
+
+	// requests counts the cache hits and misses, it is optional
+	requests *prom.CounterVec
+
+	now func() time.Time
+}
+
+type cacheEntry struct {
+	key     string
+	digest  string
+	expires time.Time
+}
+
+// DefaultCache is used by the engine to cache image verifications, caching is disabled when it is nil
+var DefaultCache *Cache
+
+// InitializeCache sets the DefaultCache, caching is disabled when maxSize or ttl is not positive
+func InitializeCache(maxSize int, ttl time.Duration, requests *prom.CounterVec) {
+	if maxSize <= 0 || ttl <= 0 {
+		DefaultCache = nil
+		return
+	}
+
+	DefaultCache = NewCache(maxSize, ttl, requests)
+}
+
+// NewCache returns a cache holding at most maxSize entries for the ttl duration,
+// the least recently used entries are evicted when the cache is full
+func NewCache(maxSize int, ttl time.Duration, requests *prom.CounterVec) *Cache {
+	return &Cache{
+		maxSize:  maxSize,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		requests: requests,
+		now:      time.Now,
+	}
+}
+
+// Get returns the digest of an image verified by the policy with the key
+func (c *Cache) Get(policy, image, key string) (string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cacheKey := buildCacheKey(policy, image, key)
+	element, ok := c.entries[cacheKey]
+	if ok && c.now().After(element.Value.(*cacheEntry).expires) {
+		c.remove(element)
+		ok = false
+	}
+
+	if !ok {
+		c.record("miss")
+		return "", false
+	}
+
+	c.lru.MoveToFront(element)
+	c.record("hit")
+	return element.Value.(*cacheEntry).digest, true
+}
+
+// Set stores the digest of an image verified by the policy with the key
+func (c *Cache) Set(policy, image, key, digest string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cacheKey := buildCacheKey(policy, image, key)
+	entry := &cacheEntry{key: cacheKey, digest: digest, expires: c.now().Add(c.ttl)}
+	if element, ok := c.entries[cacheKey]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.entries[cacheKey] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxSize {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Len returns the number of entries in the cache, including expired entries that are not evicted yet
+func (c *Cache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.lru.Len()
+}
+
+func (c *Cache) remove(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+}
+
+func (c *Cache) record(result string) {
+	if c.requests != nil {
+		c.requests.With(prom.Labels{"cache_result": result}).Inc()
+	}
+}
+
+// buildCacheKey returns the cache key, the image reference contains the digest
+// and the PEM encoded key is hashed to keep the key short
+func buildCacheKey(policy, image, key string) string {
+	return fmt.Sprintf("%s/%s/%x", policy, image, sha256.Sum256([]byte(key)))
+}
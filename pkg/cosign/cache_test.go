@@ -0,0 +1,69 @@
+package cosign
+
+import (
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/assert"
+)
+
+func Test_Cache(t *testing.T) {
+	requests := prom.NewCounterVec(prom.CounterOpts{Name: "test_cache_requests"}, []string{"cache_result"})
+	cache := NewCache(2, time.Minute, requests)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	_, ok := cache.Get("check-images", "ghcr.io/kyverno/test:v1", "key")
+	assert.Assert(t, !ok)
+
+	cache.Set("check-images", "ghcr.io/kyverno/test:v1", "key", "sha256:1")
+	digest, ok := cache.Get("check-images", "ghcr.io/kyverno/test:v1", "key")
+	assert.Assert(t, ok)
+	assert.Equal(t, digest, "sha256:1")
+
+	// entries are cached per policy and key
+	_, ok = cache.Get("other-policy", "ghcr.io/kyverno/test:v1", "key")
+	assert.Assert(t, !ok)
+	_, ok = cache.Get("check-images", "ghcr.io/kyverno/test:v1", "other-key")
+	assert.Assert(t, !ok)
+
+	assert.Equal(t, testutil.ToFloat64(requests.WithLabelValues("hit")), float64(1))
+	assert.Equal(t, testutil.ToFloat64(requests.WithLabelValues("miss")), float64(3))
+}
+
+func Test_Cache_Eviction(t *testing.T) {
+	cache := NewCache(2, time.Minute, nil)
+
+	cache.Set("check-images", "ghcr.io/kyverno/test:v1", "key", "sha256:1")
+	cache.Set("check-images", "ghcr.io/kyverno/test:v2", "key", "sha256:2")
+
+	// v1 becomes the most recently used entry, so v2 is evicted
+	_, ok := cache.Get("check-images", "ghcr.io/kyverno/test:v1", "key")
+	assert.Assert(t, ok)
+	cache.Set("check-images", "ghcr.io/kyverno/test:v3", "key", "sha256:3")
+
+	assert.Equal(t, cache.Len(), 2)
+	_, ok = cache.Get("check-images", "ghcr.io/kyverno/test:v2", "key")
+	assert.Assert(t, !ok)
+	_, ok = cache.Get("check-images", "ghcr.io/kyverno/test:v1", "key")
+	assert.Assert(t, ok)
+}
+
+func Test_Cache_Expiration(t *testing.T) {
+	cache := NewCache(10, time.Minute, nil)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.Set("check-images", "ghcr.io/kyverno/test:v1", "key", "sha256:1")
+
+	now = now.Add(30 * time.Second)
+	_, ok := cache.Get("check-images", "ghcr.io/kyverno/test:v1", "key")
+	assert.Assert(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = cache.Get("check-images", "ghcr.io/kyverno/test:v1", "key")
+	assert.Assert(t, !ok)
+	assert.Equal(t, cache.Len(), 0)
+}
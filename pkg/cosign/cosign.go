@@ -77,6 +77,21 @@ func Verify(imageRef string, key []byte, log logr.Logger) (digest string, err er
 	return digest, nil
 }
 
+// FetchImageDigest returns the digest of the image manifest with a HEAD request to the registry
+func FetchImageDigest(imageRef string) (string, error) {
+	ref, err := name.ParseReference(imageRef)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse image")
+	}
+
+	desc, err := remote.Head(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fetch image digest")
+	}
+
+	return desc.Digest.String(), nil
+}
+
 func decodePEM(raw []byte) (signature.Verifier, error) {
 	// PEM encoded file.
 	ed, err := cosign.PemToECDSAKey(raw)
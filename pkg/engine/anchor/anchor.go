@@ -22,6 +22,8 @@ func CreateElementHandler(element string, pattern interface{}, path string) Vali
 	switch {
 	case commonAnchors.IsConditionAnchor(element):
 		return NewConditionAnchorHandler(element, pattern, path)
+	case commonAnchors.IsGlobalAnchor(element):
+		return NewGlobalAnchorHandler(element, pattern, path)
 	case commonAnchors.IsExistenceAnchor(element):
 		return NewExistenceHandler(element, pattern, path)
 	case commonAnchors.IsEqualityAnchor(element):
@@ -159,6 +161,38 @@ func (ch ConditionAnchorHandler) Handle(handler resourceElementHandler, resource
 	return "", nil
 }
 
+//NewGlobalAnchorHandler returns an instance of global anchor handler
+func NewGlobalAnchorHandler(anchor string, pattern interface{}, path string) ValidationHandler {
+	return GlobalAnchorHandler{
+		anchor:  anchor,
+		pattern: pattern,
+		path:    path,
+	}
+}
+
+//GlobalAnchorHandler provides handler for global condition anchor
+type GlobalAnchorHandler struct {
+	anchor  string
+	pattern interface{}
+	path    string
+}
+
+//Handle processes global condition anchor
+func (gh GlobalAnchorHandler) Handle(handler resourceElementHandler, resourceMap map[string]interface{}, originPattern interface{}, ac *common.AnchorKey) (string, error) {
+	anchorKey, _ := commonAnchors.RemoveAnchor(gh.anchor)
+	currentPath := gh.path + anchorKey + "/"
+	// check if anchor is present in resource
+	if value, ok := resourceMap[anchorKey]; ok {
+		// validate the values of the pattern
+		returnPath, err := handler(log.Log, value, gh.pattern, originPattern, currentPath, ac)
+		if err != nil {
+			return returnPath, err
+		}
+		return "", nil
+	}
+	return "", nil
+}
+
 //NewExistenceHandler returns existence handler
 func NewExistenceHandler(anchor string, pattern interface{}, path string) ValidationHandler {
 	return ExistenceHandler{
@@ -230,7 +264,7 @@ func GetAnchorsResourcesFromMap(patternMap map[string]interface{}) (map[string]i
 	anchors := map[string]interface{}{}
 	resources := map[string]interface{}{}
 	for key, value := range patternMap {
-		if commonAnchors.IsConditionAnchor(key) || commonAnchors.IsExistenceAnchor(key) || commonAnchors.IsEqualityAnchor(key) || commonAnchors.IsNegationAnchor(key) {
+		if commonAnchors.IsConditionAnchor(key) || commonAnchors.IsExistenceAnchor(key) || commonAnchors.IsEqualityAnchor(key) || commonAnchors.IsNegationAnchor(key) || commonAnchors.IsGlobalAnchor(key) {
 			anchors[key] = value
 			continue
 		}
@@ -57,6 +57,24 @@ func TestIsExistenceAnchor_ConditionAnchor(t *testing.T) {
 	assert.Assert(t, !IsExistenceAnchor("(abc)"))
 }
 
+func TestIsGlobalAnchor_Yes(t *testing.T) {
+	assert.Assert(t, IsGlobalAnchor("<(abc)"))
+}
+
+func TestIsGlobalAnchor_NoRightBracket(t *testing.T) {
+	assert.Assert(t, !IsGlobalAnchor("<(abc"))
+}
+
+func TestIsGlobalAnchor_ConditionAnchor(t *testing.T) {
+	assert.Assert(t, !IsGlobalAnchor("(abc)"))
+}
+
+func TestRemoveAnchor_GlobalAnchor(t *testing.T) {
+	key, prefix := RemoveAnchor("<(image)")
+	assert.Equal(t, key, "image")
+	assert.Equal(t, prefix, "<(")
+}
+
 func TestRemoveAnchorsFromPath_WorksWithAbsolutePath(t *testing.T) {
 	newPath := RemoveAnchorsFromPath("/path/(to)/X(anchors)")
 	assert.Equal(t, newPath, "/path/to/anchors")
@@ -16,6 +16,19 @@ func IsConditionalAnchorError(msg string) bool {
 	return false
 }
 
+// IsGlobalAnchorError checks if error message has global anchor error string
+func IsGlobalAnchorError(msg string) bool {
+	return strings.Contains(msg, GlobalAnchorErrMsg)
+}
+
+// NewGlobalAnchorError returns a new instance of GlobalAnchorError
+func NewGlobalAnchorError(msg string) ValidateAnchorError {
+	return ValidateAnchorError{
+		Err:     GlobalAnchorErr,
+		Message: fmt.Sprintf("%s: %s", GlobalAnchorErrMsg, msg),
+	}
+}
+
 // NewConditionalAnchorError returns a new instance of ConditionalAnchorError
 func NewConditionalAnchorError(msg string) ValidateAnchorError {
 	return ValidateAnchorError{
@@ -32,6 +45,11 @@ func (e ValidateAnchorError) IsConditionAnchorError() bool {
 	return false
 }
 
+// IsGlobalAnchorError ...
+func (e ValidateAnchorError) IsGlobalAnchorError() bool {
+	return e.Err == GlobalAnchorErr
+}
+
 // IsNil ...
 func (e ValidateAnchorError) IsNil() bool {
 	return e == ValidateAnchorError{}
@@ -44,8 +62,13 @@ func (e ValidateAnchorError) Error() error {
 // AnchorError is the const specification of anchor errors
 type AnchorError int
 
-// ConditionalAnchorErr ...
-const ConditionalAnchorErr AnchorError = iota
+const (
+	// ConditionalAnchorErr refers to condition violation
+	ConditionalAnchorErr AnchorError = iota
+
+	// GlobalAnchorErr refers to global condition violation
+	GlobalAnchorErr
+)
 
 // ValidateAnchorError represents the error type of validation anchors
 type ValidateAnchorError struct {
@@ -56,6 +79,9 @@ type ValidateAnchorError struct {
 // ConditionalAnchorErrMsg - the error message for conditional anchor error
 var ConditionalAnchorErrMsg = "conditionalAnchorError"
 
+// GlobalAnchorErrMsg - the error message for global anchor error
+var GlobalAnchorErrMsg = "globalAnchorError"
+
 // AnchorKey - contains map of anchors
 type AnchorKey struct {
 	// anchorMap - for each anchor key in the patterns it will maintains information if the key exists in the resource
@@ -87,7 +113,7 @@ func (ac *AnchorKey) CheckAnchorInResource(pattern interface{}, resource interfa
 	switch typed := pattern.(type) {
 	case map[string]interface{}:
 		for key := range typed {
-			if common.IsConditionAnchor(key) || common.IsExistenceAnchor(key) || common.IsNegationAnchor(key) {
+			if common.IsConditionAnchor(key) || common.IsExistenceAnchor(key) || common.IsNegationAnchor(key) || common.IsGlobalAnchor(key) {
 				val, ok := ac.anchorMap[key]
 				if !ok {
 					ac.anchorMap[key] = false
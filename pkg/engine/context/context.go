@@ -98,12 +98,19 @@ func (ctx *Context) AddJSON(dataRaw []byte) error {
 	return nil
 }
 
+// requestWithSubresource always contains the subResource of an admission request,
+// so that request.subResource can be referenced in requests for resources
+type requestWithSubresource struct {
+	*v1beta1.AdmissionRequest
+	SubResource string `json:"subResource"`
+}
+
 // AddRequest adds an admission request to context
 func (ctx *Context) AddRequest(request *v1beta1.AdmissionRequest) error {
 	modifiedResource := struct {
 		Request interface{} `json:"request"`
 	}{
-		Request: request,
+		Request: requestWithSubresource{AdmissionRequest: request, SubResource: request.SubResource},
 	}
 
 	objRaw, err := json.Marshal(modifiedResource)
@@ -306,6 +313,28 @@ func (ctx *Context) ImageInfo() *Images {
 	return ctx.images
 }
 
+// Copy returns a copy of the context, changes to the copy do not affect the original context
+func (ctx *Context) Copy() *Context {
+	ctx.mutex.RLock()
+	defer ctx.mutex.RUnlock()
+
+	newCtx := Context{
+		jsonRaw:     make([]byte, len(ctx.jsonRaw)),
+		builtInVars: make([]string, len(ctx.builtInVars)),
+		images:      ctx.images,
+		log:         ctx.log,
+	}
+
+	copy(newCtx.jsonRaw, ctx.jsonRaw)
+	copy(newCtx.builtInVars, ctx.builtInVars)
+	if ctx.jsonRawCheckpoint != nil {
+		newCtx.jsonRawCheckpoint = make([]byte, len(ctx.jsonRawCheckpoint))
+		copy(newCtx.jsonRawCheckpoint, ctx.jsonRawCheckpoint)
+	}
+
+	return &newCtx
+}
+
 // Checkpoint creates a copy of the internal state.
 // Prior checkpoints will be overridden.
 func (ctx *Context) Checkpoint() {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"k8s.io/api/admission/v1beta1"
 	authenticationv1 "k8s.io/api/authentication/v1"
 )
 
@@ -118,3 +119,67 @@ func Test_addResourceAndUserContext(t *testing.T) {
 		t.Error("exected result does not match")
 	}
 }
+
+func Test_Copy(t *testing.T) {
+	ctx := NewContext()
+	if err := ctx.AddJSON([]byte(`{"request": {"operation": "CREATE"}}`)); err != nil {
+		t.Error(err)
+	}
+
+	ctxCopy := ctx.Copy()
+	if err := ctxCopy.AddJSON([]byte(`{"request": {"operation": "UPDATE"}}`)); err != nil {
+		t.Error(err)
+	}
+
+	result, err := ctx.Query("request.operation")
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual("CREATE", result) {
+		t.Errorf("expected the original context to be unchanged, got %v", result)
+	}
+
+	result, err = ctxCopy.Query("request.operation")
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual("UPDATE", result) {
+		t.Errorf("expected the copy to be updated, got %v", result)
+	}
+}
+
+func Test_AddRequest_SubResource(t *testing.T) {
+	ctx := NewContext()
+	if err := ctx.AddRequest(&v1beta1.AdmissionRequest{Operation: v1beta1.Create}); err != nil {
+		t.Error(err)
+	}
+
+	result, err := ctx.Query("request.subResource")
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual("", result) {
+		t.Errorf("expected an empty subresource, got %v", result)
+	}
+
+	ctx = NewContext()
+	if err := ctx.AddRequest(&v1beta1.AdmissionRequest{Operation: v1beta1.Connect, SubResource: "exec"}); err != nil {
+		t.Error(err)
+	}
+
+	result, err = ctx.Query("request.subResource")
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual("exec", result) {
+		t.Errorf("expected subresource exec, got %v", result)
+	}
+
+	result, err = ctx.Query("request.operation")
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual("CONNECT", result) {
+		t.Errorf("expected operation CONNECT, got %v", result)
+	}
+}
@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	configMapContextEntry = "configMap"
+	apiCallContextEntry   = "apiCall"
+)
+
+var (
+	contextLoadDuration *prom.HistogramVec
+	contextLoadErrors   *prom.CounterVec
+)
+
+// InitializeContextMetrics sets the metrics recording the latency and the errors of the ConfigMap
+// and APICall context entries, the metrics are not recorded when they are nil
+func InitializeContextMetrics(duration *prom.HistogramVec, errors *prom.CounterVec) {
+	contextLoadDuration = duration
+	contextLoadErrors = errors
+}
+
+func recordContextLoad(entryType string, startTime time.Time, err error) {
+	labels := prom.Labels{"context_entry_type": entryType}
+	if contextLoadDuration != nil {
+		contextLoadDuration.With(labels).Observe(time.Since(startTime).Seconds())
+	}
+
+	if err != nil && contextLoadErrors != nil {
+		contextLoadErrors.With(labels).Inc()
+	}
+}
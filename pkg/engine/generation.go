@@ -15,6 +15,9 @@ import (
 // 2. returns the list of rules that are applicable on this policy and resource, if 1 succeed
 func Generate(policyContext *PolicyContext) (resp *response.EngineResponse) {
 	policyStartTime := time.Now()
+	endSpan := startPolicySpan(policyContext, "engine.generate")
+	defer func() { endSpan(resp) }()
+
 	return filterRules(policyContext, policyStartTime)
 }
 
@@ -46,9 +49,18 @@ func filterRules(policyContext *PolicyContext, startTime time.Time) *response.En
 		return resp
 	}
 
+	rules := newRuleSpans(policyContext)
+	defer rules.end()
+
 	for _, rule := range policyContext.Policy.Spec.Rules {
+		if !rule.HasGenerate() {
+			continue
+		}
+
+		rules.start(rule.Name)
 		if ruleResp := filterRule(rule, policyContext); ruleResp != nil {
 			resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, *ruleResp)
+			rules.setResult(*ruleResp)
 		}
 	}
 
@@ -75,10 +87,10 @@ func filterRule(rule kyverno.Rule, policyContext *PolicyContext) *response.RuleR
 	logger := log.Log.WithName("Generate").WithValues("policy", policy.Name,
 		"kind", newResource.GetKind(), "namespace", newResource.GetNamespace(), "name", newResource.GetName())
 
-	if err = MatchesResourceDescription(newResource, rule, admissionInfo, excludeGroupRole, namespaceLabels); err != nil {
+	if err = MatchesResourceDescription(newResource, rule, admissionInfo, excludeGroupRole, namespaceLabels, policyContext.Subresource); err != nil {
 
 		// if the oldResource matched, return "false" to delete GR for it
-		if err = MatchesResourceDescription(oldResource, rule, admissionInfo, excludeGroupRole, namespaceLabels); err == nil {
+		if err = MatchesResourceDescription(oldResource, rule, admissionInfo, excludeGroupRole, namespaceLabels, policyContext.Subresource); err == nil {
 			return &response.RuleResponse{
 				Name:    rule.Name,
 				Type:    "Generation",
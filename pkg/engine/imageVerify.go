@@ -9,6 +9,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/engine/response"
 	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/tracing"
 	"github.com/minio/minio/pkg/wildcard"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"time"
@@ -33,20 +34,28 @@ func VerifyAndPatchImages(policyContext *PolicyContext) (resp *response.EngineRe
 	}
 
 	startTime := time.Now()
+	endSpan := startPolicySpan(policyContext, "engine.verifyImages")
+	logger = tracing.Logger(policyContext.TraceContext, logger)
 	defer func() {
 		buildResponse(logger, policyContext, resp, startTime)
+		endSpan(resp)
 		logger.V(4).Info("finished policy processing", "processingTime", resp.PolicyResponse.ProcessingTime.String(), "rulesApplied", resp.PolicyResponse.RulesAppliedCount)
 	}()
 
 	policyContext.JSONContext.Checkpoint()
 	defer policyContext.JSONContext.Restore()
 
+	rules := newRuleSpans(policyContext)
+	defer rules.end()
+
 	for i := range policyContext.Policy.Spec.Rules {
 		rule := policyContext.Policy.Spec.Rules[i]
 		if len(rule.VerifyImages) == 0 {
 			continue
 		}
 
+		rules.start(rule.Name)
+
 		if !matches(logger, rule, policyContext) {
 			continue
 		}
@@ -56,6 +65,12 @@ func VerifyAndPatchImages(policyContext *PolicyContext) (resp *response.EngineRe
 			verifyAndPatchImages(logger, policyContext, &rule, imageVerify, images.Containers, resp)
 			verifyAndPatchImages(logger, policyContext, &rule, imageVerify, images.InitContainers, resp)
 		}
+
+		for _, ruleResp := range resp.PolicyResponse.Rules {
+			if ruleResp.Name == rule.Name {
+				rules.setResult(ruleResp)
+			}
+		}
 	}
 
 	return
@@ -88,7 +103,10 @@ func verifyAndPatchImages(logger logr.Logger, policyContext *PolicyContext, rule
 		}
 
 		start := time.Now()
-		digest, err := cosign.Verify(image, []byte(key), logger)
+		_, span := tracing.StartSpan(policyContext.TraceContext, "cosign.verify", tracing.ImageKey.String(image))
+		digest, err := verifyImage(policyContext.Policy, imageInfo, key, logger)
+		tracing.SetError(span, err)
+		span.End()
 		if err != nil {
 			logger.Info("failed to verify image", "image", image, "key", key, "error", err, "duration", time.Since(start).Seconds())
 			ruleResp.Success = false
@@ -99,7 +117,7 @@ func verifyAndPatchImages(logger logr.Logger, policyContext *PolicyContext, rule
 			ruleResp.Message = fmt.Sprintf("image %s verified", image)
 
 			// add digest to image
-			if imageInfo.Digest == "" {
+			if imageInfo.Digest == "" && imageVerify.MutateDigestEnabled() {
 				patch, err := makeAddDigestPatch(imageInfo, digest)
 				if err != nil {
 					logger.Error(err, "failed to patch image with digest", "image", imageInfo.String(), "jsonPath", imageInfo.JSONPointer)
@@ -114,6 +132,56 @@ func verifyAndPatchImages(logger logr.Logger, policyContext *PolicyContext, rule
 	}
 }
 
+// verifySignature and fetchImageDigest call the registry, they are replaced in tests
+var (
+	verifySignature  = cosign.Verify
+	fetchImageDigest = cosign.FetchImageDigest
+)
+
+// verifyImage verifies the image signature and returns the image digest. The verifications are cached
+// by policy, image digest and key to avoid verifying images against the registry on every admission
+// request. An image referenced by tag is resolved to its digest first, as the tag can be moved to an
+// image that is not signed, and the digest is verified.
+func verifyImage(policy v1.ClusterPolicy, imageInfo *context.ImageInfo, key string, logger logr.Logger) (string, error) {
+	image := imageInfo.String()
+	cache := cosign.DefaultCache
+	if cache == nil {
+		return verifySignature(image, []byte(key), logger)
+	}
+
+	digest := imageInfo.Digest
+	if digest == "" {
+		var err error
+		if digest, err = fetchImageDigest(image); err != nil {
+			logger.V(3).Info("failed to resolve image digest, verifying image without cache", "image", image, "error", err.Error())
+			return verifySignature(image, []byte(key), logger)
+		}
+	}
+
+	policyKey := policy.GetName()
+	if policy.GetNamespace() != "" {
+		policyKey = policy.GetNamespace() + "/" + policyKey
+	}
+
+	imageRef := imageInfo.Registry + "/" + imageInfo.Path + "@" + digest
+	if _, ok := cache.Get(policyKey, imageRef, key); ok {
+		logger.V(4).Info("image verification found in cache", "image", image, "digest", digest)
+		return digest, nil
+	}
+
+	verifiedDigest, err := verifySignature(imageRef, []byte(key), logger)
+	if err != nil {
+		return "", err
+	}
+
+	if verifiedDigest != digest {
+		return "", fmt.Errorf("verified digest %s does not match image digest %s", verifiedDigest, digest)
+	}
+
+	cache.Set(policyKey, imageRef, key, digest)
+	return digest, nil
+}
+
 func makeAddDigestPatch(imageInfo *context.ImageInfo, digest string) ([]byte, error) {
 	var patch = make(map[string]interface{})
 	patch["op"] = "replace"
@@ -0,0 +1,145 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/cosign"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"gotest.tools/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var testVerifyImagesPolicy = []byte(`{
+	"apiVersion": "kyverno.io/v1",
+	"kind": "ClusterPolicy",
+	"metadata": {"name": "check-image"},
+	"spec": {
+		"validationFailureAction": "audit",
+		"rules": [
+			{
+				"name": "check-image",
+				"match": {"resources": {"kinds": ["Pod"]}},
+				"verifyImages": [
+					{
+						"image": "ghcr.io/kyverno/test-verify-image:*",
+						"key": "-----BEGIN PUBLIC KEY-----\nMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE8nXRh950IZbRj8Ra/N9sbqOPZrfM\n5/KAQN0/KjHcorm/J5yctVd7iEcnessRQjU917hmKO6JWVGHpDguIyakZA==\n-----END PUBLIC KEY-----"
+					}
+				]
+			}
+		]
+	}
+}`)
+
+const testVerifyImagesDigest = "sha256:ee53528c4e5f5cbd9a4c8e8b1b6d8d6a1f3e0a4c4a3a4b1d2c3e4f5a6b7c8d9e"
+
+func buildVerifyImagesResource(image string) []byte {
+	return []byte(`{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {"name": "test", "namespace": "default"},
+		"spec": {"containers": [{"name": "test", "image": "` + image + `"}]}
+	}`)
+}
+
+func buildVerifyImagesContext(t *testing.T, policy kyverno.ClusterPolicy, image string) *PolicyContext {
+	rawResource := buildVerifyImagesResource(image)
+	resource, err := utils.ConvertToUnstructured(rawResource)
+	assert.NilError(t, err)
+
+	ctx := context.NewContext()
+	err = ctx.AddResource(rawResource)
+	assert.NilError(t, err)
+	err = ctx.AddImageInfo(resource)
+	assert.NilError(t, err)
+
+	return &PolicyContext{Policy: policy, NewResource: *resource, JSONContext: ctx}
+}
+
+// fakeRegistry replaces the registry calls of the image verification
+type fakeRegistry struct {
+	digest        string
+	verifications []string
+}
+
+func (r *fakeRegistry) install(t *testing.T) {
+	verify, fetch := verifySignature, fetchImageDigest
+	t.Cleanup(func() { verifySignature, fetchImageDigest = verify, fetch })
+
+	verifySignature = func(image string, key []byte, logger logr.Logger) (string, error) {
+		r.verifications = append(r.verifications, image)
+		return r.digest, nil
+	}
+	fetchImageDigest = func(image string) (string, error) {
+		return r.digest, nil
+	}
+}
+
+func Test_VerifyAndPatchImages_Cache(t *testing.T) {
+	var policy kyverno.ClusterPolicy
+	err := json.Unmarshal(testVerifyImagesPolicy, &policy)
+	assert.NilError(t, err)
+
+	registry := &fakeRegistry{digest: testVerifyImagesDigest}
+	registry.install(t)
+
+	defer func(cache *cosign.Cache) { cosign.DefaultCache = cache }(cosign.DefaultCache)
+	cosign.DefaultCache = cosign.NewCache(10, time.Minute, nil)
+
+	// the images referenced by tag are verified once by digest
+	for i := 0; i < 3; i++ {
+		er := VerifyAndPatchImages(buildVerifyImagesContext(t, policy, "ghcr.io/kyverno/test-verify-image:signed"))
+		assert.Equal(t, len(er.PolicyResponse.Rules), 1)
+		assert.Assert(t, er.PolicyResponse.Rules[0].Success)
+		assert.Equal(t, er.PolicyResponse.ValidationFailureAction, "audit")
+		assert.Equal(t, len(er.GetPatches()), 1)
+	}
+
+	// the images referenced by digest share the cached verification and are not patched
+	er := VerifyAndPatchImages(buildVerifyImagesContext(t, policy, "ghcr.io/kyverno/test-verify-image:signed@"+testVerifyImagesDigest))
+	assert.Equal(t, len(er.PolicyResponse.Rules), 1)
+	assert.Assert(t, er.PolicyResponse.Rules[0].Success)
+	assert.Equal(t, len(er.GetPatches()), 0)
+
+	assert.DeepEqual(t, registry.verifications, []string{"ghcr.io/kyverno/test-verify-image@" + testVerifyImagesDigest})
+}
+
+func Test_verifyImage_TagMoved(t *testing.T) {
+	var policy kyverno.ClusterPolicy
+	err := json.Unmarshal(testVerifyImagesPolicy, &policy)
+	assert.NilError(t, err)
+
+	registry := &fakeRegistry{digest: testVerifyImagesDigest}
+	registry.install(t)
+
+	defer func(cache *cosign.Cache) { cosign.DefaultCache = cache }(cosign.DefaultCache)
+	cosign.DefaultCache = cosign.NewCache(10, time.Minute, nil)
+
+	imageInfo := &context.ImageInfo{Registry: "ghcr.io", Name: "test-verify-image", Path: "kyverno/test-verify-image", Tag: "signed"}
+	digest, err := verifyImage(policy, imageInfo, "key", log.Log)
+	assert.NilError(t, err)
+	assert.Equal(t, digest, testVerifyImagesDigest)
+
+	// the tag is moved to another image, which is verified again
+	movedDigest := "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	registry.digest = movedDigest
+	digest, err = verifyImage(policy, imageInfo, "key", log.Log)
+	assert.NilError(t, err)
+	assert.Equal(t, digest, movedDigest)
+
+	assert.DeepEqual(t, registry.verifications, []string{
+		"ghcr.io/kyverno/test-verify-image@" + testVerifyImagesDigest,
+		"ghcr.io/kyverno/test-verify-image@" + movedDigest,
+	})
+}
+
+func Test_makeAddDigestPatch(t *testing.T) {
+	imageInfo := &context.ImageInfo{Registry: "ghcr.io", Name: "test-verify-image", Path: "kyverno/test-verify-image", Tag: "signed", JSONPointer: "/spec/containers/0/image"}
+	patch, err := makeAddDigestPatch(imageInfo, testVerifyImagesDigest)
+	assert.NilError(t, err)
+	assert.Equal(t, string(patch), `{"op":"replace","path":"/spec/containers/0/image","value":"ghcr.io/kyverno/test-verify-image:signed@`+testVerifyImagesDigest+`"}`)
+}
@@ -0,0 +1,271 @@
+package jmespath
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"gopkg.in/inf.v0"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type operandKind int
+
+const (
+	numberOperand operandKind = iota
+	quantityOperand
+	durationOperand
+)
+
+// operand is an argument of an arithmetic function, it is either a number,
+// a resource quantity (e.g. "512Mi") or a duration (e.g. "1h30m")
+type operand struct {
+	kind     operandKind
+	number   float64
+	quantity resource.Quantity
+	duration time.Duration
+}
+
+// parsedArgument holds all the interpretations of an argument of an arithmetic function
+type parsedArgument struct {
+	number   *float64
+	quantity *resource.Quantity
+	duration *time.Duration
+}
+
+func parseArgument(f string, arguments []interface{}, index int) (parsedArgument, error) {
+	switch typed := arguments[index].(type) {
+	case float64:
+		return parsedArgument{number: &typed}, nil
+	case string:
+		var arg parsedArgument
+		if n, err := strconv.ParseFloat(typed, 64); err == nil {
+			arg.number = &n
+			return arg, nil
+		}
+
+		if q, err := resource.ParseQuantity(typed); err == nil {
+			arg.quantity = &q
+		}
+
+		if d, err := time.ParseDuration(typed); err == nil {
+			arg.duration = &d
+		}
+
+		if arg.quantity == nil && arg.duration == nil {
+			return arg, fmt.Errorf(genericError, f, fmt.Sprintf("%d argument '%s' is not a number, a quantity or a duration", index+1, typed))
+		}
+
+		return arg, nil
+	default:
+		return parsedArgument{}, fmt.Errorf(invalidArgumentTypeError, f, index+1, "Number or String")
+	}
+}
+
+// toOperand picks the interpretation of an argument given the other argument,
+// strings that are both a quantity and a duration (e.g. "500m") are treated as quantities
+// unless the other argument is a duration
+func (arg parsedArgument) toOperand(other parsedArgument) operand {
+	switch {
+	case arg.number != nil:
+		return operand{kind: numberOperand, number: *arg.number}
+	case arg.quantity != nil && (arg.duration == nil || other.number != nil || other.quantity != nil):
+		return operand{kind: quantityOperand, quantity: *arg.quantity}
+	default:
+		return operand{kind: durationOperand, duration: *arg.duration}
+	}
+}
+
+func (o operand) asQuantity() resource.Quantity {
+	if o.kind == quantityOperand {
+		return o.quantity
+	}
+
+	return *decToQuantity(floatToDec(o.number), resource.DecimalSI)
+}
+
+func floatToDec(f float64) *inf.Dec {
+	d, _ := new(inf.Dec).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	return d
+}
+
+// decToQuantity converts a decimal to a quantity, the format is used when rendering the result (e.g. "1Gi")
+func decToQuantity(d *inf.Dec, format resource.Format) *resource.Quantity {
+	// adding to an empty quantity drops the string cached by the parser, so that the format applies
+	q := resource.Quantity{}
+	q.Add(resource.MustParse(d.String()))
+	q.Format = format
+	return &q
+}
+
+func decToFloat(d *inf.Dec) float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+func parseOperands(f string, arguments []interface{}) (operand, operand, error) {
+	a, err := parseArgument(f, arguments, 0)
+	if err != nil {
+		return operand{}, operand{}, err
+	}
+
+	b, err := parseArgument(f, arguments, 1)
+	if err != nil {
+		return operand{}, operand{}, err
+	}
+
+	return a.toOperand(b), b.toOperand(a), nil
+}
+
+func incompatibleOperands(f string, a, b operand) error {
+	return fmt.Errorf(genericError, f, fmt.Sprintf("cannot combine %s and %s", a.kind, b.kind))
+}
+
+func (k operandKind) String() string {
+	switch k {
+	case quantityOperand:
+		return "quantity"
+	case durationOperand:
+		return "duration"
+	default:
+		return "number"
+	}
+}
+
+func jpAdd(arguments []interface{}) (interface{}, error) {
+	a, b, err := parseOperands(add, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return sum(add, a, b, false)
+}
+
+func jpSubtract(arguments []interface{}) (interface{}, error) {
+	a, b, err := parseOperands(subtract, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return sum(subtract, a, b, true)
+}
+
+// sum adds (or subtracts) two operands, numbers are promoted to quantities when combined with a quantity
+func sum(f string, a, b operand, negate bool) (interface{}, error) {
+	switch {
+	case a.kind == numberOperand && b.kind == numberOperand:
+		if negate {
+			return a.number - b.number, nil
+		}
+		return a.number + b.number, nil
+	case a.kind == durationOperand && b.kind == durationOperand:
+		if negate {
+			return (a.duration - b.duration).String(), nil
+		}
+		return (a.duration + b.duration).String(), nil
+	case a.kind != durationOperand && b.kind != durationOperand:
+		q, other := a.asQuantity(), b.asQuantity()
+		if negate {
+			q.Sub(other)
+		} else {
+			q.Add(other)
+		}
+		return q.String(), nil
+	default:
+		return nil, incompatibleOperands(f, a, b)
+	}
+}
+
+func jpMultiply(arguments []interface{}) (interface{}, error) {
+	a, b, err := parseOperands(multiply, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep the number as the second operand
+	if a.kind == numberOperand && b.kind != numberOperand {
+		a, b = b, a
+	}
+
+	if b.kind != numberOperand {
+		return nil, incompatibleOperands(multiply, a, b)
+	}
+
+	switch a.kind {
+	case quantityOperand:
+		product := new(inf.Dec).Mul(a.quantity.AsDec(), floatToDec(b.number))
+		return decToQuantity(product, a.quantity.Format).String(), nil
+	case durationOperand:
+		return time.Duration(float64(a.duration) * b.number).String(), nil
+	default:
+		return a.number * b.number, nil
+	}
+}
+
+func jpDivide(arguments []interface{}) (interface{}, error) {
+	a, b, err := parseOperands(divide, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	if isZero(b) {
+		return nil, fmt.Errorf(genericError, divide, "division by zero")
+	}
+
+	switch {
+	case a.kind == numberOperand && b.kind == numberOperand:
+		return a.number / b.number, nil
+	case a.kind == durationOperand && b.kind == durationOperand:
+		return float64(a.duration) / float64(b.duration), nil
+	case a.kind == durationOperand && b.kind == numberOperand:
+		return time.Duration(float64(a.duration) / b.number).String(), nil
+	case a.kind == quantityOperand && b.kind == numberOperand:
+		quotient := new(inf.Dec).QuoRound(a.quantity.AsDec(), floatToDec(b.number), 9, inf.RoundHalfUp)
+		return decToQuantity(quotient, a.quantity.Format).String(), nil
+	case a.kind != durationOperand && b.kind != durationOperand:
+		q, other := a.asQuantity(), b.asQuantity()
+		return decToFloat(new(inf.Dec).QuoRound(q.AsDec(), other.AsDec(), 9, inf.RoundHalfUp)), nil
+	default:
+		return nil, incompatibleOperands(divide, a, b)
+	}
+}
+
+func jpModulo(arguments []interface{}) (interface{}, error) {
+	a, b, err := parseOperands(modulo, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	if isZero(b) {
+		return nil, fmt.Errorf(genericError, modulo, "division by zero")
+	}
+
+	switch {
+	case a.kind == numberOperand && b.kind == numberOperand:
+		if a.number != math.Trunc(a.number) || b.number != math.Trunc(b.number) {
+			return nil, fmt.Errorf(genericError, modulo, "arguments must be integers")
+		}
+		return float64(int64(a.number) % int64(b.number)), nil
+	case a.kind == durationOperand && b.kind == durationOperand:
+		return (a.duration % b.duration).String(), nil
+	case a.kind != durationOperand && b.kind != durationOperand:
+		q, other := a.asQuantity(), b.asQuantity()
+		quotient := new(inf.Dec).QuoRound(q.AsDec(), other.AsDec(), 0, inf.RoundDown)
+		remainder := new(inf.Dec).Sub(q.AsDec(), new(inf.Dec).Mul(quotient, other.AsDec()))
+		return decToQuantity(remainder, q.Format).String(), nil
+	default:
+		return nil, incompatibleOperands(modulo, a, b)
+	}
+}
+
+func isZero(o operand) bool {
+	switch o.kind {
+	case quantityOperand:
+		return o.quantity.IsZero()
+	case durationOperand:
+		return o.duration == 0
+	default:
+		return o.number == 0
+	}
+}
@@ -1,14 +1,22 @@
 package jmespath
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"path"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/blang/semver"
 	gojmespath "github.com/jmespath/go-jmespath"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -17,6 +25,7 @@ var (
 	JpNumber      = gojmespath.JpNumber
 	JpArray       = gojmespath.JpArray
 	JpArrayString = gojmespath.JpArrayString
+	JpAny         = gojmespath.JpAny
 )
 
 type (
@@ -40,6 +49,32 @@ var (
 	regexReplaceAllLiteral = "regex_replace_all_literal"
 	regexMatch             = "regex_match"
 	labelMatch             = "label_match"
+	semverCompare          = "semver_compare"
+	timeNowUTC             = "time_now_utc"
+	timeParse              = "time_parse"
+	timeAdd                = "time_add"
+	timeSince              = "time_since"
+	timeBefore             = "time_before"
+	timeAfter              = "time_after"
+	timeBetween            = "time_between"
+	timeTruncate           = "time_truncate"
+	timeToCron             = "time_to_cron"
+	timeInWindow           = "time_in_window"
+	add                    = "add"
+	subtract               = "subtract"
+	multiply               = "multiply"
+	divide                 = "divide"
+	modulo                 = "modulo"
+	base64Decode           = "base64_decode"
+	base64Encode           = "base64_encode"
+	parseJSON              = "parse_json"
+	parseYAML              = "parse_yaml"
+	toStringUnescaped      = "to_string_unescaped"
+	quantityToNumber       = "quantity_to_number"
+	truncate               = "truncate"
+	pathCanonicalize       = "path_canonicalize"
+	items                  = "items"
+	objectFromLists        = "object_from_lists"
 )
 
 const errorPrefix = "JMESPath function '%s': "
@@ -156,6 +191,232 @@ func getFunctions() []*gojmespath.FunctionEntry {
 			},
 			Handler: jpLabelMatch,
 		},
+		{
+			// Validates if a version (param1) satisfies a semver range (param2), e.g. ">=1.0.0 <2.0.0 || >3.0.0"
+			Name: semverCompare,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpSemverCompare,
+		},
+		{
+			// Returns the current time in UTC, formatted as RFC3339
+			Name:      timeNowUTC,
+			Arguments: []ArgSpec{},
+			Handler:   jpTimeNowUTC,
+		},
+		{
+			// Parses a time (param2) with a layout (param1, RFC3339 if empty) and returns it formatted as RFC3339
+			Name: timeParse,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpTimeParse,
+		},
+		{
+			// Adds a duration (param2) to a time (param1)
+			Name: timeAdd,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpTimeAdd,
+		},
+		{
+			// Returns the duration elapsed between two times (param2 defaults to now if empty)
+			Name: timeSince,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpTimeSince,
+		},
+		{
+			// Checks if a time (param1) is before another time (param2)
+			Name: timeBefore,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpTimeBefore,
+		},
+		{
+			// Checks if a time (param1) is after another time (param2)
+			Name: timeAfter,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpTimeAfter,
+		},
+		{
+			// Checks if a time (param1) is between a start (param2, inclusive) and an end (param3, exclusive)
+			Name: timeBetween,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpTimeBetween,
+		},
+		{
+			// Rounds a time (param1) down to a multiple of a duration (param2)
+			Name: timeTruncate,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpTimeTruncate,
+		},
+		{
+			// Converts a time to a cron expression
+			Name: timeToCron,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpTimeToCron,
+		},
+		{
+			// Checks if a time (param1) is within a daily window (param2 and param3 as HH:MM),
+			// on the given days of week (param4, all days if empty)
+			Name: timeInWindow,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpArray}},
+			},
+			Handler: jpTimeInWindow,
+		},
+		{
+			// Adds two numbers, quantities (e.g. "512Mi") or durations (e.g. "1h")
+			Name: add,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpNumber, JpString}},
+				{Types: []JpType{JpNumber, JpString}},
+			},
+			Handler: jpAdd,
+		},
+		{
+			// Subtracts a number, quantity or duration (param2) from another one (param1)
+			Name: subtract,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpNumber, JpString}},
+				{Types: []JpType{JpNumber, JpString}},
+			},
+			Handler: jpSubtract,
+		},
+		{
+			// Multiplies a number, quantity or duration (param1) by a number (param2)
+			Name: multiply,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpNumber, JpString}},
+				{Types: []JpType{JpNumber, JpString}},
+			},
+			Handler: jpMultiply,
+		},
+		{
+			// Divides a number, quantity or duration (param1) by a number or a value of the same kind (param2)
+			Name: divide,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpNumber, JpString}},
+				{Types: []JpType{JpNumber, JpString}},
+			},
+			Handler: jpDivide,
+		},
+		{
+			// Returns the remainder of the division of two integers, quantities or durations
+			Name: modulo,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpNumber, JpString}},
+				{Types: []JpType{JpNumber, JpString}},
+			},
+			Handler: jpModulo,
+		},
+		{
+			Name: base64Decode,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpBase64Decode,
+		},
+		{
+			Name: base64Encode,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpBase64Encode,
+		},
+		{
+			// Parses a JSON document into an object, an array or a scalar
+			Name: parseJSON,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpParseJSON,
+		},
+		{
+			// Parses a YAML document into an object, an array or a scalar
+			Name: parseYAML,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpParseYAML,
+		},
+		{
+			// Converts a value to a string like to_string, HTML characters are not escaped
+			Name: toStringUnescaped,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpAny}},
+			},
+			Handler: jpToStringUnescaped,
+		},
+		{
+			// Converts a value to a number like to_number, quantities (e.g. "500m" or "1Gi") are converted as well
+			Name: quantityToNumber,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpAny}},
+			},
+			Handler: jpQuantityToNumber,
+		},
+		{
+			// Truncates a string (param1) to a maximum length (param2)
+			Name: truncate,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpNumber}},
+			},
+			Handler: jpTruncate,
+		},
+		{
+			// Returns the shortest equivalent of a path, e.g. "/etc//../var/" becomes "/var"
+			Name: pathCanonicalize,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpPathCanonicalize,
+		},
+		{
+			// Converts an object (or an array) into an array of {param2: key, param3: value} objects, sorted by key
+			Name: items,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpObject, JpArray}},
+				{Types: []JpType{JpString}},
+				{Types: []JpType{JpString}},
+			},
+			Handler: jpItems,
+		},
+		{
+			// Builds an object from a list of keys (param1) and a list of values (param2)
+			Name: objectFromLists,
+			Arguments: []ArgSpec{
+				{Types: []JpType{JpArray}},
+				{Types: []JpType{JpArray}},
+			},
+			Handler: jpObjectFromLists,
+		},
 	}
 
 }
@@ -385,6 +646,222 @@ func jpLabelMatch(arguments []interface{}) (interface{}, error) {
 	return true, nil
 }
 
+func jpSemverCompare(arguments []interface{}) (interface{}, error) {
+	var err error
+	v, err := validateArg(semverCompare, arguments, 0, reflect.String)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := validateArg(semverCompare, arguments, 1, reflect.String)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := semver.ParseTolerant(v.String())
+	if err != nil {
+		return nil, fmt.Errorf(genericError, semverCompare, err.Error())
+	}
+
+	versionRange, err := semver.ParseRange(r.String())
+	if err != nil {
+		return nil, fmt.Errorf(genericError, semverCompare, err.Error())
+	}
+
+	return versionRange(version), nil
+}
+
+func jpBase64Decode(arguments []interface{}) (interface{}, error) {
+	var err error
+	str, err := validateArg(base64Decode, arguments, 0, reflect.String)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(str.String())
+	if err != nil {
+		return nil, fmt.Errorf(genericError, base64Decode, err.Error())
+	}
+
+	return string(decoded), nil
+}
+
+func jpBase64Encode(arguments []interface{}) (interface{}, error) {
+	var err error
+	str, err := validateArg(base64Encode, arguments, 0, reflect.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(str.String())), nil
+}
+
+func jpParseJSON(arguments []interface{}) (interface{}, error) {
+	var err error
+	str, err := validateArg(parseJSON, arguments, 0, reflect.String)
+	if err != nil {
+		return nil, err
+	}
+
+	var output interface{}
+	if err := json.Unmarshal([]byte(str.String()), &output); err != nil {
+		return nil, fmt.Errorf(genericError, parseJSON, err.Error())
+	}
+
+	return output, nil
+}
+
+func jpParseYAML(arguments []interface{}) (interface{}, error) {
+	var err error
+	str, err := validateArg(parseYAML, arguments, 0, reflect.String)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := yaml.YAMLToJSON([]byte(str.String()))
+	if err != nil {
+		return nil, fmt.Errorf(genericError, parseYAML, err.Error())
+	}
+
+	var output interface{}
+	if err := json.Unmarshal(jsonData, &output); err != nil {
+		return nil, fmt.Errorf(genericError, parseYAML, err.Error())
+	}
+
+	return output, nil
+}
+
+func jpToStringUnescaped(arguments []interface{}) (interface{}, error) {
+	if str, ok := arguments[0].(string); ok {
+		return str, nil
+	}
+
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(arguments[0]); err != nil {
+		return nil, fmt.Errorf(genericError, toStringUnescaped, err.Error())
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+func jpQuantityToNumber(arguments []interface{}) (interface{}, error) {
+	switch typed := arguments[0].(type) {
+	case float64:
+		return typed, nil
+	case string:
+		if n, err := strconv.ParseFloat(typed, 64); err == nil {
+			return n, nil
+		}
+
+		if q, err := resource.ParseQuantity(typed); err == nil {
+			return q.AsApproximateFloat64(), nil
+		}
+
+		return nil, nil
+	default:
+		return nil, nil
+	}
+}
+
+func jpTruncate(arguments []interface{}) (interface{}, error) {
+	var err error
+	str, err := validateArg(truncate, arguments, 0, reflect.String)
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := validateArg(truncate, arguments, 1, reflect.Float64)
+	if err != nil {
+		return nil, err
+	}
+
+	if length.Float() < 0 {
+		return nil, fmt.Errorf(genericError, truncate, "length must not be negative")
+	}
+
+	runes := []rune(str.String())
+	if int(length.Float()) < len(runes) {
+		runes = runes[:int(length.Float())]
+	}
+
+	return string(runes), nil
+}
+
+func jpPathCanonicalize(arguments []interface{}) (interface{}, error) {
+	var err error
+	str, err := validateArg(pathCanonicalize, arguments, 0, reflect.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return path.Clean(str.String()), nil
+}
+
+func jpItems(arguments []interface{}) (interface{}, error) {
+	var err error
+	keyName, err := validateArg(items, arguments, 1, reflect.String)
+	if err != nil {
+		return nil, err
+	}
+
+	valueName, err := validateArg(items, arguments, 2, reflect.String)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []interface{}{}
+	switch typed := arguments[0].(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			result = append(result, map[string]interface{}{keyName.String(): key, valueName.String(): typed[key]})
+		}
+	case []interface{}:
+		for i, value := range typed {
+			result = append(result, map[string]interface{}{keyName.String(): float64(i), valueName.String(): value})
+		}
+	default:
+		return nil, fmt.Errorf(invalidArgumentTypeError, items, 1, "Object or Array")
+	}
+
+	return result, nil
+}
+
+func jpObjectFromLists(arguments []interface{}) (interface{}, error) {
+	keys, ok := arguments[0].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf(invalidArgumentTypeError, objectFromLists, 1, "Array")
+	}
+
+	values, ok := arguments[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf(invalidArgumentTypeError, objectFromLists, 2, "Array")
+	}
+
+	output := make(map[string]interface{}, len(keys))
+	for i, key := range keys {
+		k, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf(genericError, objectFromLists, fmt.Sprintf("key at index %d is not a string", i))
+		}
+
+		if i < len(values) {
+			output[k] = values[i]
+		} else {
+			output[k] = nil
+		}
+	}
+
+	return output, nil
+}
+
 // InterfaceToString casts an interface to a string type
 func ifaceToString(iface interface{}) (string, error) {
 	switch iface.(type) {
@@ -406,7 +883,7 @@ func ifaceToString(iface interface{}) (string, error) {
 func validateArg(f string, arguments []interface{}, index int, expectedType reflect.Kind) (reflect.Value, error) {
 	arg := reflect.ValueOf(arguments[index])
 	if arg.Type().Kind() != expectedType {
-		return reflect.Value{}, fmt.Errorf(invalidArgumentTypeError, equalFold, index+1, expectedType.String())
+		return reflect.Value{}, fmt.Errorf(invalidArgumentTypeError, f, index+1, expectedType.String())
 	}
 
 	return arg, nil
@@ -301,3 +301,177 @@ func Test_labelMatch(t *testing.T) {
 	}
 
 }
+
+func Test_SemverCompare(t *testing.T) {
+	testCases := []struct {
+		version        string
+		versionRange   string
+		expectedResult bool
+	}{
+		{version: "1.4.0", versionRange: ">=1.4.0", expectedResult: true},
+		{version: "1.3.9", versionRange: ">=1.4.0", expectedResult: false},
+		{version: "v1.10.2", versionRange: ">1.9.0 <2.0.0", expectedResult: true},
+		{version: "2.1.0", versionRange: ">1.9.0 <2.0.0", expectedResult: false},
+		{version: "3.0.1", versionRange: "<2.0.0 || >=3.0.0", expectedResult: true},
+		{version: "1.2", versionRange: "1.2.0", expectedResult: true},
+		{version: "1.2.0-rc1", versionRange: "<1.2.0", expectedResult: true},
+	}
+
+	for _, testCase := range testCases {
+		query, err := New("semver_compare('" + testCase.version + "', '" + testCase.versionRange + "')")
+		assert.NilError(t, err)
+
+		res, err := query.Search("")
+		assert.NilError(t, err)
+
+		result, ok := res.(bool)
+		assert.Assert(t, ok)
+		assert.Equal(t, result, testCase.expectedResult, testCase.version+" "+testCase.versionRange)
+	}
+
+	query, err := New("semver_compare('not-a-version', '>1.0.0')")
+	assert.NilError(t, err)
+	_, err = query.Search("")
+	assert.ErrorContains(t, err, "semver_compare")
+}
+
+func Test_Arithmetic(t *testing.T) {
+	testCases := []struct {
+		query          string
+		expectedResult interface{}
+	}{
+		{query: "add(`1`, `2.5`)", expectedResult: 3.5},
+		{query: "add('1Gi', '512Mi')", expectedResult: "1536Mi"},
+		{query: "add('500m', '250m')", expectedResult: "750m"},
+		{query: "add('500m', `1`)", expectedResult: "1500m"},
+		{query: "add('1h', '30m')", expectedResult: "1h30m0s"},
+		{query: "add('1m', '30s')", expectedResult: "1m30s"},
+		{query: "subtract('2Gi', '512Mi')", expectedResult: "1536Mi"},
+		{query: "subtract('1h', '90m')", expectedResult: "-30m0s"},
+		{query: "subtract(`5`, '2')", expectedResult: float64(3)},
+		{query: "multiply('512Mi', `4`)", expectedResult: "2Gi"},
+		{query: "multiply(`3`, '250m')", expectedResult: "750m"},
+		{query: "multiply('90s', `2`)", expectedResult: "3m0s"},
+		{query: "multiply(`1.5`, `2`)", expectedResult: float64(3)},
+		{query: "divide('1Gi', `4`)", expectedResult: "256Mi"},
+		{query: "divide('1Gi', '256Mi')", expectedResult: float64(4)},
+		{query: "divide('1h', '15m')", expectedResult: float64(4)},
+		{query: "divide('1h', `4`)", expectedResult: "15m0s"},
+		{query: "divide(`7`, `2`)", expectedResult: 3.5},
+		{query: "modulo(`7`, `3`)", expectedResult: float64(1)},
+		{query: "modulo('1Gi', '300Mi')", expectedResult: "124Mi"},
+		{query: "modulo('1h', '25m')", expectedResult: "10m0s"},
+	}
+
+	for _, testCase := range testCases {
+		jp, err := New(testCase.query)
+		assert.NilError(t, err)
+
+		result, err := jp.Search("")
+		assert.NilError(t, err, testCase.query)
+		assert.Equal(t, result, testCase.expectedResult, testCase.query)
+	}
+}
+
+func Test_Arithmetic_Errors(t *testing.T) {
+	testCases := []struct {
+		query         string
+		expectedError string
+	}{
+		{query: "add('1Gi', '1h')", expectedError: "cannot combine quantity and duration"},
+		{query: "add('foo', `1`)", expectedError: "is not a number, a quantity or a duration"},
+		{query: "multiply('1Gi', '1Gi')", expectedError: "cannot combine quantity and quantity"},
+		{query: "divide(`1`, `0`)", expectedError: "division by zero"},
+		{query: "divide('1h', '0s')", expectedError: "division by zero"},
+		{query: "modulo(`1.5`, `1`)", expectedError: "arguments must be integers"},
+	}
+
+	for _, testCase := range testCases {
+		jp, err := New(testCase.query)
+		assert.NilError(t, err)
+
+		_, err = jp.Search("")
+		assert.ErrorContains(t, err, testCase.expectedError)
+	}
+}
+
+func Test_SumContainerLimits(t *testing.T) {
+	data := map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"limits": map[string]interface{}{"memory": "512Mi"}},
+			map[string]interface{}{"limits": map[string]interface{}{"memory": "1Gi"}},
+		},
+	}
+
+	jp, err := New("add(containers[0].limits.memory, containers[1].limits.memory)")
+	assert.NilError(t, err)
+
+	result, err := jp.Search(data)
+	assert.NilError(t, err)
+	assert.Equal(t, result, "1536Mi")
+}
+
+func Test_EncodingFunctions(t *testing.T) {
+	testCases := []struct {
+		query          string
+		expectedResult interface{}
+	}{
+		{query: "base64_encode('kyverno')", expectedResult: "a3l2ZXJubw=="},
+		{query: "base64_decode('a3l2ZXJubw==')", expectedResult: "kyverno"},
+		{query: "parse_json('{\"a\": [1, \"b\"]}')", expectedResult: map[string]interface{}{"a": []interface{}{float64(1), "b"}}},
+		{query: "parse_json('true')", expectedResult: true},
+		{query: "parse_yaml('a:\n  b: 1\nc: [x]')", expectedResult: map[string]interface{}{"a": map[string]interface{}{"b": float64(1)}, "c": []interface{}{"x"}}},
+		{query: "to_string_unescaped(`{\"a\": \"<b>\"}`)", expectedResult: `{"a":"<b>"}`},
+		{query: "to_string_unescaped('foo')", expectedResult: "foo"},
+		{query: "to_string_unescaped(`1.5`)", expectedResult: "1.5"},
+		{query: "to_string(`{\"a\": \"<b>\"}`)", expectedResult: `{"a":"\u003cb\u003e"}`},
+		{query: "quantity_to_number('2')", expectedResult: float64(2)},
+		{query: "quantity_to_number('500m')", expectedResult: 0.5},
+		{query: "quantity_to_number('1Ki')", expectedResult: float64(1024)},
+		{query: "quantity_to_number('foo')", expectedResult: nil},
+		{query: "to_number('500m')", expectedResult: nil},
+		{query: "truncate('kyverno', `3`)", expectedResult: "kyv"},
+		{query: "truncate('kyverno', `30`)", expectedResult: "kyverno"},
+		{query: "path_canonicalize('/var//lib/../run/')", expectedResult: "/var/run"},
+		{query: "path_canonicalize('/etc/./../../etc/passwd')", expectedResult: "/etc/passwd"},
+		{
+			query:          "items(`{\"b\": 2, \"a\": 1}`, 'key', 'value')",
+			expectedResult: []interface{}{map[string]interface{}{"key": "a", "value": float64(1)}, map[string]interface{}{"key": "b", "value": float64(2)}},
+		},
+		{
+			query:          "items(`[\"x\"]`, 'index', 'item')",
+			expectedResult: []interface{}{map[string]interface{}{"index": float64(0), "item": "x"}},
+		},
+		{
+			query:          "object_from_lists(`[\"a\", \"b\"]`, `[1]`)",
+			expectedResult: map[string]interface{}{"a": float64(1), "b": nil},
+		},
+	}
+
+	for _, testCase := range testCases {
+		jp, err := New(testCase.query)
+		assert.NilError(t, err, testCase.query)
+
+		result, err := jp.Search("")
+		assert.NilError(t, err, testCase.query)
+		assert.DeepEqual(t, result, testCase.expectedResult)
+	}
+}
+
+func Test_EncodingFunctions_Errors(t *testing.T) {
+	queries := []string{
+		"base64_decode('not base64!')",
+		"parse_json('{')",
+		"parse_yaml('a: [')",
+		"truncate('kyverno', `-1`)",
+		"object_from_lists(`[1]`, `[1]`)",
+	}
+
+	for _, query := range queries {
+		jp, err := New(query)
+		assert.NilError(t, err)
+
+		_, err = jp.Search("")
+		assert.Assert(t, err != nil, query)
+	}
+}
@@ -0,0 +1,262 @@
+package jmespath
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+var (
+	clockMutex sync.RWMutex
+	clock      = time.Now
+)
+
+// SetClock overrides the clock used by the time functions,
+// it is used to evaluate policies against a fixed time (e.g. in `kyverno test`)
+func SetClock(now func() time.Time) {
+	clockMutex.Lock()
+	defer clockMutex.Unlock()
+	clock = now
+}
+
+// ResetClock restores the system clock for the time functions
+func ResetClock() {
+	SetClock(time.Now)
+}
+
+func now() time.Time {
+	clockMutex.RLock()
+	defer clockMutex.RUnlock()
+	return clock().UTC()
+}
+
+// parseTime parses a RFC3339 timestamp, or the current time if the timestamp is empty
+func parseTime(f string, index int, arguments []interface{}) (time.Time, error) {
+	arg, err := validateArg(f, arguments, index, reflect.String)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if arg.String() == "" {
+		return now(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, arg.String())
+	if err != nil {
+		return time.Time{}, fmt.Errorf(genericError, f, err.Error())
+	}
+
+	return t, nil
+}
+
+func parseDuration(f string, index int, arguments []interface{}) (time.Duration, error) {
+	arg, err := validateArg(f, arguments, index, reflect.String)
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := time.ParseDuration(arg.String())
+	if err != nil {
+		return 0, fmt.Errorf(genericError, f, err.Error())
+	}
+
+	return d, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func jpTimeNowUTC(arguments []interface{}) (interface{}, error) {
+	return formatTime(now()), nil
+}
+
+func jpTimeParse(arguments []interface{}) (interface{}, error) {
+	layout, err := validateArg(timeParse, arguments, 0, reflect.String)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, err := validateArg(timeParse, arguments, 1, reflect.String)
+	if err != nil {
+		return nil, err
+	}
+
+	l := layout.String()
+	if l == "" {
+		l = time.RFC3339
+	}
+
+	t, err := time.Parse(l, ts.String())
+	if err != nil {
+		return nil, fmt.Errorf(genericError, timeParse, err.Error())
+	}
+
+	return formatTime(t), nil
+}
+
+func jpTimeAdd(arguments []interface{}) (interface{}, error) {
+	t, err := parseTime(timeAdd, 0, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := parseDuration(timeAdd, 1, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return formatTime(t.Add(d)), nil
+}
+
+func jpTimeSince(arguments []interface{}) (interface{}, error) {
+	start, err := parseTime(timeSince, 0, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := parseTime(timeSince, 1, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return end.Sub(start).String(), nil
+}
+
+func jpTimeBefore(arguments []interface{}) (interface{}, error) {
+	t1, err := parseTime(timeBefore, 0, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	t2, err := parseTime(timeBefore, 1, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return t1.Before(t2), nil
+}
+
+func jpTimeAfter(arguments []interface{}) (interface{}, error) {
+	t1, err := parseTime(timeAfter, 0, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	t2, err := parseTime(timeAfter, 1, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return t1.After(t2), nil
+}
+
+func jpTimeBetween(arguments []interface{}) (interface{}, error) {
+	t, err := parseTime(timeBetween, 0, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := parseTime(timeBetween, 1, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := parseTime(timeBetween, 2, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return !t.Before(start) && t.Before(end), nil
+}
+
+func jpTimeTruncate(arguments []interface{}) (interface{}, error) {
+	t, err := parseTime(timeTruncate, 0, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := parseDuration(timeTruncate, 1, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return formatTime(t.Truncate(d)), nil
+}
+
+// jpTimeToCron converts a time to a cron expression (minute hour day-of-month month day-of-week),
+// e.g. to check that the current time is within a change window
+func jpTimeToCron(arguments []interface{}) (interface{}, error) {
+	t, err := parseTime(timeToCron, 0, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return fmt.Sprintf("%d %d %d %d %d", t.Minute(), t.Hour(), t.Day(), int(t.Month()), int(t.Weekday())), nil
+}
+
+// jpTimeInWindow checks if a time is within a daily window, given as HH:MM start and end times in UTC,
+// and restricted to a list of days of week (0 is Sunday) unless the list is empty
+func jpTimeInWindow(arguments []interface{}) (interface{}, error) {
+	t, err := parseTime(timeInWindow, 0, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := parseClock(timeInWindow, 1, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := parseClock(timeInWindow, 2, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	days, ok := arguments[3].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf(invalidArgumentTypeError, timeInWindow, 4, "Array")
+	}
+
+	if len(days) > 0 {
+		dayMatched := false
+		for _, d := range days {
+			day, ok := d.(float64)
+			if !ok {
+				return nil, fmt.Errorf(invalidArgumentTypeError, timeInWindow, 4, "Array of Numbers")
+			}
+			if int(day) == int(t.Weekday()) {
+				dayMatched = true
+				break
+			}
+		}
+
+		if !dayMatched {
+			return false, nil
+		}
+	}
+
+	minutes := t.Hour()*60 + t.Minute()
+	if start <= end {
+		return minutes >= start && minutes < end, nil
+	}
+
+	// window spans midnight
+	return minutes >= start || minutes < end, nil
+}
+
+// parseClock parses a HH:MM time of day and returns the minutes since midnight
+func parseClock(f string, index int, arguments []interface{}) (int, error) {
+	arg, err := validateArg(f, arguments, index, reflect.String)
+	if err != nil {
+		return 0, err
+	}
+
+	c, err := time.Parse("15:04", arg.String())
+	if err != nil {
+		return 0, fmt.Errorf(genericError, f, err.Error())
+	}
+
+	return c.Hour()*60 + c.Minute(), nil
+}
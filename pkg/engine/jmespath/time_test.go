@@ -0,0 +1,64 @@
+package jmespath
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func Test_TimeFunctions(t *testing.T) {
+	fixed, err := time.Parse(time.RFC3339, "2021-09-01T10:30:00Z")
+	assert.NilError(t, err)
+	SetClock(func() time.Time { return fixed })
+	defer ResetClock()
+
+	testCases := []struct {
+		query          string
+		expectedResult interface{}
+	}{
+		{query: "time_now_utc()", expectedResult: "2021-09-01T10:30:00Z"},
+		{query: "time_parse('', '2021-09-01T12:30:00+02:00')", expectedResult: "2021-09-01T10:30:00Z"},
+		{query: "time_parse('Mon Jan _2 15:04:05 2006', 'Wed Sep  1 10:30:00 2021')", expectedResult: "2021-09-01T10:30:00Z"},
+		{query: "time_add('2021-09-01T10:30:00Z', '36h')", expectedResult: "2021-09-02T22:30:00Z"},
+		{query: "time_add(time_now_utc(), '-1h')", expectedResult: "2021-09-01T09:30:00Z"},
+		{query: "time_since('2021-09-01T09:00:00Z', '')", expectedResult: "1h30m0s"},
+		{query: "time_since('2021-09-01T09:00:00Z', '2021-09-01T09:10:00Z')", expectedResult: "10m0s"},
+		{query: "time_before('2021-08-01T00:00:00Z', '')", expectedResult: true},
+		{query: "time_after('2021-08-01T00:00:00Z', '')", expectedResult: false},
+		{query: "time_between('', '2021-09-01T10:00:00Z', '2021-09-01T11:00:00Z')", expectedResult: true},
+		{query: "time_between('', '2021-09-01T11:00:00Z', '2021-09-01T12:00:00Z')", expectedResult: false},
+		{query: "time_truncate(time_now_utc(), '1h')", expectedResult: "2021-09-01T10:00:00Z"},
+		{query: "time_to_cron(time_now_utc())", expectedResult: "30 10 1 9 3"},
+		{query: "time_in_window('', '09:00', '17:00', `[]`)", expectedResult: true},
+		{query: "time_in_window('', '09:00', '17:00', `[1, 2, 4, 5]`)", expectedResult: false},
+		{query: "time_in_window('', '22:00', '06:00', `[]`)", expectedResult: false},
+		{query: "time_in_window('2021-09-01T23:00:00Z', '22:00', '06:00', `[3]`)", expectedResult: true},
+	}
+
+	for _, tc := range testCases {
+		jp, err := New(tc.query)
+		assert.NilError(t, err)
+
+		result, err := jp.Search("")
+		assert.NilError(t, err, tc.query)
+		assert.Equal(t, result, tc.expectedResult, tc.query)
+	}
+}
+
+func Test_TimeFunctions_InvalidArguments(t *testing.T) {
+	queries := []string{
+		"time_parse('', 'not-a-time')",
+		"time_add('2021-09-01T10:30:00Z', 'not-a-duration')",
+		"time_before('2021/09/01', '')",
+		"time_in_window('', '9am', '17:00', `[]`)",
+	}
+
+	for _, query := range queries {
+		jp, err := New(query)
+		assert.NilError(t, err)
+
+		_, err = jp.Search("")
+		assert.Assert(t, err != nil, query)
+	}
+}
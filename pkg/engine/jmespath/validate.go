@@ -0,0 +1,167 @@
+package jmespath
+
+import (
+	"fmt"
+	"reflect"
+
+	gojmespath "github.com/jmespath/go-jmespath"
+)
+
+// signature is the number and the types of the arguments of a JMESPath function
+type signature struct {
+	arguments [][]JpType
+
+	// variadic is true if the last argument can be repeated
+	variadic bool
+}
+
+// builtinSignatures are the functions of the JMESPath specification,
+// see https://jmespath.org/specification.html#built-in-functions
+var builtinSignatures = map[string]signature{
+	"abs":         {arguments: [][]JpType{{JpNumber}}},
+	"avg":         {arguments: [][]JpType{{gojmespath.JpArrayNumber}}},
+	"ceil":        {arguments: [][]JpType{{JpNumber}}},
+	"contains":    {arguments: [][]JpType{{JpArray, JpString}, {JpAny}}},
+	"ends_with":   {arguments: [][]JpType{{JpString}, {JpString}}},
+	"floor":       {arguments: [][]JpType{{JpNumber}}},
+	"join":        {arguments: [][]JpType{{JpString}, {JpArrayString}}},
+	"keys":        {arguments: [][]JpType{{JpObject}}},
+	"length":      {arguments: [][]JpType{{JpString, JpArray, JpObject}}},
+	"map":         {arguments: [][]JpType{{gojmespath.JpExpref}, {JpArray}}},
+	"max":         {arguments: [][]JpType{{gojmespath.JpArrayNumber, JpArrayString}}},
+	"max_by":      {arguments: [][]JpType{{JpArray}, {gojmespath.JpExpref}}},
+	"merge":       {arguments: [][]JpType{{JpObject}}, variadic: true},
+	"min":         {arguments: [][]JpType{{gojmespath.JpArrayNumber, JpArrayString}}},
+	"min_by":      {arguments: [][]JpType{{JpArray}, {gojmespath.JpExpref}}},
+	"not_null":    {arguments: [][]JpType{{JpAny}}, variadic: true},
+	"reverse":     {arguments: [][]JpType{{JpArray, JpString}}},
+	"sort":        {arguments: [][]JpType{{JpArrayString, gojmespath.JpArrayNumber}}},
+	"sort_by":     {arguments: [][]JpType{{JpArray}, {gojmespath.JpExpref}}},
+	"starts_with": {arguments: [][]JpType{{JpString}, {JpString}}},
+	"sum":         {arguments: [][]JpType{{gojmespath.JpArrayNumber}}},
+	"to_array":    {arguments: [][]JpType{{JpAny}}},
+	"to_number":   {arguments: [][]JpType{{JpAny}}},
+	"to_string":   {arguments: [][]JpType{{JpAny}}},
+	"type":        {arguments: [][]JpType{{JpAny}}},
+	"values":      {arguments: [][]JpType{{JpObject}}},
+}
+
+// signatures returns the signatures of the builtin and of the Kyverno functions
+func signatures() map[string]signature {
+	result := make(map[string]signature, len(builtinSignatures))
+	for name, s := range builtinSignatures {
+		result[name] = s
+	}
+
+	for _, function := range getFunctions() {
+		s := signature{arguments: make([][]JpType, len(function.Arguments))}
+		for i, argument := range function.Arguments {
+			s.arguments[i] = argument.Types
+		}
+		result[function.Name] = s
+	}
+
+	return result
+}
+
+// Validate parses a JMESPath expression and checks that every function it calls exists,
+// is called with the right number of arguments and that literal arguments have the expected type.
+// It is used to reject invalid expressions when a policy is admitted, rather than when it is applied.
+func Validate(query string) error {
+	if _, err := New(query); err != nil {
+		return err
+	}
+
+	ast, err := gojmespath.NewParser().Parse(query)
+	if err != nil {
+		return err
+	}
+
+	return validateFunctionCalls(reflect.ValueOf(ast), signatures())
+}
+
+// validateFunctionCalls walks the AST of an expression and validates the function calls, nested calls
+// included. The fields of gojmespath.ASTNode are not exported, they are read with reflection.
+func validateFunctionCalls(node reflect.Value, functions map[string]signature) error {
+	children := node.FieldByName("children")
+	if node.FieldByName("nodeType").Int() == int64(gojmespath.ASTFunctionExpression) {
+		name := node.FieldByName("value").Elem().String()
+		s, ok := functions[name]
+		if !ok {
+			return fmt.Errorf("unknown JMESPath function '%s'", name)
+		}
+
+		if err := validateFunctionCall(name, children, s); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < children.Len(); i++ {
+		if err := validateFunctionCalls(children.Index(i), functions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateFunctionCall(name string, arguments reflect.Value, s signature) error {
+	if len(s.arguments) == 0 {
+		if arguments.Len() != 0 {
+			return fmt.Errorf(genericError, name, fmt.Sprintf("expected no arguments, got %d", arguments.Len()))
+		}
+		return nil
+	}
+
+	if s.variadic {
+		if arguments.Len() < len(s.arguments) {
+			return fmt.Errorf(genericError, name, fmt.Sprintf("expected at least %d arguments, got %d", len(s.arguments), arguments.Len()))
+		}
+		return nil
+	}
+
+	if arguments.Len() != len(s.arguments) {
+		return fmt.Errorf(genericError, name, fmt.Sprintf("expected %d arguments, got %d", len(s.arguments), arguments.Len()))
+	}
+
+	for i := 0; i < arguments.Len(); i++ {
+		argument := arguments.Index(i)
+		if argument.FieldByName("nodeType").Int() != int64(gojmespath.ASTLiteral) {
+			continue
+		}
+
+		if !literalMatchesTypes(argument.FieldByName("value").Elem().Kind(), s.arguments[i]) {
+			return fmt.Errorf(invalidArgumentTypeError, name, i+1, fmt.Sprintf("%v", s.arguments[i]))
+		}
+	}
+
+	return nil
+}
+
+// literalMatchesTypes checks the kind of the value of a raw string or a JSON literal
+func literalMatchesTypes(kind reflect.Kind, types []JpType) bool {
+	for _, t := range types {
+		switch t {
+		case JpAny:
+			return true
+		case JpNumber:
+			if kind == reflect.Float64 {
+				return true
+			}
+		case JpString:
+			if kind == reflect.String {
+				return true
+			}
+		case JpArray, JpArrayString, gojmespath.JpArrayNumber:
+			if kind == reflect.Slice {
+				return true
+			}
+		case JpObject:
+			if kind == reflect.Map {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,48 @@
+package jmespath
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_Validate(t *testing.T) {
+	testCases := []struct {
+		query         string
+		expectedError string
+	}{
+		{query: "request.object.metadata.name"},
+		{query: "add(request.object.spec.containers[0].resources.limits.memory, '1Gi')"},
+		{query: "to_upper(base64_decode(request.object.data.password))"},
+		{query: "length(request.object.spec.containers[?contains(image, ':latest')])"},
+		{query: "max_by(items, &size)"},
+		{query: "not_null(a, b, c)"},
+		{query: "time_now_utc()"},
+		{query: "contains(join(',', ['a', 'b(c)']), 'x')"},
+		{query: "to_string_unescaped(`{\"a\": \"f(x, y)\"}`)"},
+		{query: "\"to_upper\"(x)", expectedError: "SyntaxError"},
+		{query: "merge(`{}`)"},
+		{query: "merge()", expectedError: "expected at least 1 arguments, got 0"},
+		{query: "starts_with('a')", expectedError: "JMESPath function 'starts_with': expected 2 arguments, got 1"},
+		{query: "request.object.metadata.[", expectedError: "SyntaxError"},
+		{query: "sum_all(request.object.spec)", expectedError: "unknown JMESPath function 'sum_all'"},
+		{query: "add('1Gi')", expectedError: "expected 2 arguments, got 1"},
+		{query: "to_upper(truncate(name, `3`, `4`))", expectedError: "JMESPath function 'truncate': expected 2 arguments, got 3"},
+		{query: "time_now_utc(`1`)", expectedError: "expected no arguments, got 1"},
+		{query: "base64_decode(`1`)", expectedError: "JMESPath function 'base64_decode': 1 argument is expected of [string] type"},
+		{query: "truncate(name, '3')", expectedError: "JMESPath function 'truncate': 2 argument is expected of [number] type"},
+		{query: "object_from_lists(`{}`, values)", expectedError: "1 argument is expected of [array] type"},
+		{query: "to_upper(`null`)", expectedError: "1 argument is expected of [string] type"},
+		{query: "items[?starts_with(name)] | [0]", expectedError: "JMESPath function 'starts_with': expected 2 arguments, got 1"},
+		{query: "{a: length(@), b: [add(x, y)]}"},
+	}
+
+	for _, testCase := range testCases {
+		err := Validate(testCase.query)
+		if testCase.expectedError == "" {
+			assert.NilError(t, err, testCase.query)
+		} else {
+			assert.ErrorContains(t, err, testCase.expectedError, testCase.query)
+		}
+	}
+}
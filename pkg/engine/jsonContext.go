@@ -4,8 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
@@ -15,6 +15,8 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine/variables"
 	"github.com/kyverno/kyverno/pkg/kyverno/store"
 	"github.com/kyverno/kyverno/pkg/resourcecache"
+	"github.com/kyverno/kyverno/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"k8s.io/client-go/dynamic/dynamiclister"
 )
 
@@ -49,13 +51,28 @@ func LoadContext(logger logr.Logger, contextEntries []kyverno.ContextEntry, resC
 
 		lister := gvrC.Lister()
 
+		spanCtx, span := tracing.StartSpan(ctx.TraceContext, "engine.loadContext", tracing.RuleKey.String(ruleName))
+		defer span.End()
+
 		for _, entry := range contextEntries {
 			if entry.ConfigMap != nil {
-				if err := loadConfigMap(logger, entry, lister, ctx.JSONContext); err != nil {
+				startTime := time.Now()
+				err := loadConfigMap(logger, entry, lister, ctx.JSONContext)
+				recordContextLoad(configMapContextEntry, startTime, err)
+				if err != nil {
+					tracing.SetError(span, err)
 					return err
 				}
 			} else if entry.APICall != nil {
-				if err := loadAPIData(logger, entry, ctx); err != nil {
+				_, apiCallSpan := tracing.StartSpan(spanCtx, "engine.apiCall",
+					tracing.ContextEntryKey.String(entry.Name), attribute.String("kyverno.apicall.urlPath", entry.APICall.URLPath))
+				startTime := time.Now()
+				err := loadAPIData(logger, entry, ctx)
+				recordContextLoad(apiCallContextEntry, startTime, err)
+				tracing.SetError(apiCallSpan, err)
+				apiCallSpan.End()
+				if err != nil {
+					tracing.SetError(span, err)
 					return err
 				}
 			}
@@ -7,10 +7,12 @@ import (
 	"github.com/go-logr/logr"
 	gojmespath "github.com/jmespath/go-jmespath"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/config"
 	"github.com/kyverno/kyverno/pkg/engine/mutate"
 	"github.com/kyverno/kyverno/pkg/engine/response"
 	"github.com/kyverno/kyverno/pkg/engine/utils"
 	"github.com/kyverno/kyverno/pkg/engine/variables"
+	"github.com/kyverno/kyverno/pkg/tracing"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -19,7 +21,7 @@ const (
 	// PodControllerCronJob represent CronJob string
 	PodControllerCronJob = "CronJob"
 	//PodControllers stores the list of Pod-controllers in csv string
-	PodControllers = "DaemonSet,Deployment,Job,StatefulSet,CronJob"
+	PodControllers = config.DefaultPodControllers
 	//PodControllersAnnotation defines the annotation key for Pod-Controllers
 	PodControllersAnnotation = "pod-policies.kyverno.io/autogen-controllers"
 )
@@ -36,6 +38,10 @@ func Mutate(policyContext *PolicyContext) (resp *response.EngineResponse) {
 	logger := log.Log.WithName("EngineMutate").WithValues("policy", policy.Name, "kind", patchedResource.GetKind(),
 		"namespace", patchedResource.GetNamespace(), "name", patchedResource.GetName())
 
+	endSpan := startPolicySpan(policyContext, "engine.mutate")
+	defer func() { endSpan(resp) }()
+	logger = tracing.Logger(policyContext.TraceContext, logger)
+
 	logger.V(4).Info("start policy processing", "startTime", startTime)
 
 	startMutateResultResponse(resp, policy, patchedResource)
@@ -52,11 +58,16 @@ func Mutate(policyContext *PolicyContext) (resp *response.EngineResponse) {
 
 	var err error
 
+	rules := newRuleSpans(policyContext)
+	defer rules.end()
+
 	for _, rule := range policy.Spec.Rules {
 		if !rule.HasMutate() {
 			continue
 		}
 
+		rules.start(rule.Name)
+
 		var ruleResponse response.RuleResponse
 		logger := logger.WithValues("rule", rule.Name)
 
@@ -65,7 +76,7 @@ func Mutate(policyContext *PolicyContext) (resp *response.EngineResponse) {
 			excludeResource = policyContext.ExcludeGroupRole
 		}
 
-		if err = MatchesResourceDescription(patchedResource, rule, policyContext.AdmissionInfo, excludeResource, policyContext.NamespaceLabels); err != nil {
+		if err = MatchesResourceDescription(patchedResource, rule, policyContext.AdmissionInfo, excludeResource, policyContext.NamespaceLabels, policyContext.Subresource); err != nil {
 			logger.V(4).Info("rule not matched", "reason", err.Error())
 			continue
 		}
@@ -123,6 +134,7 @@ func Mutate(policyContext *PolicyContext) (resp *response.EngineResponse) {
 
 			incrementAppliedCount(resp)
 			resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, ruleResp)
+			rules.setResult(ruleResp)
 
 			logger.Error(err, "failed to substitute variables, skip current rule", "rule name", ruleCopy.Name)
 			continue
@@ -145,6 +157,7 @@ func Mutate(policyContext *PolicyContext) (resp *response.EngineResponse) {
 		}
 
 		resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, ruleResponse)
+		rules.setResult(ruleResponse)
 		incrementAppliedRuleCount(resp)
 	}
 
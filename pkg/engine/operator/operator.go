@@ -1,5 +1,12 @@
 package operator
 
+import (
+	"regexp"
+	"strings"
+
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
 // Operator is string alias that represents selection operators enum
 type Operator string
 
@@ -16,17 +23,59 @@ const (
 	More Operator = ">"
 	// Less stands for <
 	Less Operator = "<"
+	// InRange stands for a-b, both bounds included
+	InRange Operator = "-"
+	// NotInRange stands for !a-b
+	NotInRange Operator = "!-"
+)
+
+var (
+	inRangeRegex    = regexp.MustCompile(`^\d+(\.\d+)?[a-zA-Z]*\s*-\s*\d+(\.\d+)?[a-zA-Z]*$`)
+	notInRangeRegex = regexp.MustCompile(`^!\s*\d+(\.\d+)?[a-zA-Z]*\s*-\s*\d+(\.\d+)?[a-zA-Z]*$`)
 )
 
 //ReferenceSign defines the operator for anchor reference
 const ReferenceSign Operator = "$()"
 
+// GetRangeBounds returns the lower and upper bounds of a range pattern
+func GetRangeBounds(pattern string, op Operator) (string, string) {
+	if op == NotInRange {
+		pattern = strings.TrimPrefix(pattern, string(NotEqual))
+	}
+
+	bounds := strings.SplitN(pattern, string(InRange), 2)
+	if len(bounds) != 2 {
+		return "", ""
+	}
+
+	return strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])
+}
+
+// hasQuantityBounds checks if both bounds of a range pattern are numbers or quantities
+func hasQuantityBounds(pattern string, op Operator) bool {
+	lower, upper := GetRangeBounds(pattern, op)
+	if _, err := apiresource.ParseQuantity(lower); err != nil {
+		return false
+	}
+
+	_, err := apiresource.ParseQuantity(upper)
+	return err == nil
+}
+
 // GetOperatorFromStringPattern parses opeartor from pattern
 func GetOperatorFromStringPattern(pattern string) Operator {
 	if len(pattern) < 2 {
 		return Equal
 	}
 
+	if notInRangeRegex.MatchString(pattern) && hasQuantityBounds(pattern, NotInRange) {
+		return NotInRange
+	}
+
+	if inRangeRegex.MatchString(pattern) && hasQuantityBounds(pattern, InRange) {
+		return InRange
+	}
+
 	if pattern[:len(MoreEqual)] == string(MoreEqual) {
 		return MoreEqual
 	}
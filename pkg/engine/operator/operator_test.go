@@ -16,3 +16,34 @@ func TestGetOperatorFromStringPattern_EmptyString(t *testing.T) {
 func TestGetOperatorFromStringPattern_OnlyOperator(t *testing.T) {
 	assert.Equal(t, GetOperatorFromStringPattern(">="), MoreEqual)
 }
+
+func TestGetOperatorFromStringPattern_InRange(t *testing.T) {
+	assert.Equal(t, GetOperatorFromStringPattern("1-1024"), InRange)
+	assert.Equal(t, GetOperatorFromStringPattern("1Gi - 2Gi"), InRange)
+	assert.Equal(t, GetOperatorFromStringPattern("0.5-1.5"), InRange)
+}
+
+func TestGetOperatorFromStringPattern_NotInRange(t *testing.T) {
+	assert.Equal(t, GetOperatorFromStringPattern("!100-200"), NotInRange)
+	assert.Equal(t, GetOperatorFromStringPattern("!1Gi-2Gi"), NotInRange)
+}
+
+func TestGetOperatorFromStringPattern_NotARange(t *testing.T) {
+	assert.Equal(t, GetOperatorFromStringPattern("2021-10-01"), Equal)
+	assert.Equal(t, GetOperatorFromStringPattern("nginx-1"), Equal)
+	assert.Equal(t, GetOperatorFromStringPattern("!nginx-1"), NotEqual)
+	assert.Equal(t, GetOperatorFromStringPattern(">-1"), More)
+	assert.Equal(t, GetOperatorFromStringPattern("1x-2y"), Equal)
+	assert.Equal(t, GetOperatorFromStringPattern("!1x-2y"), NotEqual)
+	assert.Equal(t, GetOperatorFromStringPattern("1Gi-2Gx"), Equal)
+}
+
+func TestGetRangeBounds(t *testing.T) {
+	lower, upper := GetRangeBounds("1-1024", InRange)
+	assert.Equal(t, lower, "1")
+	assert.Equal(t, upper, "1024")
+
+	lower, upper = GetRangeBounds("!1Gi - 2Gi", NotInRange)
+	assert.Equal(t, lower, "1Gi")
+	assert.Equal(t, upper, "2Gi")
+}
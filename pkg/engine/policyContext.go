@@ -1,11 +1,14 @@
 package engine
 
 import (
+	gocontext "context"
+
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	client "github.com/kyverno/kyverno/pkg/dclient"
 	"github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/resourcecache"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // PolicyContext contains the contexts for engine to process
@@ -39,4 +42,33 @@ type PolicyContext struct {
 
 	// NamespaceLabels stores the label of namespace to be processed by namespace selector
 	NamespaceLabels map[string]string
+
+	// Subresource is the subresource targeted by the admission request, it is empty for resources
+	Subresource Subresource
+
+	// TraceContext carries the span the engine spans are attached to, it may be nil
+	TraceContext gocontext.Context
+}
+
+// Subresource identifies the subresource of an admission request, for example pods/exec
+type Subresource struct {
+	// Name is the name of the subresource, for example exec, scale or status
+	Name string
+
+	// ParentKind is the kind of the resource the subresource belongs to, for example v1/Pod for pods/exec
+	ParentKind schema.GroupVersionKind
+}
+
+// Copy returns a copy of the policy context with its own resources and JSON context,
+// so that policies can be evaluated concurrently
+func (pc *PolicyContext) Copy() *PolicyContext {
+	newPc := *pc
+	newPc.Policy = *pc.Policy.DeepCopy()
+	newPc.NewResource = *pc.NewResource.DeepCopy()
+	newPc.OldResource = *pc.OldResource.DeepCopy()
+	if pc.JSONContext != nil {
+		newPc.JSONContext = pc.JSONContext.Copy()
+	}
+
+	return &newPc
 }
@@ -74,6 +74,8 @@ type RuleResponse struct {
 	Patches [][]byte `json:"patches,omitempty"`
 	// success/fail
 	Success bool `json:"success"`
+	// additional information on the result, e.g. the paths at which the validation patterns failed
+	Properties map[string]string `json:"properties,omitempty"`
 	// statistics
 	RuleStats `json:",inline"`
 }
@@ -0,0 +1,108 @@
+package engine
+
+import (
+	gocontext "context"
+	"reflect"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	resultPass = "pass"
+	resultFail = "fail"
+	resultSkip = "skip"
+)
+
+// startPolicySpan starts the span of a policy and makes it the parent of the spans started
+// while the policy is processed. The returned function ends the span and restores the parent.
+func startPolicySpan(policyContext *PolicyContext, name string) func(resp *response.EngineResponse) {
+	parent := policyContext.TraceContext
+	resource := policyContext.NewResource
+	if reflect.DeepEqual(resource, unstructured.Unstructured{}) {
+		resource = policyContext.OldResource
+	}
+
+	attrs := append(tracing.ResourceAttributes(resource.GetKind(), resource.GetNamespace(), resource.GetName()),
+		tracing.PolicyKey.String(policyContext.Policy.GetName()),
+		tracing.PolicyNamespaceKey.String(policyContext.Policy.GetNamespace()))
+
+	ctx, span := tracing.StartSpan(parent, name, attrs...)
+	policyContext.TraceContext = ctx
+
+	return func(resp *response.EngineResponse) {
+		if resp != nil {
+			span.SetAttributes(
+				tracing.ResultKey.String(policyResult(resp)),
+				attribute.Int("kyverno.rules.applied", resp.PolicyResponse.RulesAppliedCount))
+		}
+
+		span.End()
+		policyContext.TraceContext = parent
+	}
+}
+
+func policyResult(resp *response.EngineResponse) string {
+	if len(resp.PolicyResponse.Rules) == 0 {
+		return resultSkip
+	}
+
+	if len(resp.GetFailedRules()) != 0 {
+		return resultFail
+	}
+
+	return resultPass
+}
+
+// ruleSpans traces the rules of a policy, the rules are processed one at a time
+type ruleSpans struct {
+	policyContext *PolicyContext
+	parent        gocontext.Context
+	span          trace.Span
+	result        string
+}
+
+func newRuleSpans(policyContext *PolicyContext) *ruleSpans {
+	return &ruleSpans{
+		policyContext: policyContext,
+		parent:        policyContext.TraceContext,
+	}
+}
+
+// start ends the span of the previous rule and starts the span of the given rule
+func (r *ruleSpans) start(rule string) {
+	r.end()
+
+	ctx, span := tracing.StartSpan(r.parent, "engine.rule", tracing.RuleKey.String(rule))
+	r.span = span
+	r.result = resultSkip
+	r.policyContext.TraceContext = ctx
+}
+
+// setResult records the response of the current rule, a rule with several responses
+// fails when any of them fails
+func (r *ruleSpans) setResult(ruleResp response.RuleResponse) {
+	if r.span == nil || r.result == resultFail {
+		return
+	}
+
+	r.result = resultPass
+	if !ruleResp.Success {
+		r.result = resultFail
+	}
+}
+
+// end ends the span of the current rule
+func (r *ruleSpans) end() {
+	if r.span == nil {
+		return
+	}
+
+	r.span.SetAttributes(tracing.ResultKey.String(r.result))
+	r.span.End()
+	r.span = nil
+	r.policyContext.TraceContext = r.parent
+}
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gotest.tools/assert"
+)
+
+func Test_Validate_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	rawPolicy := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "require-labels"},
+		"spec": {
+			"rules": [
+				{
+					"name": "require-app",
+					"match": {"resources": {"kinds": ["Pod"]}},
+					"validate": {"message": "label app is required", "pattern": {"metadata": {"labels": {"app": "?*"}}}}
+				},
+				{
+					"name": "require-team",
+					"match": {"resources": {"kinds": ["Pod"]}},
+					"validate": {"message": "label team is required", "pattern": {"metadata": {"labels": {"team": "?*"}}}}
+				},
+				{
+					"name": "only-deployments",
+					"match": {"resources": {"kinds": ["Deployment"]}},
+					"validate": {"message": "label app is required", "pattern": {"metadata": {"labels": {"app": "?*"}}}}
+				}
+			]
+		}
+	}`)
+
+	rawResource := []byte(`{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {"name": "nginx", "namespace": "default", "labels": {"app": "nginx"}},
+		"spec": {"containers": [{"name": "nginx", "image": "nginx"}]}
+	}`)
+
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(rawPolicy, &policy))
+	resource, err := utils.ConvertToUnstructured(rawResource)
+	assert.NilError(t, err)
+
+	parentCtx, parent := tracing.StartSpan(gocontext.Background(), "webhook")
+	policyContext := &PolicyContext{Policy: policy, NewResource: *resource, JSONContext: context.NewContext(), TraceContext: parentCtx}
+	Validate(policyContext)
+	parent.End()
+
+	// the parent is restored once the policy is processed
+	assert.Equal(t, policyContext.TraceContext, parentCtx)
+
+	spans := recorder.Ended()
+	results := map[string]string{}
+	var policySpan sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		attrs := map[string]string{}
+		for _, attr := range span.Attributes() {
+			attrs[string(attr.Key)] = attr.Value.Emit()
+		}
+
+		switch span.Name() {
+		case "engine.rule":
+			results[attrs[string(tracing.RuleKey)]] = attrs[string(tracing.ResultKey)]
+		case "engine.validate":
+			policySpan = span
+			assert.Equal(t, attrs[string(tracing.PolicyKey)], "require-labels")
+			assert.Equal(t, attrs[string(tracing.ResourceKindKey)], "Pod")
+			assert.Equal(t, attrs[string(tracing.ResultKey)], "fail")
+		}
+	}
+
+	assert.Assert(t, policySpan != nil)
+	assert.Equal(t, policySpan.Parent().SpanID(), parent.SpanContext().SpanID())
+	assert.DeepEqual(t, results, map[string]string{
+		"require-app":      "pass",
+		"require-team":     "fail",
+		"only-deployments": "skip",
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/config"
 	"github.com/kyverno/kyverno/pkg/engine/wildcards"
 	"github.com/kyverno/kyverno/pkg/utils"
 	"github.com/minio/pkg/wildcard"
@@ -28,19 +29,29 @@ type EngineStats struct {
 	RulesAppliedCount int
 }
 
-func checkKind(kinds []string, resource unstructured.Unstructured) bool {
+// checkKind checks if the resource or its subresource matches one of the kinds. A kind without
+// a subresource only matches resources, and a kind with a subresource, for example Pod/exec,
+// only matches requests for that subresource of the parent kind.
+func checkKind(kinds []string, resource unstructured.Unstructured, subresource Subresource) bool {
+	gvk := resource.GroupVersionKind()
+	if subresource.Name != "" && subresource.ParentKind.Kind != "" {
+		gvk = subresource.ParentKind
+	}
+
 	for _, kind := range kinds {
-		SplitGVK := strings.Split(kind, "/")
-		if len(SplitGVK) == 1 {
-			if resource.GetKind() == kind {
-				return true
-			}
-		} else if len(SplitGVK) == 2 {
-			if resource.GroupVersionKind().Kind == SplitGVK[1] && resource.GroupVersionKind().Version == SplitGVK[0] {
+		group, version, k, sub := utils.ParseKindSelector(kind)
+		if k != gvk.Kind || sub != subresource.Name {
+			continue
+		}
+
+		if version == "" {
+			return true
+		} else if group == "" {
+			if gvk.Version == version {
 				return true
 			}
 		} else {
-			if resource.GroupVersionKind().Group == SplitGVK[0] && resource.GroupVersionKind().Kind == SplitGVK[2] && (resource.GroupVersionKind().Version == SplitGVK[1] || resource.GroupVersionKind().Version == "*") {
+			if gvk.Group == group && (gvk.Version == version || gvk.Version == "*") {
 				return true
 			}
 		}
@@ -120,11 +131,11 @@ func checkSelector(labelSelector *metav1.LabelSelector, resourceLabels map[strin
 // should be: AND across attributes but an OR inside attributes that of type list
 // To filter out the targeted resources with UserInfo, the check
 // should be: OR (across & inside) attributes
-func doesResourceMatchConditionBlock(conditionBlock kyverno.ResourceDescription, userInfo kyverno.UserInfo, admissionInfo kyverno.RequestInfo, resource unstructured.Unstructured, dynamicConfig []string, namespaceLabels map[string]string) []error {
+func doesResourceMatchConditionBlock(conditionBlock kyverno.ResourceDescription, userInfo kyverno.UserInfo, admissionInfo kyverno.RequestInfo, resource unstructured.Unstructured, dynamicConfig []string, namespaceLabels map[string]string, subresource Subresource) []error {
 	var errs []error
 
 	if len(conditionBlock.Kinds) > 0 {
-		if !checkKind(conditionBlock.Kinds, resource) {
+		if !checkKind(conditionBlock.Kinds, resource, subresource) {
 			errs = append(errs, fmt.Errorf("kind does not match %v", conditionBlock.Kinds))
 		}
 	}
@@ -256,7 +267,7 @@ func matchSubjects(ruleSubjects []rbacv1.Subject, userInfo authenticationv1.User
 }
 
 //MatchesResourceDescription checks if the resource matches resource description of the rule or not
-func MatchesResourceDescription(resourceRef unstructured.Unstructured, ruleRef kyverno.Rule, admissionInfoRef kyverno.RequestInfo, dynamicConfig []string, namespaceLabels map[string]string) error {
+func MatchesResourceDescription(resourceRef unstructured.Unstructured, ruleRef kyverno.Rule, admissionInfoRef kyverno.RequestInfo, dynamicConfig []string, namespaceLabels map[string]string, subresource Subresource) error {
 
 	rule := *ruleRef.DeepCopy()
 	resource := *resourceRef.DeepCopy()
@@ -269,7 +280,7 @@ func MatchesResourceDescription(resourceRef unstructured.Unstructured, ruleRef k
 		oneMatched := false
 		for _, rmr := range rule.MatchResources.Any {
 			// if there are no errors it means it was a match
-			if len(matchesResourceDescriptionMatchHelper(rmr, admissionInfo, resource, dynamicConfig, namespaceLabels)) == 0 {
+			if len(matchesResourceDescriptionMatchHelper(rmr, admissionInfo, resource, dynamicConfig, namespaceLabels, subresource)) == 0 {
 				oneMatched = true
 				break
 			}
@@ -280,17 +291,17 @@ func MatchesResourceDescription(resourceRef unstructured.Unstructured, ruleRef k
 	} else if len(rule.MatchResources.All) > 0 {
 		// include object if ALL of the criterias match
 		for _, rmr := range rule.MatchResources.All {
-			reasonsForFailure = append(reasonsForFailure, matchesResourceDescriptionMatchHelper(rmr, admissionInfo, resource, dynamicConfig, namespaceLabels)...)
+			reasonsForFailure = append(reasonsForFailure, matchesResourceDescriptionMatchHelper(rmr, admissionInfo, resource, dynamicConfig, namespaceLabels, subresource)...)
 		}
 	} else {
 		rmr := kyverno.ResourceFilter{UserInfo: rule.MatchResources.UserInfo, ResourceDescription: rule.MatchResources.ResourceDescription}
-		reasonsForFailure = append(reasonsForFailure, matchesResourceDescriptionMatchHelper(rmr, admissionInfo, resource, dynamicConfig, namespaceLabels)...)
+		reasonsForFailure = append(reasonsForFailure, matchesResourceDescriptionMatchHelper(rmr, admissionInfo, resource, dynamicConfig, namespaceLabels, subresource)...)
 	}
 
 	if len(rule.ExcludeResources.Any) > 0 {
 		// exclude the object if ANY of the criterias match
 		for _, rer := range rule.ExcludeResources.Any {
-			reasonsForFailure = append(reasonsForFailure, matchesResourceDescriptionExcludeHelper(rer, admissionInfo, resource, dynamicConfig, namespaceLabels)...)
+			reasonsForFailure = append(reasonsForFailure, matchesResourceDescriptionExcludeHelper(rer, admissionInfo, resource, dynamicConfig, namespaceLabels, subresource)...)
 		}
 	} else if len(rule.ExcludeResources.All) > 0 {
 		// exlcude the object if ALL the criterias match
@@ -298,7 +309,7 @@ func MatchesResourceDescription(resourceRef unstructured.Unstructured, ruleRef k
 		for _, rer := range rule.ExcludeResources.All {
 			// we got no errors inplying a resource did NOT exclude it
 			// "matchesResourceDescriptionExcludeHelper" returns errors if resource is excluded by a filter
-			if len(matchesResourceDescriptionExcludeHelper(rer, admissionInfo, resource, dynamicConfig, namespaceLabels)) == 0 {
+			if len(matchesResourceDescriptionExcludeHelper(rer, admissionInfo, resource, dynamicConfig, namespaceLabels, subresource)) == 0 {
 				excludedByAll = false
 				break
 			}
@@ -308,7 +319,7 @@ func MatchesResourceDescription(resourceRef unstructured.Unstructured, ruleRef k
 		}
 	} else {
 		rer := kyverno.ResourceFilter{UserInfo: rule.ExcludeResources.UserInfo, ResourceDescription: rule.ExcludeResources.ResourceDescription}
-		reasonsForFailure = append(reasonsForFailure, matchesResourceDescriptionExcludeHelper(rer, admissionInfo, resource, dynamicConfig, namespaceLabels)...)
+		reasonsForFailure = append(reasonsForFailure, matchesResourceDescriptionExcludeHelper(rer, admissionInfo, resource, dynamicConfig, namespaceLabels, subresource)...)
 	}
 
 	// creating final error
@@ -326,7 +337,7 @@ func MatchesResourceDescription(resourceRef unstructured.Unstructured, ruleRef k
 	return nil
 }
 
-func matchesResourceDescriptionMatchHelper(rmr kyverno.ResourceFilter, admissionInfo kyverno.RequestInfo, resource unstructured.Unstructured, dynamicConfig []string, namespaceLabels map[string]string) []error {
+func matchesResourceDescriptionMatchHelper(rmr kyverno.ResourceFilter, admissionInfo kyverno.RequestInfo, resource unstructured.Unstructured, dynamicConfig []string, namespaceLabels map[string]string, subresource Subresource) []error {
 	var errs []error
 	if reflect.DeepEqual(admissionInfo, kyverno.RequestInfo{}) {
 		rmr.UserInfo = kyverno.UserInfo{}
@@ -335,7 +346,7 @@ func matchesResourceDescriptionMatchHelper(rmr kyverno.ResourceFilter, admission
 	// checking if resource matches the rule
 	if !reflect.DeepEqual(rmr.ResourceDescription, kyverno.ResourceDescription{}) ||
 		!reflect.DeepEqual(rmr.UserInfo, kyverno.UserInfo{}) {
-		matchErrs := doesResourceMatchConditionBlock(rmr.ResourceDescription, rmr.UserInfo, admissionInfo, resource, dynamicConfig, namespaceLabels)
+		matchErrs := doesResourceMatchConditionBlock(rmr.ResourceDescription, rmr.UserInfo, admissionInfo, resource, dynamicConfig, namespaceLabels, subresource)
 		errs = append(errs, matchErrs...)
 	} else {
 		errs = append(errs, fmt.Errorf("match cannot be empty"))
@@ -343,12 +354,12 @@ func matchesResourceDescriptionMatchHelper(rmr kyverno.ResourceFilter, admission
 	return errs
 }
 
-func matchesResourceDescriptionExcludeHelper(rer kyverno.ResourceFilter, admissionInfo kyverno.RequestInfo, resource unstructured.Unstructured, dynamicConfig []string, namespaceLabels map[string]string) []error {
+func matchesResourceDescriptionExcludeHelper(rer kyverno.ResourceFilter, admissionInfo kyverno.RequestInfo, resource unstructured.Unstructured, dynamicConfig []string, namespaceLabels map[string]string, subresource Subresource) []error {
 	var errs []error
 	// checking if resource matches the rule
 	if !reflect.DeepEqual(rer.ResourceDescription, kyverno.ResourceDescription{}) ||
 		!reflect.DeepEqual(rer.UserInfo, kyverno.UserInfo{}) {
-		excludeErrs := doesResourceMatchConditionBlock(rer.ResourceDescription, rer.UserInfo, admissionInfo, resource, dynamicConfig, namespaceLabels)
+		excludeErrs := doesResourceMatchConditionBlock(rer.ResourceDescription, rer.UserInfo, admissionInfo, resource, dynamicConfig, namespaceLabels, subresource)
 		// it was a match so we want to exclude it
 		if len(excludeErrs) == 0 {
 			errs = append(errs, fmt.Errorf("resource excluded since one of the criterias excluded it"))
@@ -395,14 +406,14 @@ func transformConditions(original apiextensions.JSON) (interface{}, error) {
 	return nil, fmt.Errorf("wrongfully configured data")
 }
 
-// excludeResource checks if the resource has ownerRef set
-func excludeResource(podControllers string, resource unstructured.Unstructured) bool {
+// excludeResource checks if the resource has ownerRef set to one of the pod controllers
+func excludeResource(podControllers []string, resource unstructured.Unstructured) bool {
 	kind := resource.GetKind()
 	hasOwner := false
 	if kind == "Pod" || kind == "Job" {
 		for _, owner := range resource.GetOwnerReferences() {
 			hasOwner = true
-			if owner.Kind != "ReplicaSet" && !strings.Contains(podControllers, owner.Kind) {
+			if !isManagedOwner(podControllers, owner.Kind) {
 				return false
 			}
 		}
@@ -412,22 +423,67 @@ func excludeResource(podControllers string, resource unstructured.Unstructured)
 	return false
 }
 
+// isManagedOwner checks if the resources owned by the kind are covered by the rules generated for the pod controllers.
+// Pods owned by a ReplicaSet are assumed to be created by a Deployment, Jobs may be created by a CronJob.
+func isManagedOwner(podControllers []string, ownerKind string) bool {
+	switch ownerKind {
+	case "ReplicaSet":
+		return utils.ContainsString(podControllers, "ReplicaSet") || utils.ContainsString(podControllers, "Deployment")
+	case "Job":
+		return utils.ContainsString(podControllers, "Job") || utils.ContainsString(podControllers, PodControllerCronJob)
+	default:
+		return utils.ContainsString(podControllers, ownerKind)
+	}
+}
+
 // ManagedPodResource returns true:
-// - if the policy has auto-gen annotation && resource == Pod
-// - if the auto-gen contains cronJob && resource == Job
+// - if the policy has auto-gen annotation && resource == Pod owned by one of the pod controllers
+// - if the auto-gen contains cronJob && resource == Job owned by a CronJob
 func ManagedPodResource(policy kyverno.ClusterPolicy, resource unstructured.Unstructured) bool {
 	podControllers, ok := policy.GetAnnotations()[PodControllersAnnotation]
 	if !ok || strings.ToLower(podControllers) == "none" {
 		return false
 	}
 
-	if excludeResource(podControllers, resource) {
-		return true
+	return excludeResource(config.PodControllerKinds(podControllers), resource)
+}
+
+// GetValidationFailureAction returns the validationFailureAction of the policy for the resource.
+// The first override matching the namespace of the resource takes precedence over spec.validationFailureAction.
+func GetValidationFailureAction(policy kyverno.ClusterPolicy, resource unstructured.Unstructured, namespaceLabels map[string]string) string {
+	namespace := resource.GetNamespace()
+	if resource.GetKind() == "Namespace" {
+		namespace = resource.GetName()
+		namespaceLabels = resource.GetLabels()
 	}
 
-	if strings.Contains(podControllers, "CronJob") && excludeResource(podControllers, resource) {
-		return true
+	if namespace != "" {
+		for _, override := range policy.Spec.ValidationFailureActionOverrides {
+			if overrideMatchesNamespace(override, namespace, namespaceLabels) {
+				return override.Action
+			}
+		}
 	}
 
-	return false
+	return policy.Spec.ValidationFailureAction
+}
+
+// overrideMatchesNamespace checks if the namespace matches both the names and the selector of the override
+func overrideMatchesNamespace(override kyverno.ValidationFailureActionOverride, namespace string, namespaceLabels map[string]string) bool {
+	if len(override.Namespaces) == 0 && override.NamespaceSelector == nil {
+		return false
+	}
+
+	if len(override.Namespaces) > 0 && !utils.ContainsNamepace(override.Namespaces, namespace) {
+		return false
+	}
+
+	if override.NamespaceSelector != nil {
+		matched, err := checkSelector(override.NamespaceSelector.DeepCopy(), namespaceLabels)
+		if err != nil || !matched {
+			return false
+		}
+	}
+
+	return true
 }
@@ -8,6 +8,8 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine/utils"
 	"gotest.tools/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestMatchesResourceDescription(t *testing.T) {
@@ -880,7 +882,7 @@ func TestMatchesResourceDescription(t *testing.T) {
 		resource, _ := utils.ConvertToUnstructured(tc.Resource)
 
 		for _, rule := range policy.Spec.Rules {
-			err := MatchesResourceDescription(*resource, rule, tc.AdmissionInfo, []string{}, nil)
+			err := MatchesResourceDescription(*resource, rule, tc.AdmissionInfo, []string{}, nil, Subresource{})
 			if err != nil {
 				if !tc.areErrorsExpected {
 					t.Errorf("Testcase %d Unexpected error: %v", i+1, err)
@@ -948,7 +950,7 @@ func TestResourceDescriptionMatch_MultipleKind(t *testing.T) {
 	}
 	rule := kyverno.Rule{MatchResources: kyverno.MatchResources{ResourceDescription: resourceDescription}}
 
-	if err := MatchesResourceDescription(*resource, rule, kyverno.RequestInfo{}, []string{}, nil); err != nil {
+	if err := MatchesResourceDescription(*resource, rule, kyverno.RequestInfo{}, []string{}, nil, Subresource{}); err != nil {
 		t.Errorf("Testcase has failed due to the following:%v", err)
 	}
 
@@ -1009,7 +1011,7 @@ func TestResourceDescriptionMatch_Name(t *testing.T) {
 	}
 	rule := kyverno.Rule{MatchResources: kyverno.MatchResources{ResourceDescription: resourceDescription}}
 
-	if err := MatchesResourceDescription(*resource, rule, kyverno.RequestInfo{}, []string{}, nil); err != nil {
+	if err := MatchesResourceDescription(*resource, rule, kyverno.RequestInfo{}, []string{}, nil, Subresource{}); err != nil {
 		t.Errorf("Testcase has failed due to the following:%v", err)
 	}
 }
@@ -1069,7 +1071,7 @@ func TestResourceDescriptionMatch_Name_Regex(t *testing.T) {
 	}
 	rule := kyverno.Rule{MatchResources: kyverno.MatchResources{ResourceDescription: resourceDescription}}
 
-	if err := MatchesResourceDescription(*resource, rule, kyverno.RequestInfo{}, []string{}, nil); err != nil {
+	if err := MatchesResourceDescription(*resource, rule, kyverno.RequestInfo{}, []string{}, nil, Subresource{}); err != nil {
 		t.Errorf("Testcase has failed due to the following:%v", err)
 	}
 }
@@ -1137,7 +1139,7 @@ func TestResourceDescriptionMatch_Label_Expression_NotMatch(t *testing.T) {
 	}
 	rule := kyverno.Rule{MatchResources: kyverno.MatchResources{ResourceDescription: resourceDescription}}
 
-	if err := MatchesResourceDescription(*resource, rule, kyverno.RequestInfo{}, []string{}, nil); err != nil {
+	if err := MatchesResourceDescription(*resource, rule, kyverno.RequestInfo{}, []string{}, nil, Subresource{}); err != nil {
 		t.Errorf("Testcase has failed due to the following:%v", err)
 	}
 }
@@ -1206,7 +1208,7 @@ func TestResourceDescriptionMatch_Label_Expression_Match(t *testing.T) {
 	}
 	rule := kyverno.Rule{MatchResources: kyverno.MatchResources{ResourceDescription: resourceDescription}}
 
-	if err := MatchesResourceDescription(*resource, rule, kyverno.RequestInfo{}, []string{}, nil); err != nil {
+	if err := MatchesResourceDescription(*resource, rule, kyverno.RequestInfo{}, []string{}, nil, Subresource{}); err != nil {
 		t.Errorf("Testcase has failed due to the following:%v", err)
 	}
 }
@@ -1286,7 +1288,7 @@ func TestResourceDescriptionExclude_Label_Expression_Match(t *testing.T) {
 	rule := kyverno.Rule{MatchResources: kyverno.MatchResources{ResourceDescription: resourceDescription},
 		ExcludeResources: kyverno.ExcludeResources{ResourceDescription: resourceDescriptionExclude}}
 
-	if err := MatchesResourceDescription(*resource, rule, kyverno.RequestInfo{}, []string{}, nil); err == nil {
+	if err := MatchesResourceDescription(*resource, rule, kyverno.RequestInfo{}, []string{}, nil, Subresource{}); err == nil {
 		t.Errorf("Testcase has failed due to the following:\n Function has returned no error, even though it was supposed to fail")
 	}
 }
@@ -1418,6 +1420,30 @@ func TestManagedPodResource(t *testing.T) {
 			resource:       []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "test","ownerReferences": [{"kind": "Deployment"},{"kind": "Challenge"}]}}`),
 			expectedResult: false,
 		},
+		{
+			name:           "enable-autogen-pod-with-owner-rs-not-autogen",
+			policy:         []byte(`{"apiVersion": "kyverno.io/v1","kind": "ClusterPolicy","metadata": {"name": "test-managedPod","annotations": {"pod-policies.kyverno.io/autogen-controllers": "StatefulSet"}}}`),
+			resource:       []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "test","ownerReferences": [{"kind": "ReplicaSet"}]}}`),
+			expectedResult: false,
+		},
+		{
+			name:           "enable-autogen-pod-with-custom-owner",
+			policy:         []byte(`{"apiVersion": "kyverno.io/v1","kind": "ClusterPolicy","metadata": {"name": "test-managedPod","annotations": {"pod-policies.kyverno.io/autogen-controllers": "Deployment,CloneSet=spec.template"}}}`),
+			resource:       []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "test","ownerReferences": [{"kind": "CloneSet"}]}}`),
+			expectedResult: true,
+		},
+		{
+			name:           "enable-autogen-pod-with-owner-kind-substring",
+			policy:         []byte(`{"apiVersion": "kyverno.io/v1","kind": "ClusterPolicy","metadata": {"name": "test-managedPod","annotations": {"pod-policies.kyverno.io/autogen-controllers": "CloneSet"}}}`),
+			resource:       []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "test","ownerReferences": [{"kind": "Set"}]}}`),
+			expectedResult: false,
+		},
+		{
+			name:           "enable-autogen-job-with-owner-cronjob",
+			policy:         []byte(`{"apiVersion": "kyverno.io/v1","kind": "ClusterPolicy","metadata": {"name": "test-managedPod","annotations": {"pod-policies.kyverno.io/autogen-controllers": "all"}}}`),
+			resource:       []byte(`{"apiVersion": "batch/v1","kind": "Job","metadata": {"name": "test","ownerReferences": [{"kind": "CronJob"}]}}`),
+			expectedResult: true,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -1430,3 +1456,119 @@ func TestManagedPodResource(t *testing.T) {
 		assert.Equal(t, res, tc.expectedResult, "test %d/%s failed, expect %v, got %v", i+1, tc.name, tc.expectedResult, res)
 	}
 }
+
+func TestGetValidationFailureAction(t *testing.T) {
+	rawPolicy := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "test-overrides"},
+		"spec": {
+			"validationFailureAction": "audit",
+			"validationFailureActionOverrides": [
+				{"action": "enforce", "namespaces": ["prod-*"]},
+				{"action": "audit", "namespaces": ["prod-sandbox"]},
+				{"action": "enforce", "namespaceSelector": {"matchLabels": {"env": "staging"}}},
+				{"action": "enforce", "namespaces": ["team-*"], "namespaceSelector": {"matchLabels": {"tier": "critical"}}}
+			]
+		}
+	}`)
+
+	testCases := []struct {
+		name            string
+		resource        []byte
+		namespaceLabels map[string]string
+		expectedAction  string
+	}{
+		{
+			name:           "no-matching-override",
+			resource:       []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "test","namespace": "dev"}}`),
+			expectedAction: "audit",
+		},
+		{
+			name:           "namespace-wildcard",
+			resource:       []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "test","namespace": "prod-payments"}}`),
+			expectedAction: "enforce",
+		},
+		{
+			name:           "first-override-wins",
+			resource:       []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "test","namespace": "prod-sandbox"}}`),
+			expectedAction: "enforce",
+		},
+		{
+			name:            "namespace-selector",
+			resource:        []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "test","namespace": "qa"}}`),
+			namespaceLabels: map[string]string{"env": "staging"},
+			expectedAction:  "enforce",
+		},
+		{
+			name:            "names-and-selector-must-both-match",
+			resource:        []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "test","namespace": "team-a"}}`),
+			namespaceLabels: map[string]string{"tier": "best-effort"},
+			expectedAction:  "audit",
+		},
+		{
+			name:            "names-and-selector-match",
+			resource:        []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "test","namespace": "team-a"}}`),
+			namespaceLabels: map[string]string{"tier": "critical"},
+			expectedAction:  "enforce",
+		},
+		{
+			name:           "namespace-resource",
+			resource:       []byte(`{"apiVersion": "v1","kind": "Namespace","metadata": {"name": "qa","labels": {"env": "staging"}}}`),
+			expectedAction: "enforce",
+		},
+		{
+			name:           "cluster-scoped-resource",
+			resource:       []byte(`{"apiVersion": "v1","kind": "ClusterRole","metadata": {"name": "prod-admin"}}`),
+			expectedAction: "audit",
+		},
+	}
+
+	var policy kyverno.ClusterPolicy
+	err := json.Unmarshal(rawPolicy, &policy)
+	assert.NilError(t, err)
+
+	for _, tc := range testCases {
+		resource, err := utils.ConvertToUnstructured(tc.resource)
+		assert.NilError(t, err)
+
+		action := GetValidationFailureAction(policy, *resource, tc.namespaceLabels)
+		assert.Equal(t, action, tc.expectedAction, tc.name)
+	}
+}
+
+func TestCheckKind_Subresource(t *testing.T) {
+	pod := unstructured.Unstructured{}
+	pod.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "Pod"})
+
+	execOptions := unstructured.Unstructured{}
+	execOptions.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "PodExecOptions"})
+	exec := Subresource{Name: "exec", ParentKind: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}}
+
+	scale := unstructured.Unstructured{}
+	scale.SetGroupVersionKind(schema.GroupVersionKind{Group: "autoscaling", Version: "v1", Kind: "Scale"})
+	deploymentScale := Subresource{Name: "scale", ParentKind: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}}
+
+	testCases := []struct {
+		name        string
+		kinds       []string
+		resource    unstructured.Unstructured
+		subresource Subresource
+		expected    bool
+	}{
+		{name: "kind-matches-resource", kinds: []string{"Pod"}, resource: pod, expected: true},
+		{name: "version-kind-matches-resource", kinds: []string{"v1/Pod"}, resource: pod, expected: true},
+		{name: "subresource-does-not-match-resource", kinds: []string{"Pod/exec"}, resource: pod, expected: false},
+		{name: "kind-does-not-match-subresource", kinds: []string{"Pod"}, resource: execOptions, subresource: exec, expected: false},
+		{name: "subresource-matches", kinds: []string{"Pod/exec"}, resource: execOptions, subresource: exec, expected: true},
+		{name: "version-subresource-matches", kinds: []string{"v1/Pod/exec"}, resource: execOptions, subresource: exec, expected: true},
+		{name: "other-subresource-does-not-match", kinds: []string{"Pod/attach"}, resource: execOptions, subresource: exec, expected: false},
+		{name: "group-version-subresource-matches", kinds: []string{"apps/v1/Deployment/scale"}, resource: scale, subresource: deploymentScale, expected: true},
+		{name: "other-parent-does-not-match", kinds: []string{"StatefulSet/scale"}, resource: scale, subresource: deploymentScale, expected: false},
+		{name: "subresource-kind-does-not-match", kinds: []string{"Scale"}, resource: scale, subresource: deploymentScale, expected: false},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, checkKind(test.kinds, test.resource, test.subresource), test.expected, test.name)
+	}
+}
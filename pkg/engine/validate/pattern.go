@@ -181,16 +181,60 @@ func checkForAndConditionsAndValidate(log logr.Logger, value interface{}, patter
 // Detects if pattern has a number
 func validateValueWithStringPattern(log logr.Logger, value interface{}, pattern string) bool {
 
-	operator := operator.GetOperatorFromStringPattern(pattern)
-	pattern = pattern[len(operator):]
+	operatorVariable := operator.GetOperatorFromStringPattern(pattern)
+	if operatorVariable == operator.InRange || operatorVariable == operator.NotInRange {
+		return validateRange(log, value, pattern, operatorVariable)
+	}
+
+	pattern = pattern[len(operatorVariable):]
 	pattern = strings.TrimSpace(pattern)
 	number, str := getNumberAndStringPartsFromPattern(pattern)
 
 	if "" == number {
-		return validateString(log, value, str, operator)
+		return validateString(log, value, str, operatorVariable)
+	}
+
+	return validateNumberWithStr(log, value, pattern, operatorVariable)
+}
+
+// validateRange checks if the value is within (or outside of) the inclusive range
+// described by the pattern, bounds are compared as quantities
+func validateRange(log logr.Logger, value interface{}, pattern string, operatorVariable operator.Operator) bool {
+	lower, upper := operator.GetRangeBounds(pattern, operatorVariable)
+	lowerQuan, err := apiresource.ParseQuantity(lower)
+	if err != nil {
+		log.Error(err, "invalid lower bound in range pattern", "pattern", pattern)
+		return false
+	}
+
+	upperQuan, err := apiresource.ParseQuantity(upper)
+	if err != nil {
+		log.Error(err, "invalid upper bound in range pattern", "pattern", pattern)
+		return false
+	}
+
+	typedValue, err := convertNumberToString(value)
+	if err != nil {
+		log.Error(err, "failed to convert to string")
+		return false
+	}
+
+	valueQuan, err := apiresource.ParseQuantity(typedValue)
+	if err != nil {
+		// values that are not quantities, e.g. the version 1.2-3, are compared as strings
+		log.V(4).Info("value is not a quantity, comparing as string", "type", fmt.Sprintf("%T", typedValue), "value", typedValue)
+		if operatorVariable == operator.NotInRange {
+			return validateString(log, value, strings.TrimSpace(pattern[len(operator.NotEqual):]), operator.NotEqual)
+		}
+		return validateString(log, value, pattern, operator.Equal)
+	}
+
+	inRange := compareQuantity(valueQuan, lowerQuan, operator.MoreEqual) && compareQuantity(valueQuan, upperQuan, operator.LessEqual)
+	if operatorVariable == operator.NotInRange {
+		return !inRange
 	}
 
-	return validateNumberWithStr(log, value, pattern, operator)
+	return inRange
 }
 
 // Handler for string values
@@ -349,3 +349,33 @@ func TestGetOperatorFromStringPattern_OneChar(t *testing.T) {
 func TestGetOperatorFromStringPattern_EmptyString(t *testing.T) {
 	assert.Equal(t, operator.GetOperatorFromStringPattern(""), operator.Equal)
 }
+
+func TestValidateValueWithPattern_Range(t *testing.T) {
+	assert.Assert(t, ValidateValueWithPattern(log.Log, 80, "1-1024"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, int64(1), "1-1024"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, 1024.0, "1-1024"))
+	assert.Assert(t, !ValidateValueWithPattern(log.Log, 8080, "1-1024"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "1.5Gi", "1Gi-2Gi"))
+	assert.Assert(t, !ValidateValueWithPattern(log.Log, "3Gi", "1Gi-2Gi"))
+	assert.Assert(t, !ValidateValueWithPattern(log.Log, "abc", "1-1024"))
+}
+
+func TestValidateValueWithPattern_NotInRange(t *testing.T) {
+	assert.Assert(t, ValidateValueWithPattern(log.Log, 80, "!100-200"))
+	assert.Assert(t, !ValidateValueWithPattern(log.Log, 150, "!100-200"))
+	assert.Assert(t, !ValidateValueWithPattern(log.Log, 200, "!100-200"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, 80, "!100-200 & !300-400"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, 350, "1-10 | 300-400"))
+}
+
+func TestValidateValueWithPattern_RangeLikeString(t *testing.T) {
+	// bounds that are not quantities are compared as strings
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "1x-2y", "1x-2y"))
+	assert.Assert(t, !ValidateValueWithPattern(log.Log, "1x-3y", "1x-2y"))
+	// values that are not quantities are compared as strings
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "1.2-3", "1.2-3"))
+	assert.Assert(t, !ValidateValueWithPattern(log.Log, "1.2-4", "1.2-3"))
+	assert.Assert(t, !ValidateValueWithPattern(log.Log, "1.2-3", "!1.2-3"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "1.2-4", "!1.2-3"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "2", "1.2-3"))
+}
@@ -8,20 +8,39 @@ import (
 
 // Checks if pattern has anchors
 func hasNestedAnchors(pattern interface{}) bool {
+	return hasNestedAnchorsMatching(pattern, func(anchors map[string]interface{}) bool {
+		return len(anchors) > 0
+	})
+}
+
+// Checks if pattern has global anchors
+func hasNestedGlobalAnchors(pattern interface{}) bool {
+	return hasNestedAnchorsMatching(pattern, func(anchors map[string]interface{}) bool {
+		for key := range anchors {
+			if commonAnchors.IsGlobalAnchor(key) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Checks if pattern has anchors for which match returns true
+func hasNestedAnchorsMatching(pattern interface{}, match func(anchors map[string]interface{}) bool) bool {
 	switch typed := pattern.(type) {
 	case map[string]interface{}:
-		if anchors := getAnchorsFromMap(typed); len(anchors) > 0 {
+		if match(getAnchorsFromMap(typed)) {
 			return true
 		}
 		for _, value := range typed {
-			if hasNestedAnchors(value) {
+			if hasNestedAnchorsMatching(value, match) {
 				return true
 			}
 		}
 		return false
 	case []interface{}:
 		for _, value := range typed {
-			if hasNestedAnchors(value) {
+			if hasNestedAnchorsMatching(value, match) {
 				return true
 			}
 		}
@@ -32,15 +51,23 @@ func hasNestedAnchors(pattern interface{}) bool {
 }
 
 // getSortedNestedAnchorResource - sorts anchors key
+// keys with nested global anchors come first as a failing global anchor skips the rule,
+// followed by the keys with other nested anchors
 func getSortedNestedAnchorResource(resources map[string]interface{}) *list.List {
 	sortedResourceKeys := list.New()
+	var globalAnchorKeys []string
 	for k, v := range resources {
-		if hasNestedAnchors(v) {
+		if hasNestedGlobalAnchors(v) {
+			globalAnchorKeys = append(globalAnchorKeys, k)
+		} else if hasNestedAnchors(v) {
 			sortedResourceKeys.PushFront(k)
 		} else {
 			sortedResourceKeys.PushBack(k)
 		}
 	}
+	for _, k := range globalAnchorKeys {
+		sortedResourceKeys.PushFront(k)
+	}
 	return sortedResourceKeys
 }
 
@@ -48,9 +75,22 @@ func getSortedNestedAnchorResource(resources map[string]interface{}) *list.List
 func getAnchorsFromMap(anchorsMap map[string]interface{}) map[string]interface{} {
 	result := make(map[string]interface{})
 	for key, value := range anchorsMap {
-		if commonAnchors.IsConditionAnchor(key) || commonAnchors.IsExistenceAnchor(key) || commonAnchors.IsEqualityAnchor(key) || commonAnchors.IsNegationAnchor(key) {
+		if commonAnchors.IsConditionAnchor(key) || commonAnchors.IsExistenceAnchor(key) || commonAnchors.IsEqualityAnchor(key) || commonAnchors.IsNegationAnchor(key) || commonAnchors.IsGlobalAnchor(key) {
 			result[key] = value
 		}
 	}
 	return result
 }
+
+// getSortedAnchorKeys returns the anchor keys with the global anchors first
+func getSortedAnchorKeys(anchors map[string]interface{}) []string {
+	keys := make([]string, 0, len(anchors))
+	for key := range anchors {
+		if commonAnchors.IsGlobalAnchor(key) {
+			keys = append([]string{key}, keys...)
+		} else {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -15,11 +15,16 @@ import (
 
 // ValidateResourceWithPattern is a start of element-by-element validation process
 // It assumes that validation is started from root, so "/" is passed
+// A global anchor error is returned with an empty path, callers should skip the rule
 func ValidateResourceWithPattern(logger logr.Logger, resource, pattern interface{}) (string, error) {
 	// newAnchorMap - to check anchor key has values
 	ac := common.NewAnchorMap()
 	elemPath, err := validateResourceElement(logger, resource, pattern, pattern, "/", ac)
 	if err != nil {
+		if common.IsGlobalAnchorError(err.Error()) {
+			return "", err
+		}
+
 		if common.IsConditionalAnchorError(err.Error()) {
 			return "", nil
 		}
@@ -91,10 +96,13 @@ func validateMap(log logr.Logger, resourceMap, patternMap map[string]interface{}
 	anchors, resources := anchor.GetAnchorsResourcesFromMap(patternMap)
 
 	// Evaluate anchors
-	for key, patternElement := range anchors {
+	// global anchors are evaluated first as a failing global anchor skips the rule
+	for _, key := range getSortedAnchorKeys(anchors) {
+		patternElement := anchors[key]
 
 		// get handler for each pattern in the pattern
 		// - Conditional
+		// - Global
 		// - Existence
 		// - Equality
 		handler := anchor.CreateElementHandler(key, patternElement, path)
@@ -102,6 +110,13 @@ func validateMap(log logr.Logger, resourceMap, patternMap map[string]interface{}
 		// if there are resource values at same level, then anchor acts as conditional instead of a strict check
 		// but if there are non then its a if then check
 		if err != nil {
+			// If Global anchor fails then we skip the whole rule
+			if commonAnchors.IsGlobalAnchor(key) {
+				ac.AnchorError = common.NewGlobalAnchorError(fmt.Sprintf("global anchor did not satisfy: %s", err.Error()))
+				log.V(3).Info(ac.AnchorError.Message)
+				return "", ac.AnchorError.Error()
+			}
+
 			// If Conditional anchor fails then we don't process the resources
 			if commonAnchors.IsConditionAnchor(key) {
 				ac.AnchorError = common.NewConditionalAnchorError(fmt.Sprintf("condition anchor did not satisfy: %s", err.Error()))
@@ -213,18 +228,30 @@ func getValueFromPattern(log logr.Logger, patternMap map[string]interface{}, key
 
 // validateArrayOfMaps gets anchors from pattern array map element, applies anchors logic
 // and then validates each map due to the pattern
+// A global anchor error is only returned if it was raised by every element of the array
 func validateArrayOfMaps(log logr.Logger, resourceMapArray []interface{}, patternMap map[string]interface{}, originPattern interface{}, path string, ac *common.AnchorKey) (string, error) {
+	var globalAnchorErr error
+	applyCount := 0
 	for i, resourceElement := range resourceMapArray {
 		// check the types of resource element
 		// expect it to be map, but can be anything ?:(
 		currentPath := path + strconv.Itoa(i) + "/"
 		returnpath, err := validateResourceElement(log, resourceElement, patternMap, originPattern, currentPath, ac)
 		if err != nil {
+			if common.IsGlobalAnchorError(err.Error()) {
+				globalAnchorErr = err
+				continue
+			}
 			if common.IsConditionalAnchorError(err.Error()) {
 				continue
 			}
 			return returnpath, err
 		}
+		applyCount++
+	}
+
+	if applyCount == 0 && globalAnchorErr != nil {
+		return "", globalAnchorErr
 	}
 	return "", nil
 }
@@ -1522,3 +1522,77 @@ func TestConditionalAnchorWithMultiplePatterns(t *testing.T) {
 		}
 	}
 }
+
+func TestGlobalAnchor(t *testing.T) {
+	testCases := []struct {
+		name       string
+		pattern    []byte
+		resource   []byte
+		nilErr     bool
+		globalSkip bool
+	}{
+		{
+			name:     "image-matches-secret-present",
+			pattern:  []byte(`{"spec": {"containers": [{"name": "*","<(image)": "registry.corp.com/*"}],"imagePullSecrets": [{"name": "regcred"}]}}`),
+			resource: []byte(`{"spec": {"containers": [{"name": "nginx","image": "registry.corp.com/nginx"}],"imagePullSecrets": [{"name": "regcred"}]}}`),
+			nilErr:   true,
+		},
+		{
+			name:     "image-matches-secret-missing",
+			pattern:  []byte(`{"spec": {"containers": [{"name": "*","<(image)": "registry.corp.com/*"}],"imagePullSecrets": [{"name": "regcred"}]}}`),
+			resource: []byte(`{"spec": {"containers": [{"name": "nginx","image": "registry.corp.com/nginx"}],"imagePullSecrets": [{"name": "other"}]}}`),
+			nilErr:   false,
+		},
+		{
+			name:       "image-does-not-match",
+			pattern:    []byte(`{"spec": {"containers": [{"name": "*","<(image)": "registry.corp.com/*"}],"imagePullSecrets": [{"name": "regcred"}]}}`),
+			resource:   []byte(`{"spec": {"containers": [{"name": "nginx","image": "nginx"}],"imagePullSecrets": [{"name": "other"}]}}`),
+			nilErr:     false,
+			globalSkip: true,
+		},
+		{
+			name:     "one-of-many-images-matches",
+			pattern:  []byte(`{"spec": {"containers": [{"name": "*","<(image)": "registry.corp.com/*"}],"imagePullSecrets": [{"name": "regcred"}]}}`),
+			resource: []byte(`{"spec": {"containers": [{"name": "nginx","image": "nginx"},{"name": "app","image": "registry.corp.com/app"}],"imagePullSecrets": [{"name": "other"}]}}`),
+			nilErr:   false,
+		},
+		{
+			name:       "global-anchor-listed-last-at-same-level",
+			pattern:    []byte(`{"spec": {"=(hostNetwork)": false,"<(runtimeClassName)": "gvisor"}}`),
+			resource:   []byte(`{"spec": {"hostNetwork": true,"runtimeClassName": "runc"}}`),
+			nilErr:     false,
+			globalSkip: true,
+		},
+		{
+			name:       "global-anchor-listed-last-in-sibling",
+			pattern:    []byte(`{"spec": {"containers": [{"(name)": "nginx","image": "nginx:1.21"}],"securityContext": {"<(runAsNonRoot)": true}}}`),
+			resource:   []byte(`{"spec": {"containers": [{"name": "nginx","image": "nginx:latest"}],"securityContext": {"runAsNonRoot": false}}}`),
+			nilErr:     false,
+			globalSkip: true,
+		},
+		{
+			name:     "global-anchor-listed-last-matches",
+			pattern:  []byte(`{"spec": {"containers": [{"(name)": "nginx","image": "nginx:1.21"}],"securityContext": {"<(runAsNonRoot)": true}}}`),
+			resource: []byte(`{"spec": {"containers": [{"name": "nginx","image": "nginx:latest"}],"securityContext": {"runAsNonRoot": true}}}`),
+			nilErr:   false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		var pattern, resource interface{}
+		err := json.Unmarshal(testCase.pattern, &pattern)
+		assert.NilError(t, err)
+		err = json.Unmarshal(testCase.resource, &resource)
+		assert.NilError(t, err)
+
+		_, err = ValidateResourceWithPattern(log.Log, resource, pattern)
+		if testCase.nilErr {
+			assert.NilError(t, err, fmt.Sprintf("\ntest: %s\npattern: %s\nresource: %s\n", testCase.name, pattern, resource))
+		} else {
+			assert.Assert(t,
+				err != nil,
+				fmt.Sprintf("\ntest: %s\npattern: %s\nresource: %s\nmsg: %v", testCase.name, pattern, resource, err))
+			assert.Equal(t, common.IsGlobalAnchorError(err.Error()), testCase.globalSkip, testCase.name)
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine/utils"
 	"github.com/kyverno/kyverno/pkg/engine/validate"
 	"github.com/kyverno/kyverno/pkg/engine/variables"
+	"github.com/kyverno/kyverno/pkg/tracing"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -24,10 +25,12 @@ func Validate(policyContext *PolicyContext) (resp *response.EngineResponse) {
 	resp = &response.EngineResponse{}
 	startTime := time.Now()
 
-	logger := buildLogger(policyContext)
+	endSpan := startPolicySpan(policyContext, "engine.validate")
+	logger := tracing.Logger(policyContext.TraceContext, buildLogger(policyContext))
 	logger.V(4).Info("start policy processing", "startTime", startTime)
 	defer func() {
 		buildResponse(logger, policyContext, resp, startTime)
+		endSpan(resp)
 		logger.V(4).Info("finished policy processing", "processingTime", resp.PolicyResponse.ProcessingTime.String(), "validationRulesApplied", resp.PolicyResponse.RulesAppliedCount)
 	}()
 
@@ -67,7 +70,7 @@ func buildResponse(logger logr.Logger, ctx *PolicyContext, resp *response.Engine
 	resp.PolicyResponse.Resource.Namespace = resp.PatchedResource.GetNamespace()
 	resp.PolicyResponse.Resource.Kind = resp.PatchedResource.GetKind()
 	resp.PolicyResponse.Resource.APIVersion = resp.PatchedResource.GetAPIVersion()
-	resp.PolicyResponse.ValidationFailureAction = ctx.Policy.Spec.ValidationFailureAction
+	resp.PolicyResponse.ValidationFailureAction = GetValidationFailureAction(ctx.Policy, resp.PatchedResource, ctx.NamespaceLabels)
 	resp.PolicyResponse.ProcessingTime = time.Since(startTime)
 	resp.PolicyResponse.PolicyExecutionTimestamp = startTime.Unix()
 }
@@ -86,6 +89,9 @@ func validateResource(log logr.Logger, ctx *PolicyContext) *response.EngineRespo
 	ctx.JSONContext.Checkpoint()
 	defer ctx.JSONContext.Restore()
 
+	rules := newRuleSpans(ctx)
+	defer rules.end()
+
 	for _, rule := range ctx.Policy.Spec.Rules {
 		var err error
 
@@ -93,6 +99,8 @@ func validateResource(log logr.Logger, ctx *PolicyContext) *response.EngineRespo
 			continue
 		}
 
+		rules.start(rule.Name)
+
 		log = log.WithValues("rule", rule.Name)
 
 		if !matches(log, rule, ctx) {
@@ -137,9 +145,10 @@ func validateResource(log logr.Logger, ctx *PolicyContext) *response.EngineRespo
 
 			ruleResponse := validateResourceWithRule(log, ctx, *ruleCopy)
 			if ruleResponse != nil {
-				if !common.IsConditionalAnchorError(ruleResponse.Message) {
+				if !common.IsConditionalAnchorError(ruleResponse.Message) && !common.IsGlobalAnchorError(ruleResponse.Message) {
 					incrementAppliedCount(resp)
 					resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, *ruleResponse)
+					rules.setResult(*ruleResponse)
 				}
 			}
 		} else if rule.Validation.Deny != nil {
@@ -169,6 +178,7 @@ func validateResource(log logr.Logger, ctx *PolicyContext) *response.EngineRespo
 
 			incrementAppliedCount(resp)
 			resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, ruleResp)
+			rules.setResult(ruleResp)
 		}
 	}
 
@@ -198,13 +208,13 @@ func validateResourceWithRule(log logr.Logger, ctx *PolicyContext, rule kyverno.
 
 // matches checks if either the new or old resource satisfies the filter conditions defined in the rule
 func matches(logger logr.Logger, rule kyverno.Rule, ctx *PolicyContext) bool {
-	err := MatchesResourceDescription(ctx.NewResource, rule, ctx.AdmissionInfo, ctx.ExcludeGroupRole, ctx.NamespaceLabels)
+	err := MatchesResourceDescription(ctx.NewResource, rule, ctx.AdmissionInfo, ctx.ExcludeGroupRole, ctx.NamespaceLabels, ctx.Subresource)
 	if err == nil {
 		return true
 	}
 
 	if !reflect.DeepEqual(ctx.OldResource, unstructured.Unstructured{}) {
-		err := MatchesResourceDescription(ctx.OldResource, rule, ctx.AdmissionInfo, ctx.ExcludeGroupRole, ctx.NamespaceLabels)
+		err := MatchesResourceDescription(ctx.OldResource, rule, ctx.AdmissionInfo, ctx.ExcludeGroupRole, ctx.NamespaceLabels, ctx.Subresource)
 		if err == nil {
 			return true
 		}
@@ -252,9 +262,17 @@ func validatePatterns(log logr.Logger, ctx context.EvalInterface, resource unstr
 		pattern := validationRule.Pattern
 
 		if path, err := validate.ValidateResourceWithPattern(logger, resource.Object, pattern); err != nil {
+			if common.IsGlobalAnchorError(err.Error()) {
+				logger.V(3).Info("skipping rule as global anchor condition is not satisfied", "reason", err.Error())
+				resp.Success = true
+				resp.Message = err.Error()
+				return resp
+			}
+
 			logger.V(3).Info("validation failed", "path", path, "error", err.Error())
 			resp.Success = false
 			resp.Message = buildErrorMessage(rule, path)
+			resp.Properties = map[string]string{"pattern.path": path}
 			return resp
 		}
 
@@ -266,7 +284,9 @@ func validatePatterns(log logr.Logger, ctx context.EvalInterface, resource unstr
 
 	if validationRule.AnyPattern != nil {
 		var failedAnyPatternsErrors []error
+		var globalAnchorErr error
 		var err error
+		failedPaths := map[string]string{}
 
 		anyPatterns, err := rule.Validation.DeserializeAnyPattern()
 		if err != nil {
@@ -283,9 +303,23 @@ func validatePatterns(log logr.Logger, ctx context.EvalInterface, resource unstr
 				return resp
 			}
 
+			if common.IsGlobalAnchorError(err.Error()) {
+				logger.V(4).Info("global anchor condition is not satisfied", "anyPattern[%d]", idx)
+				globalAnchorErr = err
+				continue
+			}
+
 			logger.V(4).Info("validation rule failed", "anyPattern[%d]", idx, "path", path)
 			patternErr := fmt.Errorf("Rule %s[%d] failed at path %s.", rule.Name, idx, path)
 			failedAnyPatternsErrors = append(failedAnyPatternsErrors, patternErr)
+			failedPaths[fmt.Sprintf("anyPattern[%d].path", idx)] = path
+		}
+
+		// skip the rule if every pattern was skipped by a global anchor
+		if len(failedAnyPatternsErrors) == 0 && globalAnchorErr != nil {
+			resp.Success = true
+			resp.Message = globalAnchorErr.Error()
+			return resp
 		}
 
 		// Any Pattern validation errors
@@ -299,6 +333,7 @@ func validatePatterns(log logr.Logger, ctx context.EvalInterface, resource unstr
 
 			resp.Success = false
 			resp.Message = buildAnyPatternErrorMessage(rule, errorStr)
+			resp.Properties = failedPaths
 			return resp
 		}
 	}
@@ -132,6 +132,8 @@ func TestValidate_image_tag_fail(t *testing.T) {
 		assert.Equal(t, r.Message, msgs[index])
 	}
 	assert.Assert(t, !er.IsSuccessful())
+	assert.Assert(t, er.PolicyResponse.Rules[0].Properties == nil)
+	assert.DeepEqual(t, er.PolicyResponse.Rules[1].Properties, map[string]string{"pattern.path": "/spec/containers/0/imagePullPolicy/"})
 }
 
 func TestValidate_image_tag_pass(t *testing.T) {
@@ -307,6 +309,10 @@ func TestValidate_Fail_anyPattern(t *testing.T) {
 	for index, r := range er.PolicyResponse.Rules {
 		assert.Equal(t, r.Message, msgs[index])
 	}
+	assert.DeepEqual(t, er.PolicyResponse.Rules[0].Properties, map[string]string{
+		"anyPattern[0].path": "/metadata/namespace/",
+		"anyPattern[1].path": "/metadata/namespace/",
+	})
 }
 
 func TestValidate_host_network_port(t *testing.T) {
@@ -2417,3 +2423,166 @@ func Test_StringInDenyCondition(t *testing.T) {
 	er := Validate(&PolicyContext{Policy: policy, NewResource: *resourceUnstructured, JSONContext: ctx})
 	assert.Assert(t, er.IsSuccessful())
 }
+
+func Test_GlobalAnchorSkipsRule(t *testing.T) {
+	policyraw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {
+		  "name": "require-pull-secret"
+		},
+		"spec": {
+		  "rules": [
+			{
+			  "name": "corp-registry-needs-secret",
+			  "match": {
+				"resources": {
+				  "kinds": [
+					"Pod"
+				  ]
+				}
+			  },
+			  "validate": {
+				"pattern": {
+				  "spec": {
+					"containers": [
+					  {
+						"<(image)": "registry.corp.com/*"
+					  }
+					],
+					"imagePullSecrets": [
+					  {
+						"name": "regcred"
+					  }
+					]
+				  }
+				}
+			  }
+			}
+		  ]
+		}
+	  }`)
+
+	testCases := []struct {
+		resource     []byte
+		rulesApplied int
+		success      bool
+	}{
+		{
+			resource:     []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "nginx"},"spec": {"containers": [{"name": "nginx","image": "nginx"}]}}`),
+			rulesApplied: 0,
+		},
+		{
+			resource:     []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "app"},"spec": {"containers": [{"name": "app","image": "registry.corp.com/app"}],"imagePullSecrets": [{"name": "other"}]}}`),
+			rulesApplied: 1,
+			success:      false,
+		},
+		{
+			resource:     []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "app"},"spec": {"containers": [{"name": "app","image": "registry.corp.com/app"}],"imagePullSecrets": [{"name": "regcred"}]}}`),
+			rulesApplied: 1,
+			success:      true,
+		},
+	}
+
+	var policy kyverno.ClusterPolicy
+	err := json.Unmarshal(policyraw, &policy)
+	assert.NilError(t, err)
+
+	for _, tc := range testCases {
+		resourceUnstructured, err := utils.ConvertToUnstructured(tc.resource)
+		assert.NilError(t, err)
+
+		er := Validate(&PolicyContext{Policy: policy, NewResource: *resourceUnstructured, JSONContext: context.NewContext()})
+		assert.Equal(t, len(er.PolicyResponse.Rules), tc.rulesApplied)
+		if tc.rulesApplied > 0 {
+			assert.Equal(t, er.PolicyResponse.Rules[0].Success, tc.success)
+		}
+	}
+}
+
+func Test_SetOperatorsInPreconditionsAndDeny(t *testing.T) {
+	policyRaw := []byte(`{
+	"apiVersion": "kyverno.io/v1",
+	"kind": "ClusterPolicy",
+	"metadata": {
+	  "name": "disallow-capabilities"
+	},
+	"spec": {
+	  "rules": [
+		{
+		  "match": {
+			"resources": {
+			  "kinds": [
+				"Pod"
+			  ]
+			}
+		  },
+		  "name": "adding-capabilities",
+		  "preconditions": {
+			"all": [
+			  {
+				"key": "{{request.object.spec.containers[].image}}",
+				"operator": "AnyNotIn",
+				"value": ["trusted.corp.com/*"]
+			  }
+			]
+		  },
+		  "validate": {
+			"deny": {
+			  "conditions": {
+				"any": [
+				  {
+					"key": "{{request.object.spec.containers[].securityContext.capabilities.add[]}}",
+					"operator": "AnyIn",
+					"value": ["NET_ADMIN", "SYS_*"]
+				  }
+				]
+			  }
+			}
+		  }
+		}
+	  ],
+	  "validationFailureAction": "enforce"
+	}
+  }`)
+
+	testCases := []struct {
+		resource     []byte
+		rulesApplied int
+		success      bool
+	}{
+		{
+			resource:     []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "pod"},"spec": {"containers": [{"name": "a","image": "nginx","securityContext": {"capabilities": {"add": ["CHOWN", "SYS_TIME"]}}}]}}`),
+			rulesApplied: 1,
+			success:      false,
+		},
+		{
+			resource:     []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "pod"},"spec": {"containers": [{"name": "a","image": "nginx","securityContext": {"capabilities": {"add": ["CHOWN"]}}}]}}`),
+			rulesApplied: 1,
+			success:      true,
+		},
+		{
+			resource:     []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "pod"},"spec": {"containers": [{"name": "a","image": "trusted.corp.com/nginx","securityContext": {"capabilities": {"add": ["NET_ADMIN"]}}}]}}`),
+			rulesApplied: 0,
+		},
+	}
+
+	var policy kyverno.ClusterPolicy
+	err := json.Unmarshal(policyRaw, &policy)
+	assert.NilError(t, err)
+
+	for _, tc := range testCases {
+		ctx := context.NewContext()
+		err = ctx.AddResource(tc.resource)
+		assert.NilError(t, err)
+
+		resourceUnstructured, err := utils.ConvertToUnstructured(tc.resource)
+		assert.NilError(t, err)
+
+		er := Validate(&PolicyContext{Policy: policy, NewResource: *resourceUnstructured, JSONContext: ctx})
+		assert.Equal(t, len(er.PolicyResponse.Rules), tc.rulesApplied)
+		if tc.rulesApplied > 0 {
+			assert.Equal(t, er.PolicyResponse.Rules[0].Success, tc.success)
+		}
+	}
+}
@@ -1703,3 +1703,79 @@ func Test_Eval_NotIn_String_Set_Fail(t *testing.T) {
 		t.Error("expected to fail")
 	}
 }
+
+func Test_Eval_SetOperators(t *testing.T) {
+	testCases := []struct {
+		key      interface{}
+		operator kyverno.ConditionOperator
+		value    interface{}
+		result   bool
+	}{
+		// AnyIn
+		{key: []interface{}{"NET_ADMIN", "CHOWN"}, operator: kyverno.AnyIn, value: []interface{}{"NET_ADMIN", "SYS_ADMIN"}, result: true},
+		{key: []interface{}{"CHOWN", "KILL"}, operator: kyverno.AnyIn, value: []interface{}{"NET_ADMIN", "SYS_ADMIN"}, result: false},
+		{key: []interface{}{"CHOWN", "SYS_TIME"}, operator: kyverno.AnyIn, value: []interface{}{"SYS_*"}, result: true},
+		{key: "nginx", operator: kyverno.AnyIn, value: "nginx", result: true},
+		{key: "nginx", operator: kyverno.AnyIn, value: `["busybox", "nginx"]`, result: true},
+		{key: 8080, operator: kyverno.AnyIn, value: []interface{}{"80", "8080"}, result: true},
+		{key: []interface{}{}, operator: kyverno.AnyIn, value: []interface{}{"a"}, result: false},
+		// AllIn
+		{key: []interface{}{"a", "b"}, operator: kyverno.AllIn, value: []interface{}{"a", "b", "c"}, result: true},
+		{key: []interface{}{"a", "d"}, operator: kyverno.AllIn, value: []interface{}{"a", "b", "c"}, result: false},
+		{key: []interface{}{"prod-1", "prod-2"}, operator: kyverno.AllIn, value: "prod-*", result: true},
+		{key: "a", operator: kyverno.AllIn, value: []interface{}{"b"}, result: false},
+		// AnyNotIn
+		{key: []interface{}{"a", "d"}, operator: kyverno.AnyNotIn, value: []interface{}{"a", "b", "c"}, result: true},
+		{key: []interface{}{"a", "b"}, operator: kyverno.AnyNotIn, value: []interface{}{"a", "b", "c"}, result: false},
+		{key: "d", operator: kyverno.AnyNotIn, value: []interface{}{"a"}, result: true},
+		// AllNotIn
+		{key: []interface{}{"CHOWN", "KILL"}, operator: kyverno.AllNotIn, value: []interface{}{"NET_ADMIN", "SYS_*"}, result: true},
+		{key: []interface{}{"CHOWN", "SYS_TIME"}, operator: kyverno.AllNotIn, value: []interface{}{"NET_ADMIN", "SYS_*"}, result: false},
+		{key: "a", operator: kyverno.AllNotIn, value: "a", result: false},
+		// unsupported types
+		{key: map[string]interface{}{"a": "b"}, operator: kyverno.AnyIn, value: []interface{}{"a"}, result: false},
+		{key: "a", operator: kyverno.AllNotIn, value: map[string]interface{}{"a": "b"}, result: false},
+	}
+
+	ctx := context.NewContext()
+	for i, tc := range testCases {
+		condition := kyverno.Condition{
+			Key:      tc.key,
+			Operator: tc.operator,
+			Value:    tc.value,
+		}
+		assert.Equal(t, tc.result, Evaluate(log.Log, ctx, condition), "test case %d failed", i)
+	}
+}
+
+func Test_Eval_Numeric_Quantities_And_Durations(t *testing.T) {
+	testCases := []struct {
+		key      interface{}
+		operator kyverno.ConditionOperator
+		value    interface{}
+		result   bool
+	}{
+		{key: "3Gi", operator: kyverno.GreaterThan, value: "2Gi", result: true},
+		{key: "1Gi", operator: kyverno.GreaterThan, value: "2Gi", result: false},
+		{key: "2048Mi", operator: kyverno.GreaterThanOrEquals, value: "2Gi", result: true},
+		{key: "500m", operator: kyverno.LessThan, value: "1", result: true},
+		{key: "1500m", operator: kyverno.LessThanOrEquals, value: 1, result: false},
+		{key: 2, operator: kyverno.GreaterThan, value: "1500m", result: true},
+		{key: 0.5, operator: kyverno.LessThan, value: "600m", result: true},
+		{key: "2h", operator: kyverno.GreaterThan, value: "90m", result: true},
+		{key: "30s", operator: kyverno.LessThanOrEquals, value: "1m", result: true},
+		{key: "1h", operator: kyverno.LessThan, value: "30m", result: false},
+		{key: "abc", operator: kyverno.LessThan, value: "1Gi", result: false},
+		{key: "1Gi", operator: kyverno.LessThan, value: "abc", result: false},
+	}
+
+	ctx := context.NewContext()
+	for i, tc := range testCases {
+		condition := kyverno.Condition{
+			Key:      tc.key,
+			Operator: tc.operator,
+			Value:    tc.value,
+		}
+		assert.Equal(t, tc.result, Evaluate(log.Log, ctx, condition), "test case %d failed", i)
+	}
+}
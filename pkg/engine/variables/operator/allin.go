@@ -0,0 +1,77 @@
+package operator
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+)
+
+//NewAllInHandler returns handler to manage AllIn operations
+func NewAllInHandler(log logr.Logger, ctx context.EvalInterface) OperatorHandler {
+	return AllInHandler{
+		ctx: ctx,
+		log: log,
+	}
+}
+
+//AllInHandler provides implementation to handle AllIn Operator
+type AllInHandler struct {
+	ctx context.EvalInterface
+	log logr.Logger
+}
+
+//Evaluate evaluates expression with AllIn Operator
+func (allin AllInHandler) Evaluate(key, value interface{}) bool {
+	switch typedKey := key.(type) {
+	case []interface{}:
+		return allin.validateValueWithSlicePattern(typedKey, value)
+	default:
+		keySet, ok := toStringSet(key)
+		if !ok {
+			allin.log.Info("Unsupported type", "value", typedKey, "type", fmt.Sprintf("%T", typedKey))
+			return false
+		}
+		return allin.validateValueWithStringSetPattern(keySet, value)
+	}
+}
+
+func (allin AllInHandler) validateValueWithStringSetPattern(key []string, value interface{}) bool {
+	valueSet, ok := valueToStringSet(value)
+	if !ok {
+		allin.log.Info("expected type []string", "value", value, "type", fmt.Sprintf("%T", value))
+		return false
+	}
+
+	return allKeysExistInSet(key, valueSet)
+}
+
+func (allin AllInHandler) validateValueWithStringPattern(key string, value interface{}) bool {
+	return allin.validateValueWithStringSetPattern([]string{key}, value)
+}
+
+func (allin AllInHandler) validateValueWithBoolPattern(_ bool, _ interface{}) bool {
+	return false
+}
+
+func (allin AllInHandler) validateValueWithIntPattern(_ int64, _ interface{}) bool {
+	return false
+}
+
+func (allin AllInHandler) validateValueWithFloatPattern(_ float64, _ interface{}) bool {
+	return false
+}
+
+func (allin AllInHandler) validateValueWithMapPattern(_ map[string]interface{}, _ interface{}) bool {
+	return false
+}
+
+func (allin AllInHandler) validateValueWithSlicePattern(key []interface{}, value interface{}) bool {
+	keySet, ok := toStringSet(key)
+	if !ok {
+		allin.log.Info("expected key of type []string", "key", key)
+		return false
+	}
+
+	return allin.validateValueWithStringSetPattern(keySet, value)
+}
@@ -0,0 +1,77 @@
+package operator
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+)
+
+//NewAllNotInHandler returns handler to manage AllNotIn operations
+func NewAllNotInHandler(log logr.Logger, ctx context.EvalInterface) OperatorHandler {
+	return AllNotInHandler{
+		ctx: ctx,
+		log: log,
+	}
+}
+
+//AllNotInHandler provides implementation to handle AllNotIn Operator
+type AllNotInHandler struct {
+	ctx context.EvalInterface
+	log logr.Logger
+}
+
+//Evaluate evaluates expression with AllNotIn Operator
+func (allnotin AllNotInHandler) Evaluate(key, value interface{}) bool {
+	switch typedKey := key.(type) {
+	case []interface{}:
+		return allnotin.validateValueWithSlicePattern(typedKey, value)
+	default:
+		keySet, ok := toStringSet(key)
+		if !ok {
+			allnotin.log.Info("Unsupported type", "value", typedKey, "type", fmt.Sprintf("%T", typedKey))
+			return false
+		}
+		return allnotin.validateValueWithStringSetPattern(keySet, value)
+	}
+}
+
+func (allnotin AllNotInHandler) validateValueWithStringSetPattern(key []string, value interface{}) bool {
+	valueSet, ok := valueToStringSet(value)
+	if !ok {
+		allnotin.log.Info("expected type []string", "value", value, "type", fmt.Sprintf("%T", value))
+		return false
+	}
+
+	return !anyKeyExistsInSet(key, valueSet)
+}
+
+func (allnotin AllNotInHandler) validateValueWithStringPattern(key string, value interface{}) bool {
+	return allnotin.validateValueWithStringSetPattern([]string{key}, value)
+}
+
+func (allnotin AllNotInHandler) validateValueWithBoolPattern(_ bool, _ interface{}) bool {
+	return false
+}
+
+func (allnotin AllNotInHandler) validateValueWithIntPattern(_ int64, _ interface{}) bool {
+	return false
+}
+
+func (allnotin AllNotInHandler) validateValueWithFloatPattern(_ float64, _ interface{}) bool {
+	return false
+}
+
+func (allnotin AllNotInHandler) validateValueWithMapPattern(_ map[string]interface{}, _ interface{}) bool {
+	return false
+}
+
+func (allnotin AllNotInHandler) validateValueWithSlicePattern(key []interface{}, value interface{}) bool {
+	keySet, ok := toStringSet(key)
+	if !ok {
+		allnotin.log.Info("expected key of type []string", "key", key)
+		return false
+	}
+
+	return allnotin.validateValueWithStringSetPattern(keySet, value)
+}
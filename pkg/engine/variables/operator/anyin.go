@@ -0,0 +1,157 @@
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/minio/pkg/wildcard"
+)
+
+//NewAnyInHandler returns handler to manage AnyIn operations
+func NewAnyInHandler(log logr.Logger, ctx context.EvalInterface) OperatorHandler {
+	return AnyInHandler{
+		ctx: ctx,
+		log: log,
+	}
+}
+
+//AnyInHandler provides implementation to handle AnyIn Operator
+type AnyInHandler struct {
+	ctx context.EvalInterface
+	log logr.Logger
+}
+
+//Evaluate evaluates expression with AnyIn Operator
+func (anyin AnyInHandler) Evaluate(key, value interface{}) bool {
+	switch typedKey := key.(type) {
+	case []interface{}:
+		return anyin.validateValueWithSlicePattern(typedKey, value)
+	default:
+		keySet, ok := toStringSet(key)
+		if !ok {
+			anyin.log.Info("Unsupported type", "value", typedKey, "type", fmt.Sprintf("%T", typedKey))
+			return false
+		}
+		return anyin.validateValueWithStringSetPattern(keySet, value)
+	}
+}
+
+func (anyin AnyInHandler) validateValueWithStringSetPattern(key []string, value interface{}) bool {
+	valueSet, ok := valueToStringSet(value)
+	if !ok {
+		anyin.log.Info("expected type []string", "value", value, "type", fmt.Sprintf("%T", value))
+		return false
+	}
+
+	return anyKeyExistsInSet(key, valueSet)
+}
+
+func (anyin AnyInHandler) validateValueWithStringPattern(key string, value interface{}) bool {
+	return anyin.validateValueWithStringSetPattern([]string{key}, value)
+}
+
+func (anyin AnyInHandler) validateValueWithBoolPattern(_ bool, _ interface{}) bool {
+	return false
+}
+
+func (anyin AnyInHandler) validateValueWithIntPattern(_ int64, _ interface{}) bool {
+	return false
+}
+
+func (anyin AnyInHandler) validateValueWithFloatPattern(_ float64, _ interface{}) bool {
+	return false
+}
+
+func (anyin AnyInHandler) validateValueWithMapPattern(_ map[string]interface{}, _ interface{}) bool {
+	return false
+}
+
+func (anyin AnyInHandler) validateValueWithSlicePattern(key []interface{}, value interface{}) bool {
+	keySet, ok := toStringSet(key)
+	if !ok {
+		anyin.log.Info("expected key of type []string", "key", key)
+		return false
+	}
+
+	return anyin.validateValueWithStringSetPattern(keySet, value)
+}
+
+// toStringSet converts a scalar or a list of scalars to a list of strings
+func toStringSet(key interface{}) ([]string, bool) {
+	switch typedKey := key.(type) {
+	case []interface{}:
+		var set []string
+		for _, k := range typedKey {
+			s, ok := scalarToString(k)
+			if !ok {
+				return nil, false
+			}
+			set = append(set, s)
+		}
+		return set, true
+	default:
+		s, ok := scalarToString(typedKey)
+		if !ok {
+			return nil, false
+		}
+		return []string{s}, true
+	}
+}
+
+func scalarToString(v interface{}) (string, bool) {
+	switch typed := v.(type) {
+	case string:
+		return typed, true
+	case int, int64, float64, bool:
+		return fmt.Sprint(typed), true
+	default:
+		return "", false
+	}
+}
+
+// valueToStringSet converts the value of a set condition to a list of strings
+// The value can be a scalar, an array of scalars, or a JSON format
+// array of strings (e.g. ["val1", "val2", "val3"].
+func valueToStringSet(value interface{}) ([]string, bool) {
+	if str, ok := value.(string); ok {
+		var arr []string
+		if err := json.Unmarshal([]byte(str), &arr); err == nil {
+			return arr, true
+		}
+		return []string{str}, true
+	}
+
+	return toStringSet(value)
+}
+
+// keyExistsInSet checks if the key matches any of the values, values can contain wildcards
+func keyExistsInSet(key string, values []string) bool {
+	for _, val := range values {
+		if wildcard.Match(val, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyKeyExistsInSet checks if at least one of the keys matches the values
+func anyKeyExistsInSet(keys []string, values []string) bool {
+	for _, key := range keys {
+		if keyExistsInSet(key, values) {
+			return true
+		}
+	}
+	return false
+}
+
+// allKeysExistInSet checks if all the keys match the values
+func allKeysExistInSet(keys []string, values []string) bool {
+	for _, key := range keys {
+		if !keyExistsInSet(key, values) {
+			return false
+		}
+	}
+	return true
+}
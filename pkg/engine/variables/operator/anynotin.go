@@ -0,0 +1,77 @@
+package operator
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+)
+
+//NewAnyNotInHandler returns handler to manage AnyNotIn operations
+func NewAnyNotInHandler(log logr.Logger, ctx context.EvalInterface) OperatorHandler {
+	return AnyNotInHandler{
+		ctx: ctx,
+		log: log,
+	}
+}
+
+//AnyNotInHandler provides implementation to handle AnyNotIn Operator
+type AnyNotInHandler struct {
+	ctx context.EvalInterface
+	log logr.Logger
+}
+
+//Evaluate evaluates expression with AnyNotIn Operator
+func (anynotin AnyNotInHandler) Evaluate(key, value interface{}) bool {
+	switch typedKey := key.(type) {
+	case []interface{}:
+		return anynotin.validateValueWithSlicePattern(typedKey, value)
+	default:
+		keySet, ok := toStringSet(key)
+		if !ok {
+			anynotin.log.Info("Unsupported type", "value", typedKey, "type", fmt.Sprintf("%T", typedKey))
+			return false
+		}
+		return anynotin.validateValueWithStringSetPattern(keySet, value)
+	}
+}
+
+func (anynotin AnyNotInHandler) validateValueWithStringSetPattern(key []string, value interface{}) bool {
+	valueSet, ok := valueToStringSet(value)
+	if !ok {
+		anynotin.log.Info("expected type []string", "value", value, "type", fmt.Sprintf("%T", value))
+		return false
+	}
+
+	return !allKeysExistInSet(key, valueSet)
+}
+
+func (anynotin AnyNotInHandler) validateValueWithStringPattern(key string, value interface{}) bool {
+	return anynotin.validateValueWithStringSetPattern([]string{key}, value)
+}
+
+func (anynotin AnyNotInHandler) validateValueWithBoolPattern(_ bool, _ interface{}) bool {
+	return false
+}
+
+func (anynotin AnyNotInHandler) validateValueWithIntPattern(_ int64, _ interface{}) bool {
+	return false
+}
+
+func (anynotin AnyNotInHandler) validateValueWithFloatPattern(_ float64, _ interface{}) bool {
+	return false
+}
+
+func (anynotin AnyNotInHandler) validateValueWithMapPattern(_ map[string]interface{}, _ interface{}) bool {
+	return false
+}
+
+func (anynotin AnyNotInHandler) validateValueWithSlicePattern(key []interface{}, value interface{}) bool {
+	keySet, ok := toStringSet(key)
+	if !ok {
+		anynotin.log.Info("expected key of type []string", "key", key)
+		return false
+	}
+
+	return anynotin.validateValueWithStringSetPattern(keySet, value)
+}
@@ -3,13 +3,16 @@ package operator
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/engine/context"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 //NewNumericOperatorHandler returns handler to manage the provided numeric operations (>, >=, <=, <)
+// on numbers, and on durations or resource quantities provided as strings
 func NewNumericOperatorHandler(log logr.Logger, ctx context.EvalInterface, op kyverno.ConditionOperator) OperatorHandler {
 	return NumericOperatorHandler{
 		ctx:       ctx,
@@ -76,6 +79,9 @@ func (noh NumericOperatorHandler) validateValueWithIntPattern(key int64, value i
 		if err == nil {
 			return compareByCondition(float64(key), float64(int64val), noh.condition, &noh.log)
 		}
+		if result, ok := noh.compareQuantitiesOrDurations(strconv.FormatInt(key, 10), typedValue); ok {
+			return result
+		}
 		noh.log.Error(fmt.Errorf("Parse Error: "), "Failed to parse both float64 and int64 from the string value")
 		return false
 	default:
@@ -101,6 +107,9 @@ func (noh NumericOperatorHandler) validateValueWithFloatPattern(key float64, val
 		if err == nil {
 			return compareByCondition(key, float64(int64val), noh.condition, &noh.log)
 		}
+		if result, ok := noh.compareQuantitiesOrDurations(strconv.FormatFloat(key, 'f', -1, 64), typedValue); ok {
+			return result
+		}
 		noh.log.Error(fmt.Errorf("Parse Error: "), "Failed to parse both float64 and int64 from the string value")
 		return false
 	default:
@@ -120,10 +129,47 @@ func (noh NumericOperatorHandler) validateValueWithStringPattern(key string, val
 	if err == nil {
 		return noh.validateValueWithIntPattern(int64key, value)
 	}
-	noh.log.Error(err, "Failed to parse both float64 and int64 from the string keyt")
+	// comparing the key and the value as durations or quantities because numeric extraction failed
+	if result, ok := noh.compareQuantitiesOrDurations(key, value); ok {
+		return result
+	}
+	noh.log.Error(err, "Failed to parse float64, int64, duration or quantity from the string key")
 	return false
 }
 
+// compareQuantitiesOrDurations compares the key and the value as durations (e.g. 1h30m) when both parse as durations,
+// or else as resource quantities (e.g. 2Gi, 500m) when both parse as quantities.
+// The second return value is false if the key and the value could not be compared.
+func (noh NumericOperatorHandler) compareQuantitiesOrDurations(key string, value interface{}) (bool, bool) {
+	var strValue string
+	switch typedValue := value.(type) {
+	case string:
+		strValue = typedValue
+	case int:
+		strValue = strconv.Itoa(typedValue)
+	case int64:
+		strValue = strconv.FormatInt(typedValue, 10)
+	case float64:
+		strValue = strconv.FormatFloat(typedValue, 'f', -1, 64)
+	default:
+		return false, false
+	}
+
+	durationKey, errKey := time.ParseDuration(key)
+	durationValue, errValue := time.ParseDuration(strValue)
+	if errKey == nil && errValue == nil {
+		return compareByCondition(float64(durationKey), float64(durationValue), noh.condition, &noh.log), true
+	}
+
+	quantityKey, errKey := resource.ParseQuantity(key)
+	quantityValue, errValue := resource.ParseQuantity(strValue)
+	if errKey == nil && errValue == nil {
+		return compareByCondition(float64(quantityKey.Cmp(quantityValue)), 0, noh.condition, &noh.log), true
+	}
+
+	return false, false
+}
+
 // the following functions are unreachable because the key is strictly supposed to be numeric
 // still the following functions are just created to make NumericOperatorHandler struct implement OperatorHandler interface
 func (noh NumericOperatorHandler) validateValueWithBoolPattern(key bool, value interface{}) bool {
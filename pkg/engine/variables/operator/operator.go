@@ -45,6 +45,18 @@ func CreateOperatorHandler(log logr.Logger, ctx context.EvalInterface, op kyvern
 	case strings.ToLower(string(kyverno.NotIn)):
 		return NewNotInHandler(log, ctx)
 
+	case strings.ToLower(string(kyverno.AnyIn)):
+		return NewAnyInHandler(log, ctx)
+
+	case strings.ToLower(string(kyverno.AllIn)):
+		return NewAllInHandler(log, ctx)
+
+	case strings.ToLower(string(kyverno.AnyNotIn)):
+		return NewAnyNotInHandler(log, ctx)
+
+	case strings.ToLower(string(kyverno.AllNotIn)):
+		return NewAllNotInHandler(log, ctx)
+
 	case strings.ToLower(string(kyverno.GreaterThanOrEquals)),
 		strings.ToLower(string(kyverno.GreaterThan)),
 		strings.ToLower(string(kyverno.LessThanOrEquals)),
@@ -1,6 +1,7 @@
 package event
 
 import (
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -18,6 +19,7 @@ import (
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 )
@@ -42,9 +44,28 @@ type Generator struct {
 	// events generated at namespaced policy controller to process 'generate' rule
 	genPolicyRecorder record.EventRecorder
 	resCache          resourcecache.ResourceCache
-	log               logr.Logger
+	// limiters rate limit the events of each policy
+	limiters    map[string]flowcontrol.RateLimiter
+	limitersMux sync.Mutex
+	log         logr.Logger
 }
 
+const (
+	// policyEventsQPS and policyEventsBurst limit the rate of the events caused by a policy,
+	// the events exceeding the limit are dropped
+	policyEventsQPS   = 2
+	policyEventsBurst = 100
+
+	// correlatorBurstSize and correlatorQPS limit the rate of the events recorded for an object
+	correlatorBurstSize = 25
+	correlatorQPS       = 1.0 / 60
+
+	// events with the same reason on an object are combined into a single event once
+	// there are more than correlatorMaxEvents of them within correlatorMaxIntervalInSeconds
+	correlatorMaxEvents            = 5
+	correlatorMaxIntervalInSeconds = 600
+)
+
 //Interface to generate event
 type Interface interface {
 	Add(infoList ...Info)
@@ -64,8 +85,14 @@ func NewEventGenerator(client *client.Client, cpInformer kyvernoinformer.Cluster
 		admissionCtrRecorder: initRecorder(client, AdmissionController, log),
 		genPolicyRecorder:    initRecorder(client, GeneratePolicyController, log),
 		resCache:             resCache,
+		limiters:             make(map[string]flowcontrol.RateLimiter),
 		log:                  log,
 	}
+
+	// the rate limiters of the deleted policies are removed
+	deleteHandler := cache.ResourceEventHandlerFuncs{DeleteFunc: gen.deletePolicy}
+	cpInformer.Informer().AddEventHandler(deleteHandler)
+	pInformer.Informer().AddEventHandler(deleteHandler)
 	return &gen
 }
 
@@ -80,7 +107,13 @@ func initRecorder(client *client.Client, eventSource Source, log logr.Logger) re
 		log.Error(err, "failed to add to scheme")
 		return nil
 	}
-	eventBroadcaster := record.NewBroadcaster()
+	// duplicate events are counted on a single event and similar events are aggregated
+	eventBroadcaster := record.NewBroadcasterWithCorrelatorOptions(record.CorrelatorOptions{
+		BurstSize:            correlatorBurstSize,
+		QPS:                  correlatorQPS,
+		MaxEvents:            correlatorMaxEvents,
+		MaxIntervalInSeconds: correlatorMaxIntervalInSeconds,
+	})
 	eventBroadcaster.StartLogging(klog.V(5).Infof)
 	eventInterface, err := client.GetEventsInterface()
 	if err != nil {
@@ -106,10 +139,80 @@ func (gen *Generator) Add(infos ...Info) {
 			logger.V(4).Info("not creating an event as the resource has not been assigned a name yet", "kind", info.Kind, "name", info.Name, "namespace", info.Namespace)
 			continue
 		}
+
+		if !gen.isEnabled(info) {
+			logger.V(5).Info("event disabled by the policy", "kind", info.Kind, "name", info.Name, "namespace", info.Namespace, "reason", info.Reason)
+			continue
+		}
+
+		if !gen.allow(info) {
+			logger.V(4).Info("dropping event, the policy exceeded the event rate limit", "kind", info.Kind, "name", info.Name, "namespace", info.Namespace, "reason", info.Reason)
+			continue
+		}
+
 		gen.queue.Add(info)
 	}
 }
 
+// isEnabled checks the event reason against the event annotations of the policy that caused the event
+func (gen *Generator) isEnabled(info Info) bool {
+	namespace, name := info.policy()
+	if name == "" {
+		return true
+	}
+
+	var annotations map[string]string
+	if namespace == "" {
+		if cpol, err := gen.cpLister.Get(name); err == nil {
+			annotations = cpol.GetAnnotations()
+		}
+	} else {
+		if pol, err := gen.pLister.Policies(namespace).Get(name); err == nil {
+			annotations = pol.GetAnnotations()
+		}
+	}
+
+	return enabledReasons(annotations, info.isPolicyEvent())[info.Reason]
+}
+
+// allow takes a token from the rate limiter of the policy that caused the event
+func (gen *Generator) allow(info Info) bool {
+	namespace, name := info.policy()
+	if name == "" {
+		return true
+	}
+
+	key := namespace + "/" + name
+	gen.limitersMux.Lock()
+	limiter, ok := gen.limiters[key]
+	if !ok {
+		limiter = flowcontrol.NewTokenBucketRateLimiter(policyEventsQPS, policyEventsBurst)
+		gen.limiters[key] = limiter
+	}
+	gen.limitersMux.Unlock()
+
+	return limiter.TryAccept()
+}
+
+// deletePolicy removes the rate limiter of a deleted policy
+func (gen *Generator) deletePolicy(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		gen.log.Error(err, "failed to get the key of the deleted policy")
+		return
+	}
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		gen.log.Error(err, "failed to parse the key of the deleted policy", "key", key)
+		return
+	}
+
+	gen.limitersMux.Lock()
+	delete(gen.limiters, namespace+"/"+name)
+	gen.limitersMux.Unlock()
+}
+
 // Run begins generator
 func (gen *Generator) Run(workers int, stopCh <-chan struct{}) {
 	logger := gen.log
@@ -212,7 +315,7 @@ func (gen *Generator) syncHandler(key Info) error {
 
 	// set the event type based on reason
 	eventType := v1.EventTypeWarning
-	if key.Reason == PolicyApplied.String() {
+	if isNormal(key.Reason) {
 		eventType = v1.EventTypeNormal
 	}
 
@@ -0,0 +1,91 @@
+package event
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ResourceEventsAnnotation lists the reasons of the events a policy creates on the resources it applies to
+	ResourceEventsAnnotation = "events.kyverno.io/resource"
+
+	// PolicyEventsAnnotation lists the reasons of the events a policy creates on itself
+	PolicyEventsAnnotation = "events.kyverno.io/policy"
+
+	// noEvents disables the events when set as the value of the annotations
+	noEvents = "none"
+)
+
+// defaultResourceEvents are created on resources when the policy does not set ResourceEventsAnnotation
+var defaultResourceEvents = newReasonSet(PolicyViolation, PolicyFailed, PolicyError)
+
+// defaultPolicyEvents are created on policies when the policy does not set PolicyEventsAnnotation
+var defaultPolicyEvents = newReasonSet(PolicyViolation, PolicyApplied, PolicyFailed, PolicyError, ResourceMutated, ResourceGenerated, ImageVerified)
+
+type reasonSet map[string]bool
+
+func newReasonSet(reasons ...Reason) reasonSet {
+	set := reasonSet{}
+	for _, r := range reasons {
+		set[r.String()] = true
+	}
+	return set
+}
+
+// parseReasons parses the comma separated list of reasons of the event annotations,
+// "none" disables all events
+func parseReasons(value string) (reasonSet, error) {
+	set := reasonSet{}
+	if strings.TrimSpace(value) == noEvents {
+		return set, nil
+	}
+
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		r, err := ParseReason(name)
+		if err != nil {
+			return nil, err
+		}
+		set[r.String()] = true
+	}
+
+	return set, nil
+}
+
+// ValidateAnnotations checks the event annotations of a policy
+func ValidateAnnotations(annotations map[string]string) error {
+	for _, key := range []string{ResourceEventsAnnotation, PolicyEventsAnnotation} {
+		if value, ok := annotations[key]; ok {
+			if _, err := parseReasons(value); err != nil {
+				return fmt.Errorf("invalid annotation %s: %v", key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// enabledReasons returns the reasons of the events created on the policy, or on the resources
+// the policy applies to. Invalid annotations are ignored.
+func enabledReasons(annotations map[string]string, onPolicy bool) reasonSet {
+	key, defaults := ResourceEventsAnnotation, defaultResourceEvents
+	if onPolicy {
+		key, defaults = PolicyEventsAnnotation, defaultPolicyEvents
+	}
+
+	value, ok := annotations[key]
+	if !ok {
+		return defaults
+	}
+
+	set, err := parseReasons(value)
+	if err != nil {
+		return defaults
+	}
+
+	return set
+}
@@ -0,0 +1,100 @@
+package event
+
+import (
+	"fmt"
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_ParseReasons(t *testing.T) {
+	set, err := parseReasons("PolicyViolation, ResourceMutated,")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, set, newReasonSet(PolicyViolation, ResourceMutated))
+
+	set, err = parseReasons("none")
+	assert.NilError(t, err)
+	assert.Equal(t, len(set), 0)
+
+	_, err = parseReasons("PolicyViolation,Unknown")
+	assert.ErrorContains(t, err, "unknown event reason Unknown")
+}
+
+func Test_ValidateAnnotations(t *testing.T) {
+	assert.NilError(t, ValidateAnnotations(map[string]string{ResourceEventsAnnotation: "PolicySkipped", PolicyEventsAnnotation: "none"}))
+	assert.ErrorContains(t, ValidateAnnotations(map[string]string{PolicyEventsAnnotation: "Mutated"}), PolicyEventsAnnotation)
+}
+
+func newTestGenerator(policies ...*kyverno.ClusterPolicy) *Generator {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, p := range policies {
+		_ = indexer.Add(p)
+	}
+
+	return &Generator{
+		cpLister: kyvernolister.NewClusterPolicyLister(indexer),
+		pLister:  kyvernolister.NewPolicyLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		queue:    workqueue.NewRateLimitingQueue(rateLimiter()),
+		limiters: make(map[string]flowcontrol.RateLimiter),
+		log:      log.Log,
+	}
+}
+
+func Test_Generator_FiltersEvents(t *testing.T) {
+	policy := &kyverno.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "add-labels",
+			Annotations: map[string]string{
+				ResourceEventsAnnotation: "ResourceMutated",
+				PolicyEventsAnnotation:   "none",
+			},
+		},
+	}
+	gen := newTestGenerator(policy)
+
+	gen.Add(
+		Info{Kind: "ClusterPolicy", Name: "add-labels", Reason: ResourceMutated.String()},
+		Info{Kind: "Pod", Namespace: "default", Name: "nginx", Reason: ResourceMutated.String(), PolicyName: "add-labels"},
+		Info{Kind: "Pod", Namespace: "default", Name: "nginx", Reason: PolicyViolation.String(), PolicyName: "add-labels"},
+		// policies without annotations use the defaults
+		Info{Kind: "ClusterPolicy", Name: "require-labels", Reason: PolicyApplied.String()},
+		Info{Kind: "Pod", Namespace: "default", Name: "nginx", Reason: PolicySkipped.String(), PolicyName: "require-labels"},
+		// events that are not caused by a policy are not filtered
+		Info{Kind: "Deployment", Namespace: "kyverno", Name: "kyverno", Reason: "Update"},
+	)
+
+	assert.Equal(t, gen.queue.Len(), 3)
+}
+
+func Test_Generator_RateLimitsPolicies(t *testing.T) {
+	gen := newTestGenerator()
+	for i := 0; i < policyEventsBurst+10; i++ {
+		gen.Add(Info{Kind: "ClusterPolicy", Name: "noisy", Reason: PolicyViolation.String(), Message: fmt.Sprintf("event %d", i)})
+	}
+	assert.Assert(t, gen.queue.Len() <= policyEventsBurst+1)
+
+	assert.Assert(t, gen.allow(Info{Kind: "ClusterPolicy", Name: "quiet", Reason: PolicyViolation.String()}))
+}
+
+func Test_Generator_RemovesLimitersOfDeletedPolicies(t *testing.T) {
+	gen := newTestGenerator()
+	gen.allow(Info{Kind: "ClusterPolicy", Name: "add-labels", Reason: PolicyViolation.String()})
+	gen.allow(Info{Kind: "Pod", Namespace: "default", Name: "nginx", Reason: PolicyViolation.String(), PolicyNamespace: "default", PolicyName: "add-labels"})
+	assert.Equal(t, len(gen.limiters), 2)
+
+	gen.deletePolicy(&kyverno.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "add-labels"}})
+	assert.Equal(t, len(gen.limiters), 1)
+
+	gen.deletePolicy(cache.DeletedFinalStateUnknown{
+		Key: "default/add-labels",
+		Obj: &kyverno.Policy{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "add-labels"}},
+	})
+	assert.Equal(t, len(gen.limiters), 0)
+}
@@ -13,6 +13,11 @@ const (
 	FPolicyApply = iota
 	FResourcePolicyApply
 	SPolicyApply
+	SResourcePolicyApply
+	SkipPolicyApply
+	SkipResourcePolicyApply
+	EPolicyApply
+	EResourcePolicyApply
 )
 
 func (k MsgKey) String() string {
@@ -20,6 +25,11 @@ func (k MsgKey) String() string {
 		"Rule(s) '%s' failed to apply on resource %s",
 		"Rule(s) '%s' of policy '%s' failed to apply on the resource",
 		"Rule(s) '%s' successfully applied on resource %s",
+		"Rule(s) '%s' of policy '%s' successfully applied on the resource",
+		"No rule applied on resource %s",
+		"No rule of policy '%s' applied on the resource",
+		"Failed to apply the policy on resource %s: %v",
+		"Failed to apply policy '%s' on the resource: %v",
 	}[k]
 }
 
@@ -1,5 +1,10 @@
 package event
 
+import (
+	"fmt"
+	"strings"
+)
+
 //Reason types of Event Reasons
 type Reason int
 
@@ -10,12 +15,50 @@ const (
 	PolicyApplied
 	//PolicyFailed policy failed
 	PolicyFailed
+	//PolicySkipped policy matched the resource but none of its rules applied
+	PolicySkipped
+	//PolicyError policy could not be processed
+	PolicyError
+	//ResourceMutated resource mutated by a policy
+	ResourceMutated
+	//ResourceGenerated resource generated by a policy
+	ResourceGenerated
+	//ImageVerified images of the resource verified by a policy
+	ImageVerified
 )
 
+var reasons = [...]string{
+	"PolicyViolation",
+	"PolicyApplied",
+	"PolicyFailed",
+	"PolicySkipped",
+	"PolicyError",
+	"ResourceMutated",
+	"ResourceGenerated",
+	"ImageVerified",
+}
+
 func (r Reason) String() string {
-	return [...]string{
-		"PolicyViolation",
-		"PolicyApplied",
-		"PolicyFailed",
-	}[r]
+	return reasons[r]
+}
+
+// ParseReason returns the reason with the given name
+func ParseReason(name string) (Reason, error) {
+	for i, r := range reasons {
+		if r == name {
+			return Reason(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown event reason %s, must be one of %s", name, strings.Join(reasons[:], ", "))
+}
+
+// isNormal returns true for the reasons that are reported as Normal events, the other reasons are Warning events
+func isNormal(reason string) bool {
+	switch reason {
+	case PolicyApplied.String(), PolicySkipped.String(), ResourceMutated.String(), ResourceGenerated.String(), ImageVerified.String():
+		return true
+	}
+
+	return false
 }
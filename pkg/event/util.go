@@ -12,4 +12,22 @@ type Info struct {
 	Reason    string
 	Message   string
 	Source    Source
+
+	// PolicyName and PolicyNamespace identify the policy that caused an event on a resource,
+	// the events of the policy are filtered and rate limited with the policy settings
+	PolicyName      string
+	PolicyNamespace string
+}
+
+// isPolicyEvent returns true for the events on a policy
+func (i Info) isPolicyEvent() bool {
+	return i.Kind == "ClusterPolicy" || i.Kind == "Policy"
+}
+
+// policy returns the namespace and name of the policy the event belongs to
+func (i Info) policy() (namespace, name string) {
+	if i.isPolicyEvent() {
+		return i.Namespace, i.Name
+	}
+	return i.PolicyNamespace, i.PolicyName
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/engine/utils"
 	"github.com/kyverno/kyverno/pkg/engine/variables"
+	"github.com/kyverno/kyverno/pkg/tracing"
 	kyvernoutils "github.com/kyverno/kyverno/pkg/utils"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,9 +26,17 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 )
 
-func (c *Controller) processGR(gr *kyverno.GenerateRequest) error {
-	logger := c.log.WithValues("name", gr.Name, "policy", gr.Spec.Policy, "kind", gr.Spec.Resource.Kind, "apiVersion", gr.Spec.Resource.APIVersion, "namespace", gr.Spec.Resource.Namespace, "name", gr.Spec.Resource.Name)
-	var err error
+func (c *Controller) processGR(gr *kyverno.GenerateRequest) (err error) {
+	traceCtx, span := tracing.StartSpan(contextdefault.Background(), "generate.processGR",
+		append(tracing.ResourceAttributes(gr.Spec.Resource.Kind, gr.Spec.Resource.Namespace, gr.Spec.Resource.Name),
+			tracing.GenerateRequestKey.String(gr.Name),
+			tracing.PolicyKey.String(gr.Spec.Policy))...)
+	defer func() {
+		tracing.SetError(span, err)
+		span.End()
+	}()
+
+	logger := tracing.Logger(traceCtx, c.log).WithValues("name", gr.Name, "policy", gr.Spec.Policy, "kind", gr.Spec.Resource.Kind, "apiVersion", gr.Spec.Resource.APIVersion, "namespace", gr.Spec.Resource.Namespace, "name", gr.Spec.Resource.Name)
 	var resource *unstructured.Unstructured
 	var genResources []kyverno.ResourceSpec
 
@@ -46,7 +55,7 @@ func (c *Controller) processGR(gr *kyverno.GenerateRequest) error {
 
 	// 2 - Apply the generate policy on the resource
 	namespaceLabels := pkgcommon.GetNamespaceSelectorsFromGenericInformer(resource.GetKind(), resource.GetNamespace(), c.nsInformer, logger)
-	genResources, err = c.applyGenerate(*resource, *gr, namespaceLabels)
+	genResources, err = c.applyGenerate(traceCtx, *resource, *gr, namespaceLabels)
 
 	if err != nil {
 		// Need not update the stauts when policy doesn't apply on resource, because all the generate requests are removed by the cleanup controller
@@ -58,6 +67,8 @@ func (c *Controller) processGR(gr *kyverno.GenerateRequest) error {
 		// 3 - Report failure Events
 		events := failedEvents(err, *gr, *resource)
 		c.eventGen.Add(events...)
+	} else if len(genResources) != 0 {
+		c.eventGen.Add(generatedEvents(*gr, *resource, genResources)...)
 	}
 
 	// 4 - Update Status
@@ -66,7 +77,7 @@ func (c *Controller) processGR(gr *kyverno.GenerateRequest) error {
 
 const doesNotApply = "policy does not apply to resource"
 
-func (c *Controller) applyGenerate(resource unstructured.Unstructured, gr kyverno.GenerateRequest, namespaceLabels map[string]string) ([]kyverno.ResourceSpec, error) {
+func (c *Controller) applyGenerate(traceCtx contextdefault.Context, resource unstructured.Unstructured, gr kyverno.GenerateRequest, namespaceLabels map[string]string) ([]kyverno.ResourceSpec, error) {
 	logger := c.log.WithValues("name", gr.Name, "policy", gr.Spec.Policy, "kind", gr.Spec.Resource.Kind, "apiVersion", gr.Spec.Resource.APIVersion, "namespace", gr.Spec.Resource.Namespace, "name", gr.Spec.Resource.Name)
 	// Get the list of rules to be applied
 	// get policy
@@ -137,6 +148,7 @@ func (c *Controller) applyGenerate(resource unstructured.Unstructured, gr kyvern
 		JSONContext:         ctx,
 		NamespaceLabels:     namespaceLabels,
 		Client:              c.client,
+		TraceContext:        traceCtx,
 	}
 
 	// check if the policy still applies to the resource
@@ -16,6 +16,32 @@ func failedEvents(err error, gr kyverno.GenerateRequest, resource unstructured.U
 	re.Reason = event.PolicyFailed.String()
 	re.Source = event.GeneratePolicyController
 	re.Message = fmt.Sprintf("policy %s failed to apply: %v", gr.Spec.Policy, err)
+	re.PolicyName = gr.Spec.Policy
 
 	return []event.Info{re}
 }
+
+func generatedEvents(gr kyverno.GenerateRequest, resource unstructured.Unstructured, genResources []kyverno.ResourceSpec) []event.Info {
+	generated := make([]string, 0, len(genResources))
+	for _, r := range genResources {
+		generated = append(generated, r.Kind+"/"+r.Namespace+"/"+r.Name)
+	}
+
+	pe := event.Info{}
+	pe.Kind = "ClusterPolicy"
+	pe.Name = gr.Spec.Policy
+	pe.Reason = event.ResourceGenerated.String()
+	pe.Source = event.GeneratePolicyController
+	pe.Message = fmt.Sprintf("resources %v generated for resource %s/%s/%s", generated, resource.GetKind(), resource.GetNamespace(), resource.GetName())
+
+	re := event.Info{}
+	re.Kind = resource.GetKind()
+	re.Namespace = resource.GetNamespace()
+	re.Name = resource.GetName()
+	re.Reason = event.ResourceGenerated.String()
+	re.Source = event.GeneratePolicyController
+	re.Message = fmt.Sprintf("policy %s generated resources %v", gr.Spec.Policy, generated)
+	re.PolicyName = gr.Spec.Policy
+
+	return []event.Info{pe, re}
+}
@@ -60,6 +60,9 @@ To apply on a resource:
 To apply on a cluster:
 	kyverno apply /path/to/policy.yaml /path/to/folderOfPolicies --cluster
 
+To report validation failures of policies in audit mode (including per-namespace overrides) as warnings:
+	kyverno apply /path/to/policy.yaml --resource=/path/to/resource1 --audit-warn
+
 
 To apply policy with variables:
 
@@ -108,7 +111,7 @@ More info: https://kyverno.io/docs/kyverno-cli/
 func Command() *cobra.Command {
 	var cmd *cobra.Command
 	var resourcePaths []string
-	var cluster, policyReport, stdin bool
+	var cluster, policyReport, stdin, auditWarn bool
 	var mutateLogPath, variablesString, valuesFile, namespace string
 
 	cmd = &cobra.Command{
@@ -125,7 +128,7 @@ func Command() *cobra.Command {
 				}
 			}()
 
-			validateEngineResponses, rc, resources, skippedPolicies, err := applyCommandHelper(resourcePaths, cluster, policyReport, mutateLogPath, variablesString, valuesFile, namespace, policyPaths, stdin)
+			validateEngineResponses, rc, resources, skippedPolicies, err := applyCommandHelper(resourcePaths, cluster, policyReport, mutateLogPath, variablesString, valuesFile, namespace, policyPaths, stdin, auditWarn)
 			if err != nil {
 				return err
 			}
@@ -144,11 +147,12 @@ func Command() *cobra.Command {
 	cmd.Flags().BoolVarP(&policyReport, "policy-report", "", false, "Generates policy report when passed (default policyviolation r")
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Optional Policy parameter passed with cluster flag")
 	cmd.Flags().BoolVarP(&stdin, "stdin", "i", false, "Optional mutate policy parameter to pipe directly through to kubectl")
+	cmd.Flags().BoolVarP(&auditWarn, "audit-warn", "", false, "Reports validation failures of policies in audit mode as warnings, they do not fail the command")
 	return cmd
 }
 
 func applyCommandHelper(resourcePaths []string, cluster bool, policyReport bool, mutateLogPath string,
-	variablesString string, valuesFile string, namespace string, policyPaths []string, stdin bool) (validateEngineResponses []*response.EngineResponse, rc *resultCounts, resources []*unstructured.Unstructured, skippedPolicies []SkippedPolicy, err error) {
+	variablesString string, valuesFile string, namespace string, policyPaths []string, stdin bool, auditWarn bool) (validateEngineResponses []*response.EngineResponse, rc *resultCounts, resources []*unstructured.Unstructured, skippedPolicies []SkippedPolicy, err error) {
 
 	store.SetMock(true)
 	kubernetesConfig := genericclioptions.NewConfigFlags(true)
@@ -306,7 +310,12 @@ func applyCommandHelper(resourcePaths []string, cluster bool, policyReport bool,
 				return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError(fmt.Errorf("failed to apply policy %v on resource %v", policy.Name, resource.GetName()).Error(), err)
 			}
 			if responseError == true {
-				rc.fail++
+				// the validationFailureAction of the response takes the per-namespace overrides into account
+				if auditWarn && !validateErs.IsSuccessful() && validateErs.PolicyResponse.ValidationFailureAction != pkgCommon.Enforce {
+					rc.warn++
+				} else {
+					rc.fail++
+				}
 			} else {
 				rc.pass++
 			}
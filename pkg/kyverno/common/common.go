@@ -500,7 +500,8 @@ func MutatePolices(policies []*v1.ClusterPolicy) ([]*v1.ClusterPolicy, error) {
 			}
 			return nil, err
 		}
-		newPolicies = append(newPolicies, p)
+		// rules for pod controllers are auto-generated at runtime, as done by the policy cache
+		newPolicies = append(newPolicies, policymutation.ExpandPolicy(p, engine.PodControllers, logger))
 	}
 	return newPolicies, nil
 }
@@ -529,6 +530,12 @@ func ApplyPolicyOnResource(policy *v1.ClusterPolicy, resource *unstructured.Unst
 		}
 	}
 
+	for _, override := range policy.Spec.ValidationFailureActionOverrides {
+		if override.NamespaceSelector != nil {
+			policyWithNamespaceSelector = true
+		}
+	}
+
 	if policyWithNamespaceSelector {
 		resourceNamespace := resource.GetNamespace()
 		namespaceLabels = namespaceSelectorMap[resource.GetNamespace()]
@@ -0,0 +1,214 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	policyreport "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	kyvernoclient "github.com/kyverno/kyverno/pkg/client/clientset/versioned"
+	sanitizederror "github.com/kyverno/kyverno/pkg/kyverno/sanitizedError"
+	"github.com/kyverno/kyverno/pkg/policyreport/query"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var reportHelp = `
+To list the failed results of the policy reports in the current cluster context:
+	kyverno report --status fail
+
+To filter the results by policy, namespace and resource kind:
+	kyverno report --policy require-labels --namespace default --kind Pod
+
+To query the report API served by Kyverno (--enable-report-api), with JSON output:
+	kyverno report --server http://localhost:8001 --status fail,error -o json
+
+Filters accept comma-separated values. Results are paginated with --limit and --offset,
+set --limit to 0 to print all the results.
+`
+
+// Command returns report command
+func Command() *cobra.Command {
+	var q query.Query
+	var server, output string
+
+	// the --namespace and --server flags filter the results and select the report API
+	configFlags := genericclioptions.NewConfigFlags(true)
+	configFlags.Namespace = nil
+	configFlags.APIServer = nil
+
+	cmd := &cobra.Command{
+		Use:     "report",
+		Short:   "queries the results of policy reports",
+		Example: reportHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "table" && output != "json" {
+				return sanitizederror.New(fmt.Sprintf("invalid output format %q, must be table or json", output))
+			}
+
+			// the flags are parsed the way the report API parses URL parameters
+			q, err := query.ParseQuery(q.Values())
+			if err != nil {
+				return sanitizederror.NewWithError("invalid query", err)
+			}
+
+			var resp query.Response
+			if server != "" {
+				resp, err = queryServer(server, q)
+			} else {
+				resp, err = queryCluster(configFlags, q)
+			}
+			if err != nil {
+				return err
+			}
+
+			if output == "json" {
+				return printJSON(os.Stdout, resp)
+			}
+
+			printTable(os.Stdout, resp)
+			return nil
+		},
+	}
+
+	configFlags.AddFlags(cmd.PersistentFlags())
+	cmd.Flags().StringVar(&server, "server", "", "URL of the Kyverno report API, the reports are read from the cluster in the current context when not set")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format, one of table or json")
+	cmd.Flags().StringSliceVar(&q.Policies, "policy", nil, "Filter results by policy name")
+	cmd.Flags().StringSliceVar(&q.Rules, "rule", nil, "Filter results by rule name")
+	cmd.Flags().StringSliceVar(&q.Statuses, "status", nil, "Filter results by status, one of pass, fail, warn, error or skip")
+	cmd.Flags().StringSliceVar(&q.Severities, "severity", nil, "Filter results by severity, one of high, medium or low")
+	cmd.Flags().StringSliceVar(&q.Categories, "category", nil, "Filter results by policy category")
+	cmd.Flags().StringSliceVarP(&q.Namespaces, "namespace", "n", nil, "Filter results by namespace")
+	cmd.Flags().StringSliceVar(&q.Kinds, "kind", nil, "Filter results by resource kind")
+	cmd.Flags().IntVar(&q.Limit, "limit", query.DefaultLimit, "Maximum number of results printed, 0 prints all the results")
+	cmd.Flags().IntVar(&q.Offset, "offset", 0, "Number of matching results skipped")
+	return cmd
+}
+
+func queryServer(server string, q query.Query) (query.Response, error) {
+	var resp query.Response
+	url := strings.TrimSuffix(server, "/") + query.ResultsPath + "?" + q.Values().Encode()
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	httpResp, err := httpClient.Get(url)
+	if err != nil {
+		return resp, sanitizederror.NewWithError("failed to query the report API", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(httpResp.Body, 1024))
+		return resp, sanitizederror.New(fmt.Sprintf("report API returned %s: %s", httpResp.Status, strings.TrimSpace(string(body))))
+	}
+
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return resp, sanitizederror.NewWithError("failed to decode the report API response", err)
+	}
+
+	return resp, nil
+}
+
+func queryCluster(configFlags *genericclioptions.ConfigFlags, q query.Query) (query.Response, error) {
+	var resp query.Response
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return resp, sanitizederror.NewWithError("failed to load the kubeconfig", err)
+	}
+
+	pclient, err := kyvernoclient.NewForConfig(restConfig)
+	if err != nil {
+		return resp, sanitizederror.NewWithError("failed to create the client", err)
+	}
+
+	polrs, cpolrs, err := listReports(context.TODO(), pclient, q.Namespaces)
+	if err != nil {
+		return resp, sanitizederror.NewWithError("failed to list policy reports", err)
+	}
+
+	return query.Run(q, polrs, cpolrs), nil
+}
+
+// listReports lists the reports that may hold matching results, cluster reports are skipped
+// when the results are filtered by namespace
+func listReports(ctx context.Context, pclient kyvernoclient.Interface, namespaces []string) ([]*policyreport.PolicyReport, []*policyreport.ClusterPolicyReport, error) {
+	var polrs []*policyreport.PolicyReport
+	var cpolrs []*policyreport.ClusterPolicyReport
+
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+		cpolrList, err := pclient.Wgpolicyk8sV1alpha1().ClusterPolicyReports().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i := range cpolrList.Items {
+			cpolrs = append(cpolrs, &cpolrList.Items[i])
+		}
+	}
+
+	for _, ns := range namespaces {
+		polrList, err := pclient.Wgpolicyk8sV1alpha1().PolicyReports(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i := range polrList.Items {
+			polrs = append(polrs, &polrList.Items[i])
+		}
+	}
+
+	return polrs, cpolrs, nil
+}
+
+func printJSON(w io.Writer, resp query.Response) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(resp)
+}
+
+func printTable(w io.Writer, resp query.Response) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tPOLICY\tRULE\tKIND\tNAME\tSTATUS\tSEVERITY\tCATEGORY\tMESSAGE")
+	for _, result := range resp.Results {
+		kind, name := resultResource(result)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			valueOrDash(result.Namespace), result.Policy, valueOrDash(result.Rule), valueOrDash(kind), valueOrDash(name),
+			result.Status, valueOrDash(string(result.Severity)), valueOrDash(result.Category), result.Message)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\nshowing %d of %d results (pass: %d, fail: %d, warn: %d, error: %d, skip: %d)\n",
+		len(resp.Results), resp.Total, resp.Summary.Pass, resp.Summary.Fail, resp.Summary.Warn, resp.Summary.Error, resp.Summary.Skip)
+	if resp.Next > 0 {
+		fmt.Fprintf(w, "use --offset %d to show the next results\n", resp.Next)
+	}
+}
+
+// resultResource returns the kind and name of the resources of the result
+func resultResource(result query.Result) (string, string) {
+	var kinds, names []string
+	for _, resource := range result.Resources {
+		if resource == nil {
+			continue
+		}
+		kinds = append(kinds, resource.Kind)
+		names = append(names, resource.Name)
+	}
+
+	return strings.Join(kinds, ","), strings.Join(names, ",")
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,42 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	policyreport "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	"github.com/kyverno/kyverno/pkg/policyreport/query"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_PrintTable(t *testing.T) {
+	resp := query.Response{
+		Results: []query.Result{
+			{
+				Report:    "polr-ns-default",
+				Namespace: "default",
+				PolicyReportResult: policyreport.PolicyReportResult{
+					Policy:    "require-labels",
+					Rule:      "check-team",
+					Status:    policyreport.StatusFail,
+					Message:   "label team is required",
+					Resources: []*corev1.ObjectReference{{Kind: "Pod", Namespace: "default", Name: "nginx"}},
+				},
+			},
+		},
+		Total:   3,
+		Next:    1,
+		Summary: policyreport.PolicyReportSummary{Fail: 2, Pass: 1},
+	}
+
+	var out bytes.Buffer
+	printTable(&out, resp)
+
+	lines := strings.Split(out.String(), "\n")
+	assert.Equal(t, strings.Join(strings.Fields(lines[0]), " "), "NAMESPACE POLICY RULE KIND NAME STATUS SEVERITY CATEGORY MESSAGE")
+	assert.Equal(t, strings.Join(strings.Fields(lines[1]), " "), "default require-labels check-team Pod nginx fail - - label team is required")
+	assert.Equal(t, lines[3], "showing 1 of 3 results (pass: 1, fail: 2, warn: 0, error: 0, skip: 0)")
+	assert.Equal(t, lines[4], "use --offset 1 to show the next results")
+}
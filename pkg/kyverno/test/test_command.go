@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/go-git/go-billy/v5"
@@ -18,6 +19,7 @@ import (
 	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
 	client "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/engine/jmespath"
 	"github.com/kyverno/kyverno/pkg/engine/response"
 	"github.com/kyverno/kyverno/pkg/engine/utils"
 	"github.com/kyverno/kyverno/pkg/kyverno/common"
@@ -68,6 +70,8 @@ type Test struct {
 	Resources []string      `json:"resources"`
 	Variables string        `json:"variables"`
 	Results   []TestResults `json:"results"`
+	// CurrentTime is an optional RFC3339 timestamp used as the current time by the JMESPath time functions
+	CurrentTime string `json:"currentTime"`
 }
 
 type SkippedPolicy struct {
@@ -304,6 +308,15 @@ func applyPoliciesFromPath(fs billy.Filesystem, policyBytes []byte, valuesFile s
 
 	fmt.Printf("\nExecuting %s...", values.Name)
 
+	if values.CurrentTime != "" {
+		currentTime, err := time.Parse(time.RFC3339, values.CurrentTime)
+		if err != nil {
+			return sanitizederror.NewWithError("failed to parse currentTime, expected RFC3339 format", err)
+		}
+		jmespath.SetClock(func() time.Time { return currentTime })
+		defer jmespath.ResetClock()
+	}
+
 	_, valuesMap, namespaceSelectorMap, err := common.GetVariable(variablesString, values.Variables, fs, isGit, policyResourcePath)
 	if err != nil {
 		if !sanitizederror.IsErrorSanitized(err) {
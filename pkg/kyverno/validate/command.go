@@ -8,11 +8,13 @@ import (
 	"os"
 
 	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine"
 	"github.com/kyverno/kyverno/pkg/kyverno/common"
 	"github.com/kyverno/kyverno/pkg/kyverno/crds"
 	sanitizederror "github.com/kyverno/kyverno/pkg/kyverno/sanitizedError"
 	"github.com/kyverno/kyverno/pkg/openapi"
 	policy2 "github.com/kyverno/kyverno/pkg/policy"
+	"github.com/kyverno/kyverno/pkg/policymutation"
 	"github.com/kyverno/kyverno/pkg/utils"
 	"github.com/spf13/cobra"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
@@ -185,6 +187,9 @@ func validatePolicies(policies []*v1.ClusterPolicy, v1crd apiextensions.CustomRe
 					}
 					return err
 				}
+				// the auto-generated rules are reported in the status, as done by the policy controller
+				rules, _ := policymutation.GenerateRules(*p, engine.PodControllers, logger)
+				p.Status.Autogen.Rules = rules
 				if outputType == "yaml" {
 					yamlPolicy, _ := yaml.Marshal(p)
 					fmt.Println(string(yamlPolicy))
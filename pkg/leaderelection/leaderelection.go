@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	prom "github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/leaderelection"
@@ -46,10 +47,13 @@ type Config struct {
 	leaderElectionCfg leaderelection.LeaderElectionConfig
 	leaderElector     *leaderelection.LeaderElector
 	isLeader          int64
+	status            prom.Gauge
 	log               logr.Logger
 }
 
-func New(name, namespace string, kubeClient kubernetes.Interface, startWork, stopWork func(), log logr.Logger) (Interface, error) {
+// New creates a leader election, status records whether this instance is the leader
+// in the leader election status metric and is nil when metrics are disabled
+func New(name, namespace string, kubeClient kubernetes.Interface, startWork, stopWork func(), status *prom.GaugeVec, log logr.Logger) (Interface, error) {
 	id, err := os.Hostname()
 	if err != nil {
 		return nil, errors.Wrapf(err, "error getting host name: %s/%s", namespace, name)
@@ -82,6 +86,11 @@ func New(name, namespace string, kubeClient kubernetes.Interface, startWork, sto
 		log:        log,
 	}
 
+	if status != nil {
+		e.status = status.With(prom.Labels{"name": name})
+		e.status.Set(0)
+	}
+
 	e.leaderElectionCfg = leaderelection.LeaderElectionConfig{
 		Lock:            e.lock,
 		ReleaseOnCancel: true,
@@ -91,6 +100,7 @@ func New(name, namespace string, kubeClient kubernetes.Interface, startWork, sto
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(ctx context.Context) {
 				atomic.StoreInt64(&e.isLeader, 1)
+				e.setStatus(1)
 				e.log.WithValues("id", e.lock.Identity()).Info("started leading")
 
 				if e.startWork != nil {
@@ -100,6 +110,7 @@ func New(name, namespace string, kubeClient kubernetes.Interface, startWork, sto
 
 			OnStoppedLeading: func() {
 				atomic.StoreInt64(&e.isLeader, 0)
+				e.setStatus(0)
 				e.log.WithValues("id", e.lock.Identity()).Info("leadership lost, stopped leading")
 				if e.stopWork != nil {
 					e.stopWork()
@@ -127,6 +138,12 @@ func New(name, namespace string, kubeClient kubernetes.Interface, startWork, sto
 	return e, nil
 }
 
+func (e *Config) setStatus(value float64) {
+	if e.status != nil {
+		e.status.Set(value)
+	}
+}
+
 func (e *Config) Name() string {
 	return e.name
 }
@@ -0,0 +1,25 @@
+package backgroundscan
+
+import (
+	"time"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/metrics"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// RecordScan records the duration of the background scan of a policy and the number of resources it scanned
+func (pm PromMetrics) RecordScan(policy kyverno.ClusterPolicy, resourceCount int, duration time.Duration) {
+	policyType, policyNamespace := metrics.Cluster, "-"
+	if policy.GetNamespace() != "" {
+		policyType, policyNamespace = metrics.Namespaced, policy.GetNamespace()
+	}
+
+	labels := prom.Labels{
+		"policy_type":      string(policyType),
+		"policy_namespace": policyNamespace,
+		"policy_name":      policy.GetName(),
+	}
+	pm.BackgroundScanDuration.With(labels).Observe(duration.Seconds())
+	pm.BackgroundScanResources.With(labels).Add(float64(resourceCount))
+}
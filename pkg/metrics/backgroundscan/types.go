@@ -0,0 +1,7 @@
+package backgroundscan
+
+import (
+	"github.com/kyverno/kyverno/pkg/metrics"
+)
+
+type PromMetrics metrics.PromMetrics
@@ -16,6 +16,21 @@ type PromMetrics struct {
 	PolicyExecutionDuration *prom.HistogramVec
 	AdmissionReviewDuration *prom.HistogramVec
 	AdmissionRequests       *prom.CounterVec
+	ImageVerifyCache        *prom.CounterVec
+	BackgroundScanDuration  *prom.HistogramVec
+	BackgroundScanResources *prom.CounterVec
+	WorkqueueDepth          *prom.GaugeVec
+	WorkqueueAdds           *prom.CounterVec
+	WorkqueueRetries        *prom.CounterVec
+	WorkqueueQueueDuration  *prom.HistogramVec
+	WorkqueueWorkDuration   *prom.HistogramVec
+	WorkqueueUnfinishedWork *prom.GaugeVec
+	WorkqueueLongestRunning *prom.GaugeVec
+	ContextLoadDuration     *prom.HistogramVec
+	ContextLoadErrors       *prom.CounterVec
+	PolicyCacheSize         *prom.GaugeVec
+	CertificateExpiry       *prom.GaugeVec
+	LeaderElectionStatus    *prom.GaugeVec
 }
 
 func NewPromConfig() *PromConfig {
@@ -93,6 +108,141 @@ func NewPromConfig() *PromConfig {
 		admissionRequestsLabels,
 	)
 
+	imageVerifyCacheLabels := []string{
+		"cache_result",
+	}
+	imageVerifyCacheMetric := prom.NewCounterVec(
+		prom.CounterOpts{
+			Name: "kyverno_image_verify_cache_requests_total",
+			Help: "can be used to track the hits and misses of the image verification cache, a miss results in the image signature being verified against the registry.",
+		},
+		imageVerifyCacheLabels,
+	)
+
+	backgroundScanLabels := []string{
+		"policy_type", "policy_namespace", "policy_name",
+	}
+	backgroundScanDurationMetric := prom.NewHistogramVec(
+		prom.HistogramOpts{
+			Name:    "kyverno_background_scan_duration_seconds",
+			Help:    "can be used to track the latencies (in seconds) associated with the background scan of the existing resources by a policy.",
+			Buckets: prom.ExponentialBuckets(0.1, 2, 12),
+		},
+		backgroundScanLabels,
+	)
+
+	backgroundScanResourcesMetric := prom.NewCounterVec(
+		prom.CounterOpts{
+			Name: "kyverno_background_scan_resources_total",
+			Help: "can be used to track the number of existing resources scanned by a policy during the background scans.",
+		},
+		backgroundScanLabels,
+	)
+
+	workqueueLabels := []string{
+		"name",
+	}
+	workqueueDepthMetric := prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Name: "kyverno_workqueue_depth",
+			Help: "can be used to track the number of items waiting in the workqueues of the Kyverno controllers.",
+		},
+		workqueueLabels,
+	)
+
+	workqueueAddsMetric := prom.NewCounterVec(
+		prom.CounterOpts{
+			Name: "kyverno_workqueue_adds_total",
+			Help: "can be used to track the number of items added to the workqueues of the Kyverno controllers.",
+		},
+		workqueueLabels,
+	)
+
+	workqueueRetriesMetric := prom.NewCounterVec(
+		prom.CounterOpts{
+			Name: "kyverno_workqueue_retries_total",
+			Help: "can be used to track the number of items re-queued after a failure by the Kyverno controllers.",
+		},
+		workqueueLabels,
+	)
+
+	workqueueQueueDurationMetric := prom.NewHistogramVec(
+		prom.HistogramOpts{
+			Name:    "kyverno_workqueue_queue_duration_seconds",
+			Help:    "can be used to track how long (in seconds) items stay in the workqueues of the Kyverno controllers before being processed.",
+			Buckets: prom.ExponentialBuckets(0.001, 4, 10),
+		},
+		workqueueLabels,
+	)
+
+	workqueueWorkDurationMetric := prom.NewHistogramVec(
+		prom.HistogramOpts{
+			Name:    "kyverno_workqueue_work_duration_seconds",
+			Help:    "can be used to track how long (in seconds) the Kyverno controllers take to process an item of their workqueues.",
+			Buckets: prom.ExponentialBuckets(0.001, 4, 10),
+		},
+		workqueueLabels,
+	)
+
+	workqueueUnfinishedWorkMetric := prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Name: "kyverno_workqueue_unfinished_work_seconds",
+			Help: "can be used to track the time (in seconds) spent on the items of the workqueues that are still being processed, a large value indicates stuck workers.",
+		},
+		workqueueLabels,
+	)
+
+	workqueueLongestRunningMetric := prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Name: "kyverno_workqueue_longest_running_processor_seconds",
+			Help: "can be used to track the time (in seconds) the longest running worker of each workqueue has been processing its item.",
+		},
+		workqueueLabels,
+	)
+
+	contextLoadLabels := []string{
+		"context_entry_type",
+	}
+	contextLoadDurationMetric := prom.NewHistogramVec(
+		prom.HistogramOpts{
+			Name: "kyverno_context_load_duration_seconds",
+			Help: "can be used to track the latencies (in seconds) associated with loading the ConfigMap and APICall context entries of the rules.",
+		},
+		contextLoadLabels,
+	)
+
+	contextLoadErrorsMetric := prom.NewCounterVec(
+		prom.CounterOpts{
+			Name: "kyverno_context_load_errors_total",
+			Help: "can be used to track the number of ConfigMap and APICall context entries that could not be loaded.",
+		},
+		contextLoadLabels,
+	)
+
+	policyCacheSizeMetric := prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Name: "kyverno_policy_cache_size",
+			Help: "can be used to track the number of policies held by the policy cache of the admission webhooks, per type of rule.",
+		},
+		[]string{"policy_cache_type"},
+	)
+
+	certificateExpiryMetric := prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Name: "kyverno_webhook_certificate_expiry_timestamp_seconds",
+			Help: "can be used to track the expiry time (as a Unix timestamp) of the CA and TLS certificates used by the admission webhooks.",
+		},
+		[]string{"certificate"},
+	)
+
+	leaderElectionStatusMetric := prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Name: "kyverno_leader_election_status",
+			Help: "can be used to track whether this Kyverno instance is the leader of a leader election, 1 means the instance is the leader.",
+		},
+		[]string{"name"},
+	)
+
 	pc.Metrics = &PromMetrics{
 		PolicyResults:           policyResultsMetric,
 		PolicyRuleInfo:          policyRuleInfoMetric,
@@ -100,6 +250,21 @@ func NewPromConfig() *PromConfig {
 		PolicyExecutionDuration: policyExecutionDurationMetric,
 		AdmissionReviewDuration: admissionReviewDurationMetric,
 		AdmissionRequests:       admissionRequestsMetric,
+		ImageVerifyCache:        imageVerifyCacheMetric,
+		BackgroundScanDuration:  backgroundScanDurationMetric,
+		BackgroundScanResources: backgroundScanResourcesMetric,
+		WorkqueueDepth:          workqueueDepthMetric,
+		WorkqueueAdds:           workqueueAddsMetric,
+		WorkqueueRetries:        workqueueRetriesMetric,
+		WorkqueueQueueDuration:  workqueueQueueDurationMetric,
+		WorkqueueWorkDuration:   workqueueWorkDurationMetric,
+		WorkqueueUnfinishedWork: workqueueUnfinishedWorkMetric,
+		WorkqueueLongestRunning: workqueueLongestRunningMetric,
+		ContextLoadDuration:     contextLoadDurationMetric,
+		ContextLoadErrors:       contextLoadErrorsMetric,
+		PolicyCacheSize:         policyCacheSizeMetric,
+		CertificateExpiry:       certificateExpiryMetric,
+		LeaderElectionStatus:    leaderElectionStatusMetric,
 	}
 
 	pc.MetricsRegistry.MustRegister(pc.Metrics.PolicyResults)
@@ -108,6 +273,21 @@ func NewPromConfig() *PromConfig {
 	pc.MetricsRegistry.MustRegister(pc.Metrics.PolicyExecutionDuration)
 	pc.MetricsRegistry.MustRegister(pc.Metrics.AdmissionReviewDuration)
 	pc.MetricsRegistry.MustRegister(pc.Metrics.AdmissionRequests)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.ImageVerifyCache)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.BackgroundScanDuration)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.BackgroundScanResources)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.WorkqueueDepth)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.WorkqueueAdds)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.WorkqueueRetries)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.WorkqueueQueueDuration)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.WorkqueueWorkDuration)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.WorkqueueUnfinishedWork)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.WorkqueueLongestRunning)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.ContextLoadDuration)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.ContextLoadErrors)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.PolicyCacheSize)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.CertificateExpiry)
+	pc.MetricsRegistry.MustRegister(pc.Metrics.LeaderElectionStatus)
 
 	return pc
 }
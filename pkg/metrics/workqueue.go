@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	prom "github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// workqueueMetricsProvider exposes the metrics of the named workqueues of the Kyverno controllers
+type workqueueMetricsProvider struct {
+	metrics *PromMetrics
+}
+
+// NewWorkqueueMetricsProvider returns a workqueue.MetricsProvider backed by the workqueue metrics of pc,
+// it must be set with workqueue.SetProvider before the workqueues are created
+func NewWorkqueueMetricsProvider(pc *PromConfig) workqueue.MetricsProvider {
+	return workqueueMetricsProvider{metrics: pc.Metrics}
+}
+
+func (p workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return p.metrics.WorkqueueDepth.With(prom.Labels{"name": name})
+}
+
+func (p workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return p.metrics.WorkqueueAdds.With(prom.Labels{"name": name})
+}
+
+func (p workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return p.metrics.WorkqueueQueueDuration.With(prom.Labels{"name": name})
+}
+
+func (p workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return p.metrics.WorkqueueWorkDuration.With(prom.Labels{"name": name})
+}
+
+func (p workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.metrics.WorkqueueUnfinishedWork.With(prom.Labels{"name": name})
+}
+
+func (p workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.metrics.WorkqueueLongestRunning.With(prom.Labels{"name": name})
+}
+
+func (p workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return p.metrics.WorkqueueRetries.With(prom.Labels{"name": name})
+}
@@ -12,8 +12,10 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine"
 	"github.com/kyverno/kyverno/pkg/engine/response"
 	"github.com/kyverno/kyverno/pkg/metrics"
+	"github.com/kyverno/kyverno/pkg/metrics/backgroundscan"
 	policyExecutionDuration "github.com/kyverno/kyverno/pkg/metrics/policyexecutionduration"
 	policyResults "github.com/kyverno/kyverno/pkg/metrics/policyresults"
+	"github.com/kyverno/kyverno/pkg/utils"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -21,6 +23,12 @@ func (pc *PolicyController) processExistingResources(policy *kyverno.ClusterPoli
 	logger := pc.log.WithValues("policy", policy.Name)
 	logger.V(4).Info("applying policy to existing resources")
 
+	startTime := time.Now()
+	scanned := 0
+	defer func() {
+		pc.registerBackgroundScanMetric(*policy, scanned, time.Since(startTime))
+	}()
+
 	// Parse through all the resources drops the cache after configured rebuild time
 	pc.rm.Drop()
 
@@ -31,6 +39,12 @@ func (pc *PolicyController) processExistingResources(policy *kyverno.ClusterPoli
 
 		for _, k := range rule.MatchResources.Kinds {
 			logger = logger.WithValues("rule", rule.Name, "kind", k)
+			// subresources are only matched in admission requests
+			if _, _, _, subresource := utils.ParseKindSelector(k); subresource != "" {
+				logger.V(4).Info("skipping subresource in background scan")
+				continue
+			}
+
 			namespaced, err := pc.rm.GetScope(k)
 			if err != nil {
 				if err := pc.registerResource(k); err != nil {
@@ -45,7 +59,7 @@ func (pc *PolicyController) processExistingResources(policy *kyverno.ClusterPoli
 			metricRegisteredTracker := false
 
 			if !namespaced {
-				pc.applyAndReportPerNamespace(policy, k, "", rule, logger.WithValues("kind", k), &metricRegisteredTracker)
+				scanned += pc.applyAndReportPerNamespace(policy, k, "", rule, logger.WithValues("kind", k), &metricRegisteredTracker)
 				continue
 			}
 
@@ -54,7 +68,7 @@ func (pc *PolicyController) processExistingResources(policy *kyverno.ClusterPoli
 				// for kind: Policy, consider only the namespace which the policy belongs to.
 				// for kind: ClusterPolicy, consider all the namespaces.
 				if policy.Namespace == ns || policy.Namespace == "" {
-					pc.applyAndReportPerNamespace(policy, k, ns, rule, logger.WithValues("kind", k).WithValues("ns", ns), &metricRegisteredTracker)
+					scanned += pc.applyAndReportPerNamespace(policy, k, ns, rule, logger.WithValues("kind", k).WithValues("ns", ns), &metricRegisteredTracker)
 				}
 			}
 		}
@@ -72,11 +86,12 @@ func (pc *PolicyController) registerResource(gvk string) (err error) {
 	return nil
 }
 
-func (pc *PolicyController) applyAndReportPerNamespace(policy *kyverno.ClusterPolicy, kind string, ns string, rule kyverno.Rule, logger logr.Logger, metricAlreadyRegistered *bool) {
+// applyAndReportPerNamespace applies the rule to the resources of the kind in the namespace and returns the number of scanned resources
+func (pc *PolicyController) applyAndReportPerNamespace(policy *kyverno.ClusterPolicy, kind string, ns string, rule kyverno.Rule, logger logr.Logger, metricAlreadyRegistered *bool) int {
 	rMap := pc.getResourcesPerNamespace(kind, ns, rule, logger)
 	excludeAutoGenResources(*policy, rMap, logger)
 	if len(rMap) == 0 {
-		return
+		return 0
 	}
 
 	var engineResponses []*response.EngineResponse
@@ -96,6 +111,15 @@ func (pc *PolicyController) applyAndReportPerNamespace(policy *kyverno.ClusterPo
 	}
 
 	pc.report(engineResponses, logger)
+	return len(rMap)
+}
+
+func (pc *PolicyController) registerBackgroundScanMetric(policy kyverno.ClusterPolicy, resourceCount int, duration time.Duration) {
+	if pc.promConfig == nil {
+		return
+	}
+
+	backgroundscan.ParsePromMetrics(*pc.promConfig.Metrics).RecordScan(policy, resourceCount, duration)
 }
 
 func (pc *PolicyController) registerPolicyResultsMetricValidation(logger logr.Logger, policy kyverno.ClusterPolicy, engineResponse response.EngineResponse) {
@@ -40,12 +40,16 @@ func (pc *PolicyController) report(engineResponses []*response.EngineResponse, l
 // forceReconciliation forces a background scan by adding all policies to the workqueue
 func (pc *PolicyController) forceReconciliation(reconcileCh <-chan bool, stopCh <-chan struct{}) {
 	logger := pc.log.WithName("forceReconciliation")
-	ticker := time.NewTicker(pc.reconcilePeriod)
+	interval := pc.backgroundScanInterval()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	autogenControllers := pc.configHandler.GetAutogenControllers()
 
 	for {
 		select {
 		case <-ticker.C:
-			logger.Info("performing the background scan", "scan interval", pc.reconcilePeriod.String())
+			logger.Info("performing the background scan", "scan interval", interval.String())
 			if err := pc.policyReportEraser.CleanupReportChangeRequests(cleanupReportChangeRequests); err != nil {
 				logger.Error(err, "failed to cleanup report change requests")
 			}
@@ -56,6 +60,13 @@ func (pc *PolicyController) forceReconciliation(reconcileCh <-chan bool, stopCh
 
 			pc.requeuePolicies()
 
+			// a new interval configured in the KyvernoConfig applies from the next scan
+			if current := pc.backgroundScanInterval(); current != interval {
+				logger.Info("background scan interval changed", "old", interval.String(), "new", current.String())
+				interval = current
+				ticker.Reset(interval)
+			}
+
 		case erase := <-reconcileCh:
 			logger.Info("received the reconcile signal, reconciling policy report")
 			if err := pc.policyReportEraser.CleanupReportChangeRequests(cleanupReportChangeRequests); err != nil {
@@ -70,12 +81,27 @@ func (pc *PolicyController) forceReconciliation(reconcileCh <-chan bool, stopCh
 
 			pc.requeuePolicies()
 
+			// the rules auto-generated for the configured pod controllers are recorded in the status of all the policies
+			if current := pc.configHandler.GetAutogenControllers(); current != autogenControllers {
+				logger.Info("autogen controllers changed, updating the status of the policies", "old", autogenControllers, "new", current)
+				autogenControllers = current
+				pc.updateAutogenStatuses(logger)
+			}
+
 		case <-stopCh:
 			return
 		}
 	}
 }
 
+// backgroundScanInterval returns the interval configured in the KyvernoConfig, or the --background-scan flag
+func (pc *PolicyController) backgroundScanInterval() time.Duration {
+	if interval := pc.configHandler.GetBackgroundScanInterval(); interval > 0 {
+		return interval
+	}
+	return pc.reconcilePeriod
+}
+
 func cleanupReportChangeRequests(pclient *kyvernoclient.Clientset, rcrLister changerequestlister.ReportChangeRequestLister, crcrLister changerequestlister.ClusterReportChangeRequestLister) error {
 	var errors []string
 
@@ -220,6 +246,8 @@ func generateFailEventsPerEr(log logr.Logger, er *response.EngineResponse) []eve
 		e.Reason = event.PolicyViolation.String()
 		e.Source = event.PolicyController
 		e.Message = fmt.Sprintf("policy '%s' (%s) rule '%s' failed. %v", er.PolicyResponse.Policy.Name, rule.Type, rule.Name, rule.Message)
+		e.PolicyName = er.PolicyResponse.Policy.Name
+		e.PolicyNamespace = er.PolicyResponse.Policy.Namespace
 		eventInfos = append(eventInfos, e)
 	}
 
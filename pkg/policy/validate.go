@@ -7,14 +7,18 @@ import (
 	"reflect"
 	"strings"
 
-	"github.com/jmespath/go-jmespath"
 	"github.com/kyverno/kyverno/pkg/engine"
+	"github.com/kyverno/kyverno/pkg/engine/jmespath"
 	"github.com/kyverno/kyverno/pkg/engine/variables"
 	"github.com/kyverno/kyverno/pkg/kyverno/common"
 
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	pkgCommon "github.com/kyverno/kyverno/pkg/common"
+	"github.com/kyverno/kyverno/pkg/config"
 	dclient "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/event"
 	"github.com/kyverno/kyverno/pkg/openapi"
+	pm "github.com/kyverno/kyverno/pkg/policymutation"
 	"github.com/kyverno/kyverno/pkg/utils"
 	"github.com/minio/pkg/wildcard"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -27,6 +31,12 @@ import (
 // - One operation per rule
 // - ResourceDescription mandatory checks
 func Validate(policy *kyverno.ClusterPolicy, client *dclient.Client, mock bool, openAPIController *openapi.Controller) error {
+	return validatePolicy(policy, client, mock, openAPIController, false)
+}
+
+// validatePolicy validates the policy, autogen is set when the rules of the policy are the rules auto-generated
+// for the pod controllers, these are not generated again nor validated with the OpenAPI schemas
+func validatePolicy(policy *kyverno.ClusterPolicy, client *dclient.Client, mock bool, openAPIController *openapi.Controller, autogen bool) error {
 	p := *policy
 	if len(common.PolicyHasVariables(p)) > 0 {
 		err := common.PolicyHasNonAllowedVariables(p)
@@ -43,6 +53,32 @@ func Validate(policy *kyverno.ClusterPolicy, client *dclient.Client, mock bool,
 	if path, err := validateUniqueRuleName(p); err != nil {
 		return fmt.Errorf("path: spec.%s: %v", path, err)
 	}
+
+	if path, err := validateValidationFailureActionOverrides(p.Spec.ValidationFailureActionOverrides); err != nil {
+		return fmt.Errorf("path: spec.%s: %v", path, err)
+	}
+
+	if path, err := validateWebhookSettings(p.Spec); err != nil {
+		return fmt.Errorf("path: spec.%s: %v", path, err)
+	}
+
+	if err := event.ValidateAnnotations(p.GetAnnotations()); err != nil {
+		return fmt.Errorf("path: metadata.annotations: %v", err)
+	}
+
+	if controllers, ok := p.GetAnnotations()[engine.PodControllersAnnotation]; ok {
+		if _, err := config.ParsePodControllers(controllers); err != nil {
+			return fmt.Errorf("path: metadata.annotations.%s: %v", engine.PodControllersAnnotation, err)
+		}
+	}
+
+	// rules for pod controllers are generated at runtime and must be valid as well
+	if !autogen {
+		if err := validateAutogenRules(p, client, mock, openAPIController); err != nil {
+			return err
+		}
+	}
+
 	if p.Spec.Background == nil || *p.Spec.Background == true {
 		if err := ContainsVariablesOtherThanObject(p); err != nil {
 			return fmt.Errorf("only select variables are allowed in background mode. Set spec.background=false to disable background mode for this policy rule: %s ", err)
@@ -69,6 +105,10 @@ func Validate(policy *kyverno.ClusterPolicy, client *dclient.Client, mock bool,
 			return fmt.Errorf("path: spec.rules[%d]: %v", i, err)
 		}
 
+		if err := validateVariableExpressions(rule); err != nil {
+			return fmt.Errorf("path: spec.rules[%d]: %v", i, err)
+		}
+
 		// validate Cluster Resources in namespaced policy
 		// For namespaced policy, ClusterResource type field and values are not allowed in match and exclude
 		if !mock && p.ObjectMeta.Namespace != "" {
@@ -179,6 +219,10 @@ func Validate(policy *kyverno.ClusterPolicy, client *dclient.Client, mock bool,
 		}
 	}
 
+	if autogen {
+		return nil
+	}
+
 	if !mock {
 		if err := openAPIController.ValidatePolicyFields(p); err != nil {
 			return err
@@ -192,6 +236,27 @@ func Validate(policy *kyverno.ClusterPolicy, client *dclient.Client, mock bool,
 	return nil
 }
 
+// validateAutogenRules validates the rules auto-generated for the pod controllers as the rules of the policy,
+// so that a policy is rejected at admission instead of failing at runtime
+func validateAutogenRules(p kyverno.ClusterPolicy, client *dclient.Client, mock bool, openAPIController *openapi.Controller) error {
+	rules, err := pm.GenerateRules(p, "", log.Log)
+	if err != nil {
+		return err
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	autogen := p.DeepCopy()
+	autogen.Spec.Rules = rules
+	if err := validatePolicy(autogen, client, mock, openAPIController, true); err != nil {
+		return fmt.Errorf("invalid rules auto-generated for pod controllers: %v", err)
+	}
+
+	return nil
+}
+
 func validateMatchKindHelper(rule kyverno.Rule) error {
 	if !ruleOnlyDealsWithResourceMetaData(rule) {
 		return fmt.Errorf("policy can only deal with the metadata field of the resource if" +
@@ -663,7 +728,12 @@ func validateConditions(conditions apiextensions.JSON, schemaKey string) (string
 // validateConditionValues validates whether all the values under the 'value' field of a 'conditions' field
 // are apt with respect to the provided 'condition.key'
 func validateConditionValues(c kyverno.Condition) (string, error) {
-	switch strings.ReplaceAll(c.Key.(string), " ", "") {
+	if path, err := validateConditionOperator(c); err != nil {
+		return path, err
+	}
+
+	key, _ := c.Key.(string)
+	switch strings.ReplaceAll(key, " ", "") {
 	case "{{request.operation}}":
 		return validateConditionValuesKeyRequestOperation(c)
 	default:
@@ -671,6 +741,34 @@ func validateConditionValues(c kyverno.Condition) (string, error) {
 	}
 }
 
+// validateConditionOperator validates whether the 'operator' field of a 'conditions' field is a supported operator,
+// and that set operators (AnyIn, AllIn, AnyNotIn, AllNotIn) are provided a string or a list under the 'value' field
+func validateConditionOperator(c kyverno.Condition) (string, error) {
+	var operator kyverno.ConditionOperator
+	for name, op := range kyverno.ConditionOperators {
+		if strings.EqualFold(name, string(c.Operator)) {
+			operator = op
+			break
+		}
+	}
+
+	if operator == "" {
+		return "operator", fmt.Errorf("unknown operator '%s' found under the 'operator' field", c.Operator)
+	}
+
+	switch operator {
+	case kyverno.AnyIn, kyverno.AllIn, kyverno.AnyNotIn, kyverno.AllNotIn:
+		switch c.Value.(type) {
+		case string, []interface{}:
+			return "", nil
+		default:
+			return "value", fmt.Errorf("'value' field found to be of the type %T. The provided value/values are expected to be either in the form of a string or list when using the %s operator", c.Value, operator)
+		}
+	}
+
+	return "", nil
+}
+
 // validateConditionValuesKeyRequestOperation validates whether all the values under the 'value' field of a 'conditions' field
 // are one of ["CREATE", "UPDATE", "DELETE", "CONNECT"] when 'condition.key' is {{request.operation}}
 func validateConditionValuesKeyRequestOperation(c kyverno.Condition) (string, error) {
@@ -705,6 +803,41 @@ func validateConditionValuesKeyRequestOperation(c kyverno.Condition) (string, er
 	return "", nil
 }
 
+// validateValidationFailureActionOverrides checks that each override has a valid action
+// and selects namespaces by names and/or a valid label selector
+func validateValidationFailureActionOverrides(overrides []kyverno.ValidationFailureActionOverride) (string, error) {
+	for i, override := range overrides {
+		if override.Action != pkgCommon.Enforce && override.Action != pkgCommon.Audit {
+			return fmt.Sprintf("validationFailureActionOverrides[%d].action", i), fmt.Errorf("invalid action %s, must be %s or %s", override.Action, pkgCommon.Audit, pkgCommon.Enforce)
+		}
+
+		if len(override.Namespaces) == 0 && override.NamespaceSelector == nil {
+			return fmt.Sprintf("validationFailureActionOverrides[%d]", i), fmt.Errorf("namespaces or namespaceSelector is required")
+		}
+
+		if override.NamespaceSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(override.NamespaceSelector); err != nil {
+				return fmt.Sprintf("validationFailureActionOverrides[%d].namespaceSelector", i), err
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// validateWebhookSettings checks the failure policy and the timeout of the policy webhook
+func validateWebhookSettings(spec kyverno.Spec) (string, error) {
+	if spec.FailurePolicy != nil && *spec.FailurePolicy != "Ignore" && *spec.FailurePolicy != "Fail" {
+		return "failurePolicy", fmt.Errorf("invalid failure policy %s, must be Ignore or Fail", *spec.FailurePolicy)
+	}
+
+	if spec.WebhookTimeoutSeconds != nil && (*spec.WebhookTimeoutSeconds < 1 || *spec.WebhookTimeoutSeconds > 30) {
+		return "webhookTimeoutSeconds", fmt.Errorf("invalid timeout %d, must be between 1 and 30 seconds", *spec.WebhookTimeoutSeconds)
+	}
+
+	return "", nil
+}
+
 // validateUniqueRuleName checks if the rule names are unique across a policy
 func validateUniqueRuleName(p kyverno.ClusterPolicy) (string, error) {
 	var ruleNames []string
@@ -767,6 +900,48 @@ func validateRuleContext(rule kyverno.Rule) error {
 	return nil
 }
 
+// validateVariableExpressions checks the JMESPath expressions of the variables used in a rule,
+// calls to unknown functions or with invalid arguments are rejected instead of failing at runtime
+func validateVariableExpressions(rule kyverno.Rule) error {
+	ruleJSON, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+
+	var ruleMap interface{}
+	if err := json.Unmarshal(ruleJSON, &ruleMap); err != nil {
+		return err
+	}
+
+	return validateVariableExpressionsInElement(ruleMap)
+}
+
+func validateVariableExpressionsInElement(element interface{}) error {
+	switch typed := element.(type) {
+	case map[string]interface{}:
+		for _, value := range typed {
+			if err := validateVariableExpressionsInElement(value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, value := range typed {
+			if err := validateVariableExpressionsInElement(value); err != nil {
+				return err
+			}
+		}
+	case string:
+		for _, v := range variables.RegexVariables.FindAllString(typed, -1) {
+			expression := strings.TrimSpace(v[2 : len(v)-2])
+			if err := jmespath.Validate(expression); err != nil {
+				return fmt.Errorf("invalid variable %s: %v", v, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 func validateConfigMap(entry kyverno.ContextEntry) error {
 	if entry.ConfigMap == nil {
 		return fmt.Errorf("configMap is empty")
@@ -810,7 +985,7 @@ func validateAPICall(entry kyverno.ContextEntry) error {
 	jmesPath := variables.ReplaceAllVars(entry.APICall.JMESPath, func(s string) string { return "kyvernojmespathvariable" })
 
 	if !strings.Contains(jmesPath, "kyvernojmespathvariable") && entry.APICall.JMESPath != "" {
-		if _, err := jmespath.NewParser().Parse(entry.APICall.JMESPath); err != nil {
+		if err := jmespath.Validate(entry.APICall.JMESPath); err != nil {
 			return fmt.Errorf("failed to parse JMESPath %s: %v", entry.APICall.JMESPath, err)
 		}
 	}
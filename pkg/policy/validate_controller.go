@@ -1,12 +1,13 @@
 package policy
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"reflect"
-	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -18,6 +19,7 @@ import (
 	pkgCommon "github.com/kyverno/kyverno/pkg/common"
 	"github.com/kyverno/kyverno/pkg/config"
 	client "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/engine"
 	"github.com/kyverno/kyverno/pkg/event"
 	"github.com/kyverno/kyverno/pkg/kyverno/common"
 	"github.com/kyverno/kyverno/pkg/metrics"
@@ -25,7 +27,6 @@ import (
 	pm "github.com/kyverno/kyverno/pkg/policymutation"
 	"github.com/kyverno/kyverno/pkg/policyreport"
 	"github.com/kyverno/kyverno/pkg/resourcecache"
-	utils "github.com/kyverno/kyverno/pkg/utils"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -266,7 +267,7 @@ func (pc *PolicyController) addPolicy(obj interface{}) {
 	// register kyverno_policy_changes_total metric concurrently
 	go pc.registerPolicyChangesMetricAddPolicy(logger, p)
 
-	if p.Spec.Background == nil || p.Spec.ValidationFailureAction == "" || missingAutoGenRules(p, logger) {
+	if p.Spec.Background == nil || p.Spec.ValidationFailureAction == "" {
 		pol, _ := common.MutatePolicy(p, logger)
 		pol.SetGroupVersionKind(schema.GroupVersionKind{Group: "kyverno.io", Version: "v1", Kind: "ClusterPolicy"})
 		_, err := pc.client.UpdateResource("kyverno.io/v1", "ClusterPolicy", "", pol, false)
@@ -275,6 +276,8 @@ func (pc *PolicyController) addPolicy(obj interface{}) {
 		}
 	}
 
+	pc.updateAutogenStatus(p, logger)
+
 	if !pc.canBackgroundProcess(p) {
 		return
 	}
@@ -293,7 +296,7 @@ func (pc *PolicyController) updatePolicy(old, cur interface{}) {
 	// register kyverno_policy_changes_total metric concurrently
 	go pc.registerPolicyChangesMetricUpdatePolicy(logger, oldP, curP)
 
-	if curP.Spec.Background == nil || curP.Spec.ValidationFailureAction == "" || missingAutoGenRules(curP, logger) {
+	if curP.Spec.Background == nil || curP.Spec.ValidationFailureAction == "" {
 		pol, _ := common.MutatePolicy(curP, logger)
 		pol.SetGroupVersionKind(schema.GroupVersionKind{Group: "kyverno.io", Version: "v1", Kind: "ClusterPolicy"})
 		_, err := pc.client.UpdateResource("kyverno.io/v1", "ClusterPolicy", "", pol, false)
@@ -302,11 +305,13 @@ func (pc *PolicyController) updatePolicy(old, cur interface{}) {
 		}
 	}
 
+	pc.updateAutogenStatus(curP, logger)
+
 	if !pc.canBackgroundProcess(curP) {
 		return
 	}
 
-	if reflect.DeepEqual(oldP.Spec, curP.Spec) {
+	if reflect.DeepEqual(oldP.Spec, curP.Spec) && !autogenAnnotationChanged(oldP, curP) {
 		return
 	}
 
@@ -423,7 +428,7 @@ func (pc *PolicyController) addNsPolicy(obj interface{}) {
 	logger.Info("policy created", "uid", p.UID, "kind", "Policy", "name", p.Name, "namespaces", p.Namespace)
 
 	pol := ConvertPolicyToClusterPolicy(p)
-	if pol.Spec.Background == nil || pol.Spec.ValidationFailureAction == "" || missingAutoGenRules(pol, logger) {
+	if pol.Spec.Background == nil || pol.Spec.ValidationFailureAction == "" {
 		nsPol, _ := common.MutatePolicy(pol, logger)
 		nsPol.SetGroupVersionKind(schema.GroupVersionKind{Group: "kyverno.io", Version: "v1", Kind: "Policy"})
 		_, err := pc.client.UpdateResource("kyverno.io/v1", "Policy", p.Namespace, nsPol, false)
@@ -431,6 +436,9 @@ func (pc *PolicyController) addNsPolicy(obj interface{}) {
 			logger.Error(err, "failed to add namespace policy")
 		}
 	}
+
+	pc.updateAutogenStatus(pol, logger)
+
 	if !pc.canBackgroundProcess(pol) {
 		return
 	}
@@ -450,7 +458,7 @@ func (pc *PolicyController) updateNsPolicy(old, cur interface{}) {
 
 	ncurP := ConvertPolicyToClusterPolicy(curP)
 
-	if ncurP.Spec.Background == nil || ncurP.Spec.ValidationFailureAction == "" || missingAutoGenRules(ncurP, logger) {
+	if ncurP.Spec.Background == nil || ncurP.Spec.ValidationFailureAction == "" {
 		nsPol, _ := common.MutatePolicy(ncurP, logger)
 		nsPol.SetGroupVersionKind(schema.GroupVersionKind{Group: "kyverno.io", Version: "v1", Kind: "Policy"})
 		_, err := pc.client.UpdateResource("kyverno.io/v1", "Policy", ncurP.GetNamespace(), nsPol, false)
@@ -459,11 +467,13 @@ func (pc *PolicyController) updateNsPolicy(old, cur interface{}) {
 		}
 	}
 
+	pc.updateAutogenStatus(ncurP, logger)
+
 	if !pc.canBackgroundProcess(ncurP) {
 		return
 	}
 
-	if reflect.DeepEqual(oldP.Spec, curP.Spec) {
+	if reflect.DeepEqual(oldP.Spec, curP.Spec) && !autogenAnnotationChanged(ConvertPolicyToClusterPolicy(oldP), ncurP) {
 		return
 	}
 
@@ -506,6 +516,10 @@ func (pc *PolicyController) deleteNsPolicy(obj interface{}) {
 }
 
 func (pc *PolicyController) enqueueRCRDeletedRule(old, cur *kyverno.ClusterPolicy) {
+	// the results of the auto-generated rules are removed with the rules they are generated from
+	old = pm.ExpandPolicy(old, pc.configHandler.GetAutogenControllers(), pc.log)
+	cur = pm.ExpandPolicy(cur, pc.configHandler.GetAutogenControllers(), pc.log)
+
 	curRule := make(map[string]bool)
 	for _, rule := range cur.Spec.Rules {
 		curRule[rule.Name] = true
@@ -640,7 +654,7 @@ func (pc *PolicyController) syncPolicy(key string) error {
 	}
 
 	updateGR(pc.kyvernoClient, policy.Name, grList, logger)
-	pc.processExistingResources(policy)
+	pc.processExistingResources(pm.ExpandPolicy(policy, pc.configHandler.GetAutogenControllers(), logger))
 	return nil
 }
 
@@ -692,40 +706,62 @@ func updateGR(kyvernoClient *kyvernoclient.Clientset, policyKey string, grList [
 	}
 }
 
-func missingAutoGenRules(policy *kyverno.ClusterPolicy, log logr.Logger) bool {
-	var podRuleName []string
-	ruleCount := 1
-	if canApplyAutoGen, _ := pm.CanAutoGen(policy, log); canApplyAutoGen {
-		for _, rule := range policy.Spec.Rules {
-			podRuleName = append(podRuleName, rule.Name)
-		}
+// autogenAnnotationChanged checks if the pod controllers the rules are generated for have changed
+func autogenAnnotationChanged(old, cur *kyverno.ClusterPolicy) bool {
+	return old.GetAnnotations()[engine.PodControllersAnnotation] != cur.GetAnnotations()[engine.PodControllersAnnotation]
+}
+
+// updateAutogenStatus records the rules auto-generated for the pod controllers in the status of the policy,
+// the spec of the policy is left as written
+func (pc *PolicyController) updateAutogenStatus(policy *kyverno.ClusterPolicy, logger logr.Logger) {
+	rules, err := pm.GenerateRules(*policy, pc.configHandler.GetAutogenControllers(), logger)
+	if err != nil {
+		logger.Error(err, "failed to auto generate rules", "name", policy.Name)
 	}
 
-	if len(podRuleName) > 0 {
-		annotations := policy.GetAnnotations()
-		val, ok := annotations["pod-policies.kyverno.io/autogen-controllers"]
-		if !ok {
-			return true
-		}
-		if val == "none" {
-			return false
-		}
-		res := strings.Split(val, ",")
+	// the rules are compared encoded as the status is decoded from JSON
+	current, _ := json.Marshal(policy.Status.Autogen.Rules)
+	desired, _ := json.Marshal(rules)
+	if bytes.Equal(current, desired) {
+		return
+	}
 
-		if len(res) == 1 {
-			ruleCount = 2
-		}
-		if len(res) > 1 {
-			if utils.ContainsString(res, "CronJob") {
-				ruleCount = 3
-			} else {
-				ruleCount = 2
-			}
-		}
+	if policy.GetNamespace() == "" {
+		cpol := policy.DeepCopy()
+		cpol.Status.Autogen.Rules = rules
+		_, err = pc.kyvernoClient.KyvernoV1().ClusterPolicies().UpdateStatus(context.TODO(), cpol, metav1.UpdateOptions{})
+	} else {
+		pol := kyverno.Policy(*policy.DeepCopy())
+		pol.Status.Autogen.Rules = rules
+		_, err = pc.kyvernoClient.KyvernoV1().Policies(pol.GetNamespace()).UpdateStatus(context.TODO(), &pol, metav1.UpdateOptions{})
+	}
 
-		if len(policy.Spec.Rules) != (ruleCount * len(podRuleName)) {
-			return true
-		}
+	if err != nil {
+		logger.Error(err, "failed to update the auto-generated rules in the policy status", "namespace", policy.GetNamespace(), "name", policy.GetName())
+		return
+	}
+
+	logger.V(3).Info("updated the auto-generated rules in the policy status", "namespace", policy.GetNamespace(), "name", policy.GetName(), "rules", len(rules))
+}
+
+// updateAutogenStatuses records the auto-generated rules in the status of all the policies,
+// it is called when the configured pod controllers change
+func (pc *PolicyController) updateAutogenStatuses(logger logr.Logger) {
+	cpols, err := pc.pLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "unable to list ClusterPolicies")
+	}
+
+	for _, cpol := range cpols {
+		pc.updateAutogenStatus(cpol, logger)
+	}
+
+	pols, err := pc.npLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "unable to list Policies")
+	}
+
+	for _, pol := range pols {
+		pc.updateAutogenStatus(ConvertPolicyToClusterPolicy(pol), logger)
 	}
-	return false
 }
@@ -2,12 +2,10 @@ package policy
 
 import (
 	"encoding/json"
-	"fmt"
 	"testing"
 
 	"github.com/kyverno/kyverno/pkg/openapi"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
-	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"gotest.tools/assert"
@@ -469,6 +467,81 @@ func Test_Validate_PreconditionsValuesList_KeyRequestOperation_UnknownItem(t *te
 	assert.Assert(t, err != nil)
 }
 
+func Test_Validate_Conditions_SetOperators(t *testing.T) {
+	denyConditions := []byte(`
+	{
+		"any": [
+			{
+				"key":"{{request.object.spec.containers[].securityContext.capabilities.add[]}}",
+				"operator":"AnyIn",
+				"value": ["NET_ADMIN", "SYS_*"]
+			},
+			{
+				"key":"{{request.object.spec.containers[].image}}",
+				"operator":"AllNotIn",
+				"value": "registry.corp.com/*"
+			}
+		],
+		"all": [
+			{
+				"key":"{{request.object.metadata.labels.app}}",
+				"operator":"AnyNotIn",
+				"value": ["foo", "bar"]
+			},
+			{
+				"key":["a", "b"],
+				"operator":"allin",
+				"value": ["a", "b", "c"]
+			}
+		]
+	}
+	`)
+
+	var dcs apiextensions.JSON
+	err := json.Unmarshal(denyConditions, &dcs)
+	assert.NilError(t, err)
+
+	_, err = validateConditions(dcs, "conditions")
+	assert.NilError(t, err)
+}
+
+func Test_Validate_Conditions_InvalidOperator(t *testing.T) {
+	conditions := []byte(`
+	[
+		{
+			"key":"{{request.object.metadata.name}}",
+			"operator":"AnyOf",
+			"value": ["foo"]
+		}
+	]
+	`)
+
+	var cs apiextensions.JSON
+	err := json.Unmarshal(conditions, &cs)
+	assert.NilError(t, err)
+
+	path, err := validateConditions(cs, "conditions")
+	assert.Assert(t, err != nil)
+	assert.Equal(t, path, "conditions[0].operator")
+
+	conditions = []byte(`
+	[
+		{
+			"key":"{{request.object.metadata.name}}",
+			"operator":"AnyIn",
+			"value": {"foo": "bar"}
+		}
+	]
+	`)
+
+	err = json.Unmarshal(conditions, &cs)
+	assert.NilError(t, err)
+
+	path, err = validateConditions(cs, "conditions")
+	assert.Assert(t, err != nil)
+	assert.Equal(t, path, "conditions[0].value")
+}
+
 func Test_Validate_ResourceDescription_MissingKindsOnExclude(t *testing.T) {
 	var err error
 	excludeResourcedescirption := []byte(`
@@ -1336,44 +1409,6 @@ func Test_Validate_Any_Kind(t *testing.T) {
 	assert.Assert(t, err != nil)
 }
 
-func Test_checkAutoGenRules(t *testing.T) {
-	testCases := []struct {
-		name           string
-		policy         []byte
-		expectedResult bool
-	}{
-		{
-			name:           "rule-missing-autogen-cronjob",
-			policy:         []byte(`{"apiVersion":"kyverno.io/v1","kind":"ClusterPolicy","metadata":{"name":"test","annotations":{"pod-policies.kyverno.io/autogen-controllers":"Deployment,CronJob"}},"spec":{"rules":[{"match":{"resources":{"kinds":["Pod"]}},"name":"block-old-flux","validate":{"message":"CannotuseoldFluxv1annotation.","pattern":{"metadata":{"=(annotations)":{"X(fluxcd.io/*)":"*?"}}}}},{"match":{"resources":{"kinds":["Deployment"]}},"name":"autogen-block-old-flux","validate":{"message":"CannotuseoldFluxv1annotation.","pattern":{"spec":{"template":{"metadata":{"=(annotations)":{"X(fluxcd.io/*)":"*?"}}}}}}}]}}`),
-			expectedResult: true,
-		},
-		{
-			name:           "rule-missing-autogen-deployment",
-			policy:         []byte(`{"apiVersion":"kyverno.io/v1","kind":"ClusterPolicy","metadata":{"name":"test","annotations":{"pod-policies.kyverno.io/autogen-controllers":"Deployment,CronJob"}},"spec":{"rules":[{"match":{"resources":{"kinds":["Pod"]}},"name":"block-old-flux","validate":{"message":"CannotuseoldFluxv1annotation.","pattern":{"metadata":{"=(annotations)":{"X(fluxcd.io/*)":"*?"}}}}},{"match":{"resources":{"kinds":["CronJob"]}},"name":"autogen-cronjob-block-old-flux","validate":{"message":"CannotuseoldFluxv1annotation.","pattern":{"spec":{"jobTemplate":{"spec":{"template":{"metadata":{"=(annotations)":{"X(fluxcd.io/*)":"*?"}}}}}}}}}]}}`),
-			expectedResult: true,
-		},
-		{
-			name:           "rule-missing-autogen-all",
-			policy:         []byte(`{"apiVersion":"kyverno.io/v1","kind":"ClusterPolicy","metadata":{"name":"test","annotations":{"pod-policies.kyverno.io/autogen-controllers":"Deployment,CronJob,StatefulSet,Job,DaemonSet"}},"spec":{"rules":[{"match":{"resources":{"kinds":["Pod"]}},"name":"block-old-flux","validate":{"message":"CannotuseoldFluxv1annotation.","pattern":{"metadata":{"=(annotations)":{"X(fluxcd.io/*)":"*?"}}}}}]}}`),
-			expectedResult: true,
-		},
-		{
-			name:           "rule-with-autogen-disabled",
-			policy:         []byte(`{"apiVersion":"kyverno.io/v1","kind":"ClusterPolicy","metadata":{"name":"test","annotations":{"pod-policies.kyverno.io/autogen-controllers":"none"}},"spec":{"rules":[{"match":{"resources":{"kinds":["Pod"]}},"name":"block-old-flux","validate":{"message":"CannotuseoldFluxv1annotation.","pattern":{"metadata":{"=(annotations)":{"X(fluxcd.io/*)":"*?"}}}}}]}}`),
-			expectedResult: false,
-		},
-	}
-
-	for _, test := range testCases {
-		var policy kyverno.ClusterPolicy
-		err := json.Unmarshal(test.policy, &policy)
-		assert.NilError(t, err)
-
-		res := missingAutoGenRules(&policy, log.Log)
-		assert.Equal(t, test.expectedResult, res, fmt.Sprintf("test %s failed", test.name))
-	}
-}
-
 func Test_Validate_ApiCall(t *testing.T) {
 	testCases := []struct {
 		resource       kyverno.ContextEntry
@@ -1406,6 +1441,15 @@ func Test_Validate_ApiCall(t *testing.T) {
 			},
 			expectedResult: nil,
 		},
+		{
+			resource: kyverno.ContextEntry{
+				APICall: &kyverno.APICall{
+					URLPath:  "/apis/networking.k8s.io/v1/namespaces/{{request.namespace}}/networkpolicies",
+					JMESPath: "items[].metadata.name | sum_names(@)",
+				},
+			},
+			expectedResult: "failed to parse JMESPath items[].metadata.name | sum_names(@): unknown JMESPath function 'sum_names'",
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -1418,6 +1462,67 @@ func Test_Validate_ApiCall(t *testing.T) {
 		}
 	}
 }
+
+func Test_Validate_VariableExpressions(t *testing.T) {
+	testCases := []struct {
+		rule          []byte
+		expectedError string
+	}{
+		{
+			rule: []byte(`{
+				"name": "limit-memory",
+				"validate": {
+					"deny": {
+						"conditions": [{
+							"key": "{{ add(request.object.spec.containers[0].resources.limits.memory, '512Mi') }}",
+							"operator": "GreaterThan",
+							"value": "2Gi"
+						}]
+					}
+				}
+			}`),
+		},
+		{
+			rule: []byte(`{
+				"name": "unknown-function",
+				"validate": {
+					"message": "{{ concat(request.object.spec.containers[].name) }}",
+					"pattern": {"metadata": {"name": "?*"}}
+				}
+			}`),
+			expectedError: "invalid variable {{ concat(request.object.spec.containers[].name) }}: unknown JMESPath function 'concat'",
+		},
+		{
+			rule: []byte(`{
+				"name": "invalid-literal",
+				"validate": {
+					"deny": {
+						"conditions": [{
+							"key": "{{ truncate(request.object.metadata.name, '5') }}",
+							"operator": "Equals",
+							"value": "kyver"
+						}]
+					}
+				}
+			}`),
+			expectedError: "JMESPath function 'truncate': 2 argument is expected of [number] type",
+		},
+	}
+
+	for _, testCase := range testCases {
+		var rule kyverno.Rule
+		err := json.Unmarshal(testCase.rule, &rule)
+		assert.NilError(t, err)
+
+		err = validateVariableExpressions(rule)
+		if testCase.expectedError == "" {
+			assert.NilError(t, err)
+		} else {
+			assert.ErrorContains(t, err, testCase.expectedError)
+		}
+	}
+}
+
 func Test_Wildcards_Kind(t *testing.T) {
 	rawPolicy := []byte(`
 	{
@@ -1462,3 +1567,158 @@ func Test_Wildcards_Kind(t *testing.T) {
 	err = Validate(policy, nil, true, openAPIController)
 	assert.Assert(t, err != nil)
 }
+
+func Test_Validate_ValidationFailureActionOverrides(t *testing.T) {
+	testCases := []struct {
+		overrides     []byte
+		expectedPath  string
+		expectedError string
+	}{
+		{
+			overrides: []byte(`[
+				{"action": "enforce", "namespaces": ["prod-*"]},
+				{"action": "audit", "namespaceSelector": {"matchLabels": {"env": "dev"}}}
+			]`),
+		},
+		{
+			overrides:     []byte(`[{"action": "block", "namespaces": ["prod"]}]`),
+			expectedPath:  "validationFailureActionOverrides[0].action",
+			expectedError: "invalid action block",
+		},
+		{
+			overrides: []byte(`[
+				{"action": "enforce", "namespaces": ["prod"]},
+				{"action": "audit"}
+			]`),
+			expectedPath:  "validationFailureActionOverrides[1]",
+			expectedError: "namespaces or namespaceSelector is required",
+		},
+		{
+			overrides:     []byte(`[{"action": "enforce", "namespaceSelector": {"matchExpressions": [{"key": "env", "operator": "Invalid"}]}}]`),
+			expectedPath:  "validationFailureActionOverrides[0].namespaceSelector",
+			expectedError: "is not a valid pod selector operator",
+		},
+	}
+
+	for _, testCase := range testCases {
+		var overrides []kyverno.ValidationFailureActionOverride
+		err := json.Unmarshal(testCase.overrides, &overrides)
+		assert.NilError(t, err)
+
+		path, err := validateValidationFailureActionOverrides(overrides)
+		if testCase.expectedError == "" {
+			assert.NilError(t, err)
+		} else {
+			assert.ErrorContains(t, err, testCase.expectedError)
+			assert.Equal(t, path, testCase.expectedPath)
+		}
+	}
+}
+
+func Test_Validate_WebhookSettings(t *testing.T) {
+	testCases := []struct {
+		spec          []byte
+		expectedPath  string
+		expectedError string
+	}{
+		{
+			spec: []byte(`{}`),
+		},
+		{
+			spec: []byte(`{"failurePolicy": "Fail", "webhookTimeoutSeconds": 10}`),
+		},
+		{
+			spec:          []byte(`{"failurePolicy": "Retry"}`),
+			expectedPath:  "failurePolicy",
+			expectedError: "invalid failure policy Retry",
+		},
+		{
+			spec:          []byte(`{"webhookTimeoutSeconds": 0}`),
+			expectedPath:  "webhookTimeoutSeconds",
+			expectedError: "invalid timeout 0",
+		},
+		{
+			spec:          []byte(`{"failurePolicy": "Ignore", "webhookTimeoutSeconds": 31}`),
+			expectedPath:  "webhookTimeoutSeconds",
+			expectedError: "invalid timeout 31",
+		},
+	}
+
+	for _, testCase := range testCases {
+		var spec kyverno.Spec
+		err := json.Unmarshal(testCase.spec, &spec)
+		assert.NilError(t, err)
+
+		path, err := validateWebhookSettings(spec)
+		if testCase.expectedError == "" {
+			assert.NilError(t, err)
+		} else {
+			assert.ErrorContains(t, err, testCase.expectedError)
+			assert.Equal(t, path, testCase.expectedPath)
+		}
+	}
+}
+
+func Test_Validate_AutogenRules(t *testing.T) {
+	// the generated rule names are truncated to 63 characters and are no longer unique
+	rawPolicy := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {
+			"name": "require-labels"
+		},
+		"spec": {
+			"rules": [
+				{
+					"name": "require-the-team-label-on-all-pods-of-the-namespace-for-billing",
+					"match": {
+						"resources": {
+							"kinds": ["Pod"]
+						}
+					},
+					"validate": {
+						"message": "label team is required",
+						"pattern": {
+							"metadata": {
+								"labels": {
+									"team": "?*"
+								}
+							}
+						}
+					}
+				},
+				{
+					"name": "require-the-team-label-on-all-pods-of-the-namespace-for-routing",
+					"match": {
+						"resources": {
+							"kinds": ["Pod"]
+						}
+					},
+					"validate": {
+						"message": "label team is required",
+						"pattern": {
+							"metadata": {
+								"labels": {
+									"team": "?*"
+								}
+							}
+						}
+					}
+				}
+			]
+		}
+	}`)
+
+	var policy *kyverno.ClusterPolicy
+	err := json.Unmarshal(rawPolicy, &policy)
+	assert.NilError(t, err)
+
+	openAPIController, _ := openapi.NewOpenAPIController()
+	err = Validate(policy, nil, true, openAPIController)
+	assert.ErrorContains(t, err, "invalid rules auto-generated for pod controllers")
+
+	// the rules are valid when no rule is generated
+	policy.SetAnnotations(map[string]string{"pod-policies.kyverno.io/autogen-controllers": "none"})
+	err = Validate(policy, nil, true, openAPIController)
+	assert.NilError(t, err)
+}
@@ -2,12 +2,15 @@ package policycache
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
-	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/common"
+	"github.com/kyverno/kyverno/pkg/config"
 	policy2 "github.com/kyverno/kyverno/pkg/policy"
+	pm "github.com/kyverno/kyverno/pkg/policymutation"
+	prom "github.com/prometheus/client_golang/prometheus"
 )
 
 type pMap struct {
@@ -22,17 +25,38 @@ type pMap struct {
 	// nameCacheMap stores the names of all existing policies in dataMap
 	// Policy names are stored as <namespace>/<name>
 	nameCacheMap map[PolicyType]map[string]bool
+
+	// policies stores the cached policies by name, the policies returned by the cache
+	// include the rules auto-generated for the pod controllers at runtime
+	policies map[string]cachedPolicy
+}
+
+// cachedPolicy is a policy as written and with the rules auto-generated for the pod controllers
+type cachedPolicy struct {
+	policy   *kyverno.ClusterPolicy
+	expanded *kyverno.ClusterPolicy
+
+	// podControllers are the pod controllers the rules are auto-generated for
+	podControllers string
 }
 
 // policyCache ...
 type policyCache struct {
 	pMap
 	logr.Logger
-	// list/get cluster policy resource
-	pLister kyvernolister.ClusterPolicyLister
 
-	// npLister can list/get namespace policy from the shared informer's store
-	npLister kyvernolister.PolicyLister
+	// size records the number of cached policies per policy type, it is nil when metrics are disabled
+	size *prom.GaugeVec
+
+	// configHandler provides the pod controllers rules are auto-generated for,
+	// the default pod controllers are used when it is nil
+	configHandler config.Interface
+
+	// autogenLock serializes the additions of policies and the updates of the auto-generated rules
+	autogenLock sync.Mutex
+
+	// podControllers are the pod controllers the rules of all the cached policies are auto-generated for
+	podControllers atomic.Value
 }
 
 // Interface ...
@@ -53,7 +77,7 @@ type Interface interface {
 }
 
 // newPolicyCache ...
-func newPolicyCache(log logr.Logger, pLister kyvernolister.ClusterPolicyLister, npLister kyvernolister.PolicyLister) Interface {
+func newPolicyCache(log logr.Logger, size *prom.GaugeVec) Interface {
 	namesCache := map[PolicyType]map[string]bool{
 		Mutate:          make(map[string]bool),
 		ValidateEnforce: make(map[string]bool),
@@ -63,20 +87,25 @@ func newPolicyCache(log logr.Logger, pLister kyvernolister.ClusterPolicyLister,
 	}
 
 	return &policyCache{
-		pMap{
+		pMap: pMap{
 			nameCacheMap: namesCache,
 			kindDataMap:  make(map[string]map[PolicyType][]string),
+			policies:     make(map[string]cachedPolicy),
 		},
-		log,
-		pLister,
-		npLister,
+		Logger: log,
+		size:   size,
 	}
 }
 
 // Add a policy to cache
 func (pc *policyCache) Add(policy *kyverno.ClusterPolicy) {
-	pc.pMap.add(policy)
+	pc.autogenLock.Lock()
+	defer pc.autogenLock.Unlock()
+
+	podControllers := pc.getPodControllers()
+	pc.pMap.add(policy, pm.ExpandPolicy(policy, podControllers, pc.Logger), podControllers)
 	pc.Logger.V(4).Info("policy is added to cache", "name", policy.GetName())
+	pc.recordSize()
 }
 
 // Get the list of matched policies
@@ -84,6 +113,7 @@ func (pc *policyCache) get(pkey PolicyType, kind, nspace string) []string {
 	return pc.pMap.get(pkey, kind, nspace)
 }
 func (pc *policyCache) GetPolicies(pkey PolicyType, kind, nspace string) []*kyverno.ClusterPolicy {
+	pc.updateAutogenRules()
 	policies := pc.getPolicyObject(pkey, kind, "")
 	if nspace == "" {
 		return policies
@@ -97,13 +127,88 @@ func (pc *policyCache) GetPolicies(pkey PolicyType, kind, nspace string) []*kyve
 func (pc *policyCache) Remove(policy *kyverno.ClusterPolicy) {
 	pc.pMap.remove(policy)
 	pc.Logger.V(4).Info("policy is removed from cache", "name", policy.GetName())
+	pc.recordSize()
+}
+
+// getPodControllers returns the configured pod controllers rules are auto-generated for
+func (pc *policyCache) getPodControllers() string {
+	if pc.configHandler == nil {
+		return ""
+	}
+
+	return pc.configHandler.GetAutogenControllers()
+}
+
+// updateAutogenRules generates the rules of the cached policies again when the configured pod controllers have changed
+func (pc *policyCache) updateAutogenRules() {
+	podControllers := pc.getPodControllers()
+	if current, _ := pc.podControllers.Load().(string); current == podControllers {
+		return
+	}
+
+	pc.autogenLock.Lock()
+	defer pc.autogenLock.Unlock()
+
+	podControllers = pc.getPodControllers()
+	for _, cached := range pc.pMap.list() {
+		if cached.podControllers != podControllers {
+			pc.pMap.replace(cached, pm.ExpandPolicy(cached.policy, podControllers, pc.Logger), podControllers)
+		}
+	}
+
+	pc.podControllers.Store(podControllers)
+}
+
+// recordSize updates the policy cache size metric
+func (pc *policyCache) recordSize() {
+	if pc.size == nil {
+		return
+	}
+
+	for policyType, count := range pc.pMap.sizes() {
+		pc.size.With(prom.Labels{"policy_cache_type": policyType.String()}).Set(float64(count))
+	}
+}
+
+// add indexes the rules of the policy expanded with the rules auto-generated for the pod controllers
+func (m *pMap) add(policy, expanded *kyverno.ClusterPolicy, podControllers string) {
+	m.Lock()
+	defer m.Unlock()
+	m.addLocked(policy, expanded, podControllers)
 }
 
-func (m *pMap) add(policy *kyverno.ClusterPolicy) {
+// replace replaces the cached policy with the policy expanded again, unless the policy was removed or updated meanwhile
+func (m *pMap) replace(cached cachedPolicy, expanded *kyverno.ClusterPolicy, podControllers string) {
 	m.Lock()
 	defer m.Unlock()
 
+	pName := cached.policy.GetName()
+	if cached.policy.GetNamespace() != "" {
+		pName = cached.policy.GetNamespace() + "/" + pName
+	}
+
+	if current, ok := m.policies[pName]; !ok || current.policy != cached.policy {
+		return
+	}
+
+	m.removeLocked(cached.policy)
+	m.addLocked(cached.policy, expanded, podControllers)
+}
+
+// addLocked indexes the rules of the expanded policy, the caller holds the lock
+func (m *pMap) addLocked(policy, expanded *kyverno.ClusterPolicy, podControllers string) {
+	// policies with overrides may be enforced in some namespaces and audited in others,
+	// they are indexed as both and the webhooks select the action for the resource namespace
 	enforcePolicy := policy.Spec.ValidationFailureAction == "enforce"
+	auditPolicy := !enforcePolicy
+	for _, override := range policy.Spec.ValidationFailureActionOverrides {
+		if override.Action == "enforce" {
+			enforcePolicy = true
+		} else {
+			auditPolicy = true
+		}
+	}
+
 	mutateMap := m.nameCacheMap[Mutate]
 	validateEnforceMap := m.nameCacheMap[ValidateEnforce]
 	validateAuditMap := m.nameCacheMap[ValidateAudit]
@@ -116,19 +221,20 @@ func (m *pMap) add(policy *kyverno.ClusterPolicy) {
 		pName = pSpace + "/" + pName
 	}
 
-	for _, rule := range policy.Spec.Rules {
+	m.policies[pName] = cachedPolicy{policy: policy, expanded: expanded, podControllers: podControllers}
+	for _, rule := range expanded.Spec.Rules {
 
 		if len(rule.MatchResources.Any) > 0 {
 			for _, rmr := range rule.MatchResources.Any {
-				addCacheHelper(rmr, m, rule, mutateMap, pName, enforcePolicy, validateEnforceMap, validateAuditMap, generateMap, imageVerifyMap)
+				addCacheHelper(rmr, m, rule, mutateMap, pName, enforcePolicy, auditPolicy, validateEnforceMap, validateAuditMap, generateMap, imageVerifyMap)
 			}
 		} else if len(rule.MatchResources.All) > 0 {
 			for _, rmr := range rule.MatchResources.All {
-				addCacheHelper(rmr, m, rule, mutateMap, pName, enforcePolicy, validateEnforceMap, validateAuditMap, generateMap, imageVerifyMap)
+				addCacheHelper(rmr, m, rule, mutateMap, pName, enforcePolicy, auditPolicy, validateEnforceMap, validateAuditMap, generateMap, imageVerifyMap)
 			}
 		} else {
 			r := kyverno.ResourceFilter{UserInfo: rule.MatchResources.UserInfo, ResourceDescription: rule.MatchResources.ResourceDescription}
-			addCacheHelper(r, m, rule, mutateMap, pName, enforcePolicy, validateEnforceMap, validateAuditMap, generateMap, imageVerifyMap)
+			addCacheHelper(r, m, rule, mutateMap, pName, enforcePolicy, auditPolicy, validateEnforceMap, validateAuditMap, generateMap, imageVerifyMap)
 		}
 	}
 
@@ -139,7 +245,7 @@ func (m *pMap) add(policy *kyverno.ClusterPolicy) {
 	m.nameCacheMap[VerifyImages] = imageVerifyMap
 }
 
-func addCacheHelper(rmr kyverno.ResourceFilter, m *pMap, rule kyverno.Rule, mutateMap map[string]bool, pName string, enforcePolicy bool, validateEnforceMap map[string]bool, validateAuditMap map[string]bool, generateMap map[string]bool, imageVerifyMap map[string]bool) {
+func addCacheHelper(rmr kyverno.ResourceFilter, m *pMap, rule kyverno.Rule, mutateMap map[string]bool, pName string, enforcePolicy bool, auditPolicy bool, validateEnforceMap map[string]bool, validateAuditMap map[string]bool, generateMap map[string]bool, imageVerifyMap map[string]bool) {
 	for _, gvk := range rmr.Kinds {
 		_, kind := common.GetKindFromGVK(gvk)
 		_, ok := m.kindDataMap[kind]
@@ -163,14 +269,15 @@ func addCacheHelper(rmr kyverno.ResourceFilter, m *pMap, rule kyverno.Rule, muta
 					validatePolicy := m.kindDataMap[kind][ValidateEnforce]
 					m.kindDataMap[kind][ValidateEnforce] = append(validatePolicy, pName)
 				}
-				continue
 			}
 
 			// ValidateAudit
-			if !validateAuditMap[kind+"/"+pName] {
-				validateAuditMap[kind+"/"+pName] = true
-				validatePolicy := m.kindDataMap[kind][ValidateAudit]
-				m.kindDataMap[kind][ValidateAudit] = append(validatePolicy, pName)
+			if auditPolicy {
+				if !validateAuditMap[kind+"/"+pName] {
+					validateAuditMap[kind+"/"+pName] = true
+					validatePolicy := m.kindDataMap[kind][ValidateAudit]
+					m.kindDataMap[kind][ValidateAudit] = append(validatePolicy, pName)
+				}
 			}
 			continue
 		}
@@ -212,15 +319,49 @@ func (pc *pMap) get(key PolicyType, gvk, namespace string) (names []string) {
 	return names
 }
 
+// sizes returns the number of distinct policies cached for each policy type
+func (m *pMap) sizes() map[PolicyType]int {
+	m.RLock()
+	defer m.RUnlock()
+
+	names := map[PolicyType]map[string]bool{}
+	for policyType := range m.nameCacheMap {
+		names[policyType] = map[string]bool{}
+	}
+
+	for _, policies := range m.kindDataMap {
+		for policyType, pNames := range policies {
+			for _, pName := range pNames {
+				names[policyType][pName] = true
+			}
+		}
+	}
+
+	sizes := make(map[PolicyType]int, len(names))
+	for policyType, pNames := range names {
+		sizes[policyType] = len(pNames)
+	}
+	return sizes
+}
+
 func (m *pMap) remove(policy *kyverno.ClusterPolicy) {
 	m.Lock()
 	defer m.Unlock()
+	m.removeLocked(policy)
+}
+
+// removeLocked removes the rules of the cached policy from the index, the caller holds the lock
+func (m *pMap) removeLocked(policy *kyverno.ClusterPolicy) {
 	var pName = policy.GetName()
 	pSpace := policy.GetNamespace()
 	if pSpace != "" {
 		pName = pSpace + "/" + pName
 	}
 
+	if cached, ok := m.policies[pName]; ok {
+		policy = cached.expanded
+	}
+
 	for _, rule := range policy.Spec.Rules {
 
 		if len(rule.MatchResources.Any) > 0 {
@@ -236,6 +377,8 @@ func (m *pMap) remove(policy *kyverno.ClusterPolicy) {
 			removeCacheHelper(r, m, pName)
 		}
 	}
+
+	delete(m.policies, pName)
 }
 
 func removeCacheHelper(rmr kyverno.ResourceFilter, m *pMap, pName string) {
@@ -263,18 +406,25 @@ func removeCacheHelper(rmr kyverno.ResourceFilter, m *pMap, pName string) {
 func (m *policyCache) getPolicyObject(key PolicyType, gvk string, nspace string) (policyObject []*kyverno.ClusterPolicy) {
 	_, kind := common.GetKindFromGVK(gvk)
 	policyNames := m.pMap.get(key, kind, nspace)
+
+	m.pMap.RLock()
+	defer m.pMap.RUnlock()
 	for _, policyName := range policyNames {
-		var policy *kyverno.ClusterPolicy
-		ns, key, isNamespacedPolicy := policy2.ParseNamespacedPolicy(policyName)
-		if !isNamespacedPolicy {
-			policy, _ = m.pLister.Get(key)
-		} else {
-			if ns == nspace {
-				nspolicy, _ := m.npLister.Policies(ns).Get(key)
-				policy = policy2.ConvertPolicyToClusterPolicy(nspolicy)
-			}
+		if cached, ok := m.pMap.policies[policyName]; ok {
+			policyObject = append(policyObject, cached.expanded)
 		}
-		policyObject = append(policyObject, policy)
 	}
 	return policyObject
 }
+
+// list returns the cached policies
+func (m *pMap) list() []cachedPolicy {
+	m.RLock()
+	defer m.RUnlock()
+
+	policies := make([]cachedPolicy, 0, len(m.policies))
+	for _, cached := range m.policies {
+		policies = append(policies, cached)
+	}
+	return policies
+}
@@ -2,51 +2,19 @@ package policycache
 
 import (
 	"encoding/json"
-	"fmt"
 	"testing"
 
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
-
-	lv1 "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/config"
+	"github.com/kyverno/kyverno/pkg/engine"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"gotest.tools/assert"
-	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-type dummyLister struct {
-}
-
-func (dl dummyLister) List(selector labels.Selector) (ret []*kyverno.ClusterPolicy, err error) {
-	return nil, fmt.Errorf("not implemented")
-}
-
-func (dl dummyLister) Get(name string) (*kyverno.ClusterPolicy, error) {
-	return nil, fmt.Errorf("not implemented")
-}
-
-func (dl dummyLister) ListResources(selector labels.Selector) (ret []*kyverno.ClusterPolicy, err error) {
-	return nil, fmt.Errorf("not implemented")
-}
-
-// type dymmyNsNamespace struct {}
-
-type dummyNsLister struct {
-}
-
-func (dl dummyNsLister) Policies(name string) lv1.PolicyNamespaceLister {
-	return dummyNsLister{}
-}
-
-func (dl dummyNsLister) List(selector labels.Selector) (ret []*kyverno.Policy, err error) {
-	return nil, fmt.Errorf("not implemented")
-}
-
-func (dl dummyNsLister) Get(name string) (*kyverno.Policy, error) {
-	return nil, fmt.Errorf("not implemented")
-}
-
 func Test_All(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newPolicy(t)
 	//add
 	pCache.Add(policy)
@@ -78,7 +46,7 @@ func Test_All(t *testing.T) {
 }
 
 func Test_Add_Duplicate_Policy(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newPolicy(t)
 	pCache.Add(policy)
 	pCache.Add(policy)
@@ -104,7 +72,7 @@ func Test_Add_Duplicate_Policy(t *testing.T) {
 }
 
 func Test_Add_Validate_Audit(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newPolicy(t)
 	pCache.Add(policy)
 	pCache.Add(policy)
@@ -128,8 +96,38 @@ func Test_Add_Validate_Audit(t *testing.T) {
 	}
 }
 
+func Test_Add_Validate_Overrides(t *testing.T) {
+	pCache := newPolicyCache(log.Log, nil)
+	policy := newPolicy(t)
+	policy.Spec.ValidationFailureAction = "audit"
+	policy.Spec.ValidationFailureActionOverrides = []kyverno.ValidationFailureActionOverride{
+		{Action: "enforce", Namespaces: []string{"prod-*"}},
+	}
+	pCache.Add(policy)
+
+	for _, rule := range policy.Spec.Rules {
+		for _, kind := range rule.MatchResources.Kinds {
+			validateEnforce := pCache.get(ValidateEnforce, kind, "")
+			assert.Equal(t, len(validateEnforce), 1)
+
+			validateAudit := pCache.get(ValidateAudit, kind, "")
+			assert.Equal(t, len(validateAudit), 1)
+		}
+	}
+
+	pCache.Remove(policy)
+	assert.Equal(t, len(pCache.get(ValidateEnforce, "Pod", "")), 0)
+	assert.Equal(t, len(pCache.get(ValidateAudit, "Pod", "")), 0)
+
+	// an enforce policy with enforce overrides only is not audited
+	policy.Spec.ValidationFailureAction = "enforce"
+	pCache.Add(policy)
+	assert.Equal(t, len(pCache.get(ValidateEnforce, "Pod", "")), 1)
+	assert.Equal(t, len(pCache.get(ValidateAudit, "Pod", "")), 0)
+}
+
 func Test_Add_Remove(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newPolicy(t)
 	kind := "Pod"
 	pCache.Add(policy)
@@ -157,7 +155,7 @@ func Test_Add_Remove(t *testing.T) {
 }
 
 func Test_Add_Remove_Any(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newAnyPolicy(t)
 	kind := "Pod"
 	pCache.Add(policy)
@@ -185,7 +183,7 @@ func Test_Add_Remove_Any(t *testing.T) {
 }
 
 func Test_Remove_From_Empty_Cache(t *testing.T) {
-	pCache := newPolicyCache(log.Log, nil, nil)
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newPolicy(t)
 
 	pCache.Remove(policy)
@@ -818,7 +816,7 @@ func newNsMutatePolicy(t *testing.T) *kyverno.ClusterPolicy {
 }
 
 func Test_Ns_All(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newNsPolicy(t)
 	//add
 	pCache.Add(policy)
@@ -850,7 +848,7 @@ func Test_Ns_All(t *testing.T) {
 }
 
 func Test_Ns_Add_Duplicate_Policy(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newNsPolicy(t)
 	pCache.Add(policy)
 	pCache.Add(policy)
@@ -877,7 +875,7 @@ func Test_Ns_Add_Duplicate_Policy(t *testing.T) {
 }
 
 func Test_Ns_Add_Validate_Audit(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newNsPolicy(t)
 	pCache.Add(policy)
 	pCache.Add(policy)
@@ -902,7 +900,7 @@ func Test_Ns_Add_Validate_Audit(t *testing.T) {
 }
 
 func Test_Ns_Add_Remove(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newNsPolicy(t)
 	nspace := policy.GetNamespace()
 	kind := "Pod"
@@ -920,7 +918,7 @@ func Test_Ns_Add_Remove(t *testing.T) {
 }
 
 func Test_GVk_Cache(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newGVKPolicy(t)
 	//add
 	pCache.Add(policy)
@@ -936,7 +934,7 @@ func Test_GVk_Cache(t *testing.T) {
 }
 
 func Test_GVK_Add_Remove(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newGVKPolicy(t)
 	kind := "ClusterRole"
 	pCache.Add(policy)
@@ -953,7 +951,7 @@ func Test_GVK_Add_Remove(t *testing.T) {
 }
 
 func Test_Add_Validate_Enforce(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newUserTestPolicy(t)
 	nspace := policy.GetNamespace()
 	//add
@@ -969,7 +967,7 @@ func Test_Add_Validate_Enforce(t *testing.T) {
 }
 
 func Test_Ns_Add_Remove_User(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newUserTestPolicy(t)
 	nspace := policy.GetNamespace()
 	kind := "Deployment"
@@ -987,7 +985,7 @@ func Test_Ns_Add_Remove_User(t *testing.T) {
 }
 
 func Test_Mutate_Policy(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newMutatePolicy(t)
 	//add
 	pCache.Add(policy)
@@ -1006,7 +1004,7 @@ func Test_Mutate_Policy(t *testing.T) {
 }
 
 func Test_Generate_Policy(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newgenratePolicy(t)
 	//add
 	pCache.Add(policy)
@@ -1023,7 +1021,7 @@ func Test_Generate_Policy(t *testing.T) {
 }
 
 func Test_NsMutate_Policy(t *testing.T) {
-	pCache := newPolicyCache(log.Log, dummyLister{}, dummyNsLister{})
+	pCache := newPolicyCache(log.Log, nil)
 	policy := newMutatePolicy(t)
 	nspolicy := newNsMutatePolicy(t)
 	//add
@@ -1046,3 +1044,186 @@ func Test_NsMutate_Policy(t *testing.T) {
 	}
 
 }
+
+func Test_Subresource_Policy(t *testing.T) {
+	pCache := newPolicyCache(log.Log, nil)
+	policy := newSubresourcePolicy(t)
+	pCache.Add(policy)
+
+	validateEnforce := pCache.get(ValidateEnforce, "Pod/exec", "")
+	if len(validateEnforce) != 1 {
+		t.Errorf("expected 1 validate policy for Pod/exec, found %v", len(validateEnforce))
+	}
+
+	validateEnforce = pCache.get(ValidateEnforce, "Deployment/scale", "")
+	if len(validateEnforce) != 1 {
+		t.Errorf("expected 1 validate policy for Deployment/scale, found %v", len(validateEnforce))
+	}
+
+	// policies for subresources do not apply to the parent resource
+	validateEnforce = pCache.get(ValidateEnforce, "Pod", "")
+	if len(validateEnforce) != 0 {
+		t.Errorf("expected 0 validate policy for Pod, found %v", len(validateEnforce))
+	}
+
+	pCache.Remove(policy)
+	validateEnforce = pCache.get(ValidateEnforce, "Pod/exec", "")
+	if len(validateEnforce) != 0 {
+		t.Errorf("expected 0 validate policy for Pod/exec, found %v", len(validateEnforce))
+	}
+}
+
+func newSubresourcePolicy(t *testing.T) *kyverno.ClusterPolicy {
+	rawPolicy := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {
+		   "name": "deny-exec-and-scale"
+		},
+		"spec": {
+		   "validationFailureAction": "enforce",
+		   "rules": [
+			  {
+				 "name": "deny-exec-and-scale",
+				 "match": {
+					"resources": {
+					   "kinds": [
+						  "Pod/exec",
+						  "apps/v1/Deployment/scale"
+					   ]
+					}
+				 },
+				 "validate": {
+					"message": "Pods cannot be executed into and Deployments cannot be scaled.",
+					"deny": {}
+				 }
+			  }
+		   ]
+		}
+	 }`)
+
+	var policy *kyverno.ClusterPolicy
+	err := json.Unmarshal(rawPolicy, &policy)
+	assert.NilError(t, err)
+
+	return policy
+}
+
+func Test_Size_Metric(t *testing.T) {
+	size := prom.NewGaugeVec(prom.GaugeOpts{Name: "kyverno_policy_cache_size"}, []string{"policy_cache_type"})
+	pCache := newPolicyCache(log.Log, size)
+	policy := newMutatePolicy(t)
+	nspolicy := newNsMutatePolicy(t)
+
+	pCache.Add(policy)
+	pCache.Add(policy)
+	pCache.Add(nspolicy)
+	assert.Equal(t, testutil.ToFloat64(size.With(prom.Labels{"policy_cache_type": "mutate"})), float64(2))
+	assert.Equal(t, testutil.ToFloat64(size.With(prom.Labels{"policy_cache_type": "generate"})), float64(0))
+
+	pCache.Remove(policy)
+	assert.Equal(t, testutil.ToFloat64(size.With(prom.Labels{"policy_cache_type": "mutate"})), float64(1))
+}
+
+// autogenConfig configures the pod controllers rules are auto-generated for
+type autogenConfig struct {
+	config.Interface
+	podControllers string
+}
+
+func (c *autogenConfig) GetAutogenControllers() string {
+	return c.podControllers
+}
+
+func Test_Autogen_Rules(t *testing.T) {
+	pCache := newPolicyCache(log.Log, nil)
+	policy := newPodPolicy(t)
+
+	pCache.Add(policy)
+	for _, kind := range []string{"Pod", "Deployment", "CronJob"} {
+		validateEnforce := pCache.get(ValidateEnforce, kind, "")
+		if len(validateEnforce) != 1 {
+			t.Errorf("expected 1 validate policy for %s, found %v", kind, len(validateEnforce))
+		}
+	}
+
+	// the policy spec is left untouched, the generated rules are merged into the cached policy once
+	assert.Equal(t, len(policy.Spec.Rules), 1)
+	policies := pCache.GetPolicies(ValidateEnforce, "Deployment", "")
+	assert.Equal(t, len(policies), 1)
+	expanded := policies[0]
+	assert.Equal(t, len(expanded.Spec.Rules), 3)
+	assert.Equal(t, expanded.Spec.Rules[1].Name, "autogen-require-team-label")
+	assert.Equal(t, expanded.Spec.Rules[2].Name, "autogen-cronjob-require-team-label")
+	assert.Equal(t, expanded.GetAnnotations()[engine.PodControllersAnnotation], engine.PodControllers)
+	assert.Assert(t, pCache.GetPolicies(ValidateEnforce, "Pod", "")[0] == expanded)
+
+	pCache.Remove(policy)
+	for _, kind := range []string{"Pod", "Deployment", "CronJob"} {
+		validateEnforce := pCache.get(ValidateEnforce, kind, "")
+		if len(validateEnforce) != 0 {
+			t.Errorf("expected 0 validate policy for %s, found %v", kind, len(validateEnforce))
+		}
+	}
+}
+
+func Test_Autogen_Rules_ConfiguredControllers(t *testing.T) {
+	pCache := newPolicyCache(log.Log, nil).(*policyCache)
+	configHandler := &autogenConfig{podControllers: "Deployment"}
+	pCache.configHandler = configHandler
+	policy := newPodPolicy(t)
+
+	pCache.Add(policy)
+	assert.Equal(t, len(pCache.GetPolicies(ValidateEnforce, "Deployment", "")), 1)
+	assert.Equal(t, len(pCache.GetPolicies(ValidateEnforce, "StatefulSet", "")), 0)
+
+	// the rules are generated again when the configured pod controllers change
+	configHandler.podControllers = "StatefulSet,CronJob"
+	assert.Equal(t, len(pCache.GetPolicies(ValidateEnforce, "Deployment", "")), 0)
+	policies := pCache.GetPolicies(ValidateEnforce, "StatefulSet", "")
+	assert.Equal(t, len(policies), 1)
+	assert.Equal(t, policies[0].GetAnnotations()[engine.PodControllersAnnotation], "StatefulSet,CronJob")
+	assert.Equal(t, len(pCache.GetPolicies(ValidateEnforce, "CronJob", "")), 1)
+	assert.Assert(t, policy.GetAnnotations() == nil)
+}
+
+func newPodPolicy(t *testing.T) *kyverno.ClusterPolicy {
+	rawPolicy := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {
+		   "name": "require-team-label"
+		},
+		"spec": {
+		   "validationFailureAction": "enforce",
+		   "rules": [
+			  {
+				 "name": "require-team-label",
+				 "match": {
+					"resources": {
+					   "kinds": [
+						  "Pod"
+					   ]
+					}
+				 },
+				 "validate": {
+					"message": "The label team is required.",
+					"pattern": {
+					   "metadata": {
+						  "labels": {
+							 "team": "?*"
+						  }
+					   }
+					}
+				 }
+			  }
+		   ]
+		}
+	 }`)
+
+	var policy *kyverno.ClusterPolicy
+	err := json.Unmarshal(rawPolicy, &policy)
+	assert.NilError(t, err)
+
+	return policy
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/config"
+	"github.com/kyverno/kyverno/pkg/engine"
+	prom "github.com/prometheus/client_golang/prometheus"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -25,10 +28,15 @@ type Controller struct {
 func NewPolicyCacheController(
 	pInformer kyvernoinformer.ClusterPolicyInformer,
 	nspInformer kyvernoinformer.PolicyInformer,
+	size *prom.GaugeVec,
+	configHandler config.Interface,
 	log logr.Logger) *Controller {
 
+	policyCache := newPolicyCache(log, size).(*policyCache)
+	policyCache.configHandler = configHandler
+
 	pc := Controller{
-		Cache: newPolicyCache(log, pInformer.Lister(), nspInformer.Lister()),
+		Cache: policyCache,
 		log:   log,
 	}
 
@@ -68,7 +76,7 @@ func (c *Controller) updatePolicy(old, cur interface{}) {
 	pOld := old.(*kyverno.ClusterPolicy)
 	pNew := cur.(*kyverno.ClusterPolicy)
 
-	if reflect.DeepEqual(pOld.Spec, pNew.Spec) {
+	if reflect.DeepEqual(pOld.Spec, pNew.Spec) && !autogenAnnotationChanged(pOld.GetAnnotations(), pNew.GetAnnotations()) {
 		return
 	}
 	c.Cache.Remove(pOld)
@@ -80,6 +88,11 @@ func (c *Controller) deletePolicy(obj interface{}) {
 	c.Cache.Remove(p)
 }
 
+// autogenAnnotationChanged returns true if the pod controllers rules are auto-generated for have changed
+func autogenAnnotationChanged(old, cur map[string]string) bool {
+	return old[engine.PodControllersAnnotation] != cur[engine.PodControllersAnnotation]
+}
+
 // addNsPolicy - Add Policy to cache
 func (c *Controller) addNsPolicy(obj interface{}) {
 	p := obj.(*kyverno.Policy)
@@ -90,7 +103,7 @@ func (c *Controller) addNsPolicy(obj interface{}) {
 func (c *Controller) updateNsPolicy(old, cur interface{}) {
 	npOld := old.(*kyverno.Policy)
 	npNew := cur.(*kyverno.Policy)
-	if reflect.DeepEqual(npOld.Spec, npNew.Spec) {
+	if reflect.DeepEqual(npOld.Spec, npNew.Spec) && !autogenAnnotationChanged(npOld.GetAnnotations(), npNew.GetAnnotations()) {
 		return
 	}
 	c.Cache.Remove(convertPolicyToClusterPolicy(npOld))
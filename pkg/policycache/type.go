@@ -11,3 +11,20 @@ const (
 	Generate
 	VerifyImages
 )
+
+func (t PolicyType) String() string {
+	switch t {
+	case Mutate:
+		return "mutate"
+	case ValidateEnforce:
+		return "validateEnforce"
+	case ValidateAudit:
+		return "validateAudit"
+	case Generate:
+		return "generate"
+	case VerifyImages:
+		return "verifyImages"
+	default:
+		return "unknown"
+	}
+}
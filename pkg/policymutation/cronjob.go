@@ -64,6 +64,28 @@ func generateCronJobRule(rule kyverno.Rule, controllers string, log logr.Logger)
 		return *cronJobRule
 	}
 
+	if (jobRule.Mutation != nil) && (len(jobRule.Mutation.Patches) > 0) {
+		patches, err := shiftPatches(jobRule.Mutation.Patches, "/spec/jobTemplate")
+		if err != nil {
+			logger.Error(err, "failed to generate patches for cronJob")
+			return kyvernoRule{}
+		}
+
+		cronJobRule.Mutation = &kyverno.Mutation{Patches: patches}
+		return *cronJobRule
+	}
+
+	if (jobRule.Mutation != nil) && (jobRule.Mutation.PatchesJSON6902 != "") {
+		patchesJSON6902, err := shiftPatchesJSON6902(jobRule.Mutation.PatchesJSON6902, "/spec/jobTemplate")
+		if err != nil {
+			logger.Error(err, "failed to generate patchesJson6902 for cronJob")
+			return kyvernoRule{}
+		}
+
+		cronJobRule.Mutation = &kyverno.Mutation{PatchesJSON6902: patchesJSON6902}
+		return *cronJobRule
+	}
+
 	if (jobRule.Validation != nil) && (jobRule.Validation.Pattern != nil) {
 		newValidate := &kyverno.Validation{
 			Message: variables.FindAndShiftReferences(log, rule.Validation.Message, "spec/jobTemplate/spec/template", "pattern"),
@@ -101,6 +123,11 @@ func generateCronJobRule(rule kyverno.Rule, controllers string, log logr.Logger)
 		return *cronJobRule
 	}
 
+	if (jobRule.Validation != nil) && (jobRule.Validation.Deny != nil) {
+		cronJobRule.Validation = jobRule.Validation.DeepCopy()
+		return *cronJobRule
+	}
+
 	return kyvernoRule{}
 }
 
@@ -5,23 +5,25 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 
-	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/common"
+	"github.com/kyverno/kyverno/pkg/config"
 	"github.com/kyverno/kyverno/pkg/engine"
 	"github.com/kyverno/kyverno/pkg/engine/variables"
 	"github.com/kyverno/kyverno/pkg/utils"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"sigs.k8s.io/yaml"
 )
 
 // GenerateJSONPatchesForDefaults generates default JSON patches for
 // - ValidationFailureAction
 // - Background
-// - auto-gen annotation and rules
+// The rules for pod controllers are generated at runtime and the auto-gen annotation is not set, see ExpandPolicy.
 func GenerateJSONPatchesForDefaults(policy *kyverno.ClusterPolicy, log logr.Logger) ([]byte, []string) {
 	var patches [][]byte
 	var updateMsgs []string
@@ -38,17 +40,6 @@ func GenerateJSONPatchesForDefaults(policy *kyverno.ClusterPolicy, log logr.Logg
 		updateMsgs = append(updateMsgs, updateMsg)
 	}
 
-	patch, errs := GeneratePodControllerRule(*policy, log)
-	if len(errs) > 0 {
-		var errMsgs []string
-		for _, err := range errs {
-			errMsgs = append(errMsgs, err.Error())
-			log.Error(err, "failed to generate pod controller rule")
-		}
-		updateMsgs = append(updateMsgs, strings.Join(errMsgs, ";"))
-	}
-	patches = append(patches, patch...)
-
 	convertPatch, errs := convertPatchToJSON6902(policy, log)
 	if len(errs) > 0 {
 		var errMsgs []string
@@ -216,41 +207,116 @@ func defaultvalidationFailureAction(policy *kyverno.ClusterPolicy, log logr.Logg
 // scenario C: some certain controllers that user set -> generate on defined controllers
 //             copy entire match / exclude block, it's users' responsibility to
 //             make sure all fields are applicable to pod controllers
+//
+// controllers are set as a comma-separated list of kinds, a kind can be followed by "=" and
+// the path of its pod template when it is not spec.template, e.g. "Deployment,CloneSet,Foo=spec.podTemplate"
 
-// GeneratePodControllerRule returns two patches: rulePatches and annotation patch(if necessary)
-func GeneratePodControllerRule(policy kyverno.ClusterPolicy, log logr.Logger) (patches [][]byte, errs []error) {
+// autogenControllers returns the controllers the rules of the policy are generated for,
+// podControllers are used when the policy does not have the auto-gen annotation
+func autogenControllers(policy kyverno.ClusterPolicy, podControllers string, log logr.Logger) string {
 	applyAutoGen, desiredControllers := CanAutoGen(&policy, log)
+	if !applyAutoGen {
+		return desiredControllers
+	}
 
-	ann := policy.GetAnnotations()
-	actualControllers, ok := ann[engine.PodControllersAnnotation]
+	if controllers, ok := policy.GetAnnotations()[engine.PodControllersAnnotation]; ok {
+		return controllers
+	}
 
-	// - scenario A
-	// - predefined controllers are invalid, overwrite the value
-	if !ok || !applyAutoGen {
-		actualControllers = desiredControllers
-		annPatch, err := defaultPodControllerAnnotation(ann, actualControllers)
-		if err != nil {
-			errs = append(errs, fmt.Errorf("failed to generate pod controller annotation for policy '%s': %v", policy.Name, err))
-		} else {
-			patches = append(patches, annPatch)
+	if podControllers != "" {
+		return podControllers
+	}
+
+	return desiredControllers
+}
+
+// GenerateRules returns the rules auto-generated for the pod controllers from the rules of the policy matching Pods.
+// The controllers are read from the auto-gen annotation, podControllers are used when the policy does not have
+// the annotation, all pod controllers if empty. The rules are not added to the policy, see ExpandPolicy.
+func GenerateRules(policy kyverno.ClusterPolicy, podControllers string, log logr.Logger) ([]kyverno.Rule, error) {
+	controllers := autogenControllers(policy, podControllers, log)
+	if controllers == "none" {
+		return nil, nil
+	}
+
+	log.V(3).Info("auto generating rule for pod controllers", "controllers", controllers)
+
+	rawRules, errs := generateRules(policy, controllers, log)
+	var rules []kyverno.Rule
+	for _, rawRule := range rawRules {
+		var rule kyverno.Rule
+		if err := json.Unmarshal(rawRule, &rule); err != nil {
+			errs = append(errs, err)
+			continue
 		}
-	} else {
-		if !applyAutoGen {
-			actualControllers = desiredControllers
+
+		rules = append(rules, rule)
+	}
+
+	if len(errs) > 0 {
+		var errMsgs []string
+		for _, err := range errs {
+			errMsgs = append(errMsgs, err.Error())
 		}
+
+		return rules, fmt.Errorf("failed to generate pod controller rules for policy '%s': %s", policy.Name, strings.Join(errMsgs, ";"))
+	}
+
+	return rules, nil
+}
+
+// ExpandPolicy returns a copy of the policy including the rules auto-generated for the pod controllers,
+// the policy itself is returned when no rule is generated. See GenerateRules and MergeRules.
+// The auto-gen annotation of the copy is set to the controllers the rules are generated for when the
+// policy does not have the annotation, so that the Pods created by these controllers are not evaluated again.
+func ExpandPolicy(policy *kyverno.ClusterPolicy, podControllers string, log logr.Logger) *kyverno.ClusterPolicy {
+	rules, err := GenerateRules(*policy, podControllers, log)
+	if err != nil {
+		log.Error(err, "failed to auto generate rules", "policy", policy.Name)
 	}
 
-	// scenario B
-	if actualControllers == "none" {
-		return patches, nil
+	expanded := MergeRules(policy, rules)
+	if expanded == policy {
+		return policy
 	}
 
-	log.V(3).Info("auto generating rule for pod controllers", "controllers", actualControllers)
+	annotations := expanded.GetAnnotations()
+	if _, ok := annotations[engine.PodControllersAnnotation]; !ok {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
 
-	p, err := generateRulePatches(policy, actualControllers, log)
-	patches = append(patches, p...)
-	errs = append(errs, err...)
-	return
+		annotations[engine.PodControllersAnnotation] = autogenControllers(*policy, podControllers, log)
+		expanded.SetAnnotations(annotations)
+	}
+
+	return expanded
+}
+
+// MergeRules returns a copy of the policy with the generated rules appended to its rules, a generated rule
+// replaces the rule of the same name, e.g. a rule generated by a previous release and stored in the spec.
+// The policy itself is returned when there are no generated rules.
+func MergeRules(policy *kyverno.ClusterPolicy, rules []kyverno.Rule) *kyverno.ClusterPolicy {
+	if len(rules) == 0 {
+		return policy
+	}
+
+	expanded := policy.DeepCopy()
+	ruleIndex := make(map[string]int, len(expanded.Spec.Rules))
+	for i, rule := range expanded.Spec.Rules {
+		ruleIndex[rule.Name] = i
+	}
+
+	for _, rule := range rules {
+		if i, ok := ruleIndex[rule.Name]; ok {
+			expanded.Spec.Rules[i] = *rule.DeepCopy()
+			continue
+		}
+
+		expanded.Spec.Rules = append(expanded.Spec.Rules, *rule.DeepCopy())
+	}
+
+	return expanded
 }
 
 // CanAutoGen checks whether the rule(s) (in policy) can be applied to Pod controllers
@@ -258,7 +324,7 @@ func GeneratePodControllerRule(policy kyverno.ClusterPolicy, log logr.Logger) (p
 // - "none" if:
 //          - name or selector is defined
 //          - mixed kinds (Pod + pod controller) is defined
-//          - mutate.Patches/mutate.PatchesJSON6902/validate.deny/generate rule is defined
+//          - generate rule is defined
 // - otherwise it returns all pod controllers
 func CanAutoGen(policy *kyverno.ClusterPolicy, log logr.Logger) (applyAutoGen bool, controllers string) {
 	for _, rule := range policy.Spec.Rules {
@@ -276,8 +342,7 @@ func CanAutoGen(policy *kyverno.ClusterPolicy, log logr.Logger) (applyAutoGen bo
 			return false, "none"
 		}
 
-		if rule.Mutation.Patches != nil || rule.Mutation.PatchesJSON6902 != "" ||
-			rule.Validation.Deny != nil || rule.HasGenerate() {
+		if rule.HasGenerate() {
 			return false, "none"
 		}
 	}
@@ -285,121 +350,111 @@ func CanAutoGen(policy *kyverno.ClusterPolicy, log logr.Logger) (applyAutoGen bo
 	return true, engine.PodControllers
 }
 
-func createRuleMap(rules []kyverno.Rule) map[string]kyvernoRule {
-	var ruleMap = make(map[string]kyvernoRule)
-	for _, rule := range rules {
-		var jsonFriendlyStruct kyvernoRule
+// updateGenRuleByteForTemplate replaces the references to the Pod with references to the pod template at the given path.
+// The deny conditions and messages keep the references to the name and the namespace of the controller, as these are
+// rarely set on pod templates.
+func updateGenRuleByteForTemplate(pbyte []byte, templatePath string, genRule kyvernoRule) (obj []byte) {
+	if err := json.Unmarshal(pbyte, &genRule); err != nil {
+		return obj
+	}
+	obj = []byte(strings.Replace(string(pbyte), "request.object.spec", "request.object."+templatePath+".spec", -1))
+	obj = []byte(strings.Replace(string(obj), "request.object.metadata", "request.object."+templatePath+".metadata", -1))
+	if genRule.Validation != nil && genRule.Validation.Deny != nil {
+		controllerMetadata := regexp.MustCompile(`request\.object\.` + regexp.QuoteMeta(templatePath) + `\.metadata\.(name|namespace)\b`)
+		obj = controllerMetadata.ReplaceAll(obj, []byte("request.object.metadata.$1"))
+	}
+	return obj
+}
 
-		jsonFriendlyStruct.Name = rule.Name
+// generateRules generates the JSON encoded rules for podControllers based on scenario A and C
+func generateRules(policy kyverno.ClusterPolicy, controllers string, log logr.Logger) (rules [][]byte, errs []error) {
+	podControllers, err := config.ParsePodControllers(controllers)
+	if err != nil {
+		return nil, []error{fmt.Errorf("invalid annotation %s: %v", engine.PodControllersAnnotation, err)}
+	}
+	templateKinds, hasCronJob, customTemplates := groupPodControllers(podControllers)
 
-		if !reflect.DeepEqual(rule.MatchResources, kyverno.MatchResources{}) {
-			jsonFriendlyStruct.MatchResources = rule.MatchResources.DeepCopy()
+	appendRule := func(genRule kyvernoRule, templatePath string) {
+		if reflect.DeepEqual(genRule, kyvernoRule{}) {
+			return
 		}
 
-		if !reflect.DeepEqual(rule.ExcludeResources, kyverno.ExcludeResources{}) {
-			jsonFriendlyStruct.ExcludeResources = rule.ExcludeResources.DeepCopy()
+		rawRule, err := json.Marshal(genRule)
+		if err != nil {
+			errs = append(errs, err)
+			return
 		}
 
-		if !reflect.DeepEqual(rule.Mutation, kyverno.Mutation{}) {
-			jsonFriendlyStruct.Mutation = rule.Mutation.DeepCopy()
+		if rawRule = updateGenRuleByteForTemplate(rawRule, templatePath, genRule); rawRule != nil {
+			rules = append(rules, rawRule)
 		}
+	}
+
+	for _, rule := range policy.Spec.Rules {
+		// handle all other controllers other than CronJob
+		appendRule(generateRuleForControllers(rule, strings.Join(templateKinds, ","), log), config.DefaultPodTemplatePath)
 
-		if !reflect.DeepEqual(rule.Validation, kyverno.Validation{}) {
-			jsonFriendlyStruct.Validation = rule.Validation.DeepCopy()
+		// handle CronJob, it appends an additional rule
+		if hasCronJob {
+			appendRule(generateCronJobRule(rule, engine.PodControllerCronJob, log), config.CronJobPodTemplatePath)
 		}
 
-		ruleMap[rule.Name] = jsonFriendlyStruct
-	}
-	return ruleMap
-}
-func updateGenRuleByte(pbyte []byte, kind string, genRule kyvernoRule) (obj []byte) {
-	if err := json.Unmarshal(pbyte, &genRule); err != nil {
-		return obj
-	}
-	if kind == "Pod" {
-		obj = []byte(strings.Replace(string(pbyte), "request.object.spec", "request.object.spec.template.spec", -1))
-	}
-	if kind == "Cronjob" {
-		obj = []byte(strings.Replace(string(pbyte), "request.object.spec", "request.object.spec.jobTemplate.spec.template.spec", -1))
+		// handle the controllers with another pod template path, each path appends an additional rule
+		for _, t := range customTemplates {
+			appendRule(generateRuleForTemplate(rule, autogenRuleName(strings.ToLower(t.kinds[0]), rule.Name), t.kinds, t.path, log), t.path)
+		}
 	}
-	obj = []byte(strings.Replace(string(obj), "request.object.metadata", "request.object.spec.template.metadata", -1))
-	return obj
-}
 
-// generateRulePatches generates rule for podControllers based on scenario A and C
-func generateRulePatches(policy kyverno.ClusterPolicy, controllers string, log logr.Logger) (rulePatches [][]byte, errs []error) {
-	insertIdx := len(policy.Spec.Rules)
+	return
+}
 
-	ruleMap := createRuleMap(policy.Spec.Rules)
-	var ruleIndex = make(map[string]int)
-	for index, rule := range policy.Spec.Rules {
-		ruleIndex[rule.Name] = index
-	}
+// podTemplate groups the pod controllers sharing a pod template path other than spec.template
+type podTemplate struct {
+	path  string
+	kinds []string
+}
 
-	for _, rule := range policy.Spec.Rules {
-		patchPostion := insertIdx
-		convertToPatches := func(genRule kyvernoRule, patchPostion int) []byte {
-			operation := "add"
-			if existingAutoGenRule, alreadyExists := ruleMap[genRule.Name]; alreadyExists {
-				existingAutoGenRuleRaw, _ := json.Marshal(existingAutoGenRule)
-				genRuleRaw, _ := json.Marshal(genRule)
-
-				if string(existingAutoGenRuleRaw) == string(genRuleRaw) {
-					return nil
+// groupPodControllers returns the kinds of the pod controllers whose pod template is at spec.template,
+// whether CronJob is a pod controller, and the other pod controllers grouped by pod template path
+func groupPodControllers(podControllers []config.PodController) (templateKinds []string, hasCronJob bool, customTemplates []podTemplate) {
+	for _, pc := range podControllers {
+		switch {
+		case pc.Kind == engine.PodControllerCronJob:
+			hasCronJob = true
+		case pc.TemplatePath == config.DefaultPodTemplatePath:
+			templateKinds = append(templateKinds, pc.Kind)
+		default:
+			found := false
+			for i := range customTemplates {
+				if customTemplates[i].path == pc.TemplatePath {
+					customTemplates[i].kinds = append(customTemplates[i].kinds, pc.Kind)
+					found = true
+					break
 				}
-				operation = "replace"
-				patchPostion = ruleIndex[genRule.Name]
-			}
-
-			// generate patch bytes
-			jsonPatch := struct {
-				Path  string      `json:"path"`
-				Op    string      `json:"op"`
-				Value interface{} `json:"value"`
-			}{
-				fmt.Sprintf("/spec/rules/%s", strconv.Itoa(patchPostion)),
-				operation,
-				genRule,
-			}
-			pbytes, err := json.Marshal(jsonPatch)
-			if err != nil {
-				errs = append(errs, err)
-				return nil
 			}
 
-			// check the patch
-			if _, err := jsonpatch.DecodePatch([]byte("[" + string(pbytes) + "]")); err != nil {
-				errs = append(errs, err)
-				return nil
+			if !found {
+				customTemplates = append(customTemplates, podTemplate{path: pc.TemplatePath, kinds: []string{pc.Kind}})
 			}
-
-			return pbytes
 		}
+	}
 
-		// handle all other controllers other than CronJob
-		genRule := generateRuleForControllers(rule, stripCronJob(controllers), log)
-		if !reflect.DeepEqual(genRule, kyvernoRule{}) {
-			pbytes := convertToPatches(genRule, patchPostion)
-			pbytes = updateGenRuleByte(pbytes, "Pod", genRule)
-			if pbytes != nil {
-				rulePatches = append(rulePatches, pbytes)
-			}
-			insertIdx++
-			patchPostion = insertIdx
-		}
+	return
+}
 
-		// handle CronJob, it appends an additional rule
-		genRule = generateCronJobRule(rule, controllers, log)
-		if !reflect.DeepEqual(genRule, kyvernoRule{}) {
-			pbytes := convertToPatches(genRule, patchPostion)
-			pbytes = updateGenRuleByte(pbytes, "Cronjob", genRule)
-			if pbytes != nil {
-				rulePatches = append(rulePatches, pbytes)
-			}
-			insertIdx++
-		}
+// autogenRuleName returns the name of a generated rule, rule names are limited to 63 characters
+func autogenRuleName(prefix, ruleName string) string {
+	name := "autogen-"
+	if prefix != "" {
+		name += prefix + "-"
 	}
-	return
+
+	name += ruleName
+	if len(name) > 63 {
+		name = name[:63]
+	}
+
+	return name
 }
 
 // the kyvernoRule holds the temporary kyverno rule struct
@@ -422,10 +477,25 @@ type kyvernoRule struct {
 	VerifyImages     []*kyverno.ImageVerification `json:"verifyImages,omitempty" yaml:"verifyImages,omitempty"`
 }
 
+// generateRuleForControllers generates the rule for the controllers whose pod template is at spec.template
 func generateRuleForControllers(rule kyverno.Rule, controllers string, log logr.Logger) kyvernoRule {
+	if controllers == "all" {
+		controllers = stripCronJob(engine.PodControllers)
+	}
+
+	var kinds []string
+	if controllers != "none" {
+		kinds = config.PodControllerKinds(controllers)
+	}
+
+	return generateRuleForTemplate(rule, autogenRuleName("", rule.Name), kinds, config.DefaultPodTemplatePath, log)
+}
+
+// generateRuleForTemplate generates a rule named name matching the kinds, that applies the rule to the pod template at templatePath
+func generateRuleForTemplate(rule kyverno.Rule, name string, kinds []string, templatePath string, log logr.Logger) kyvernoRule {
 	logger := log.WithName("generateRuleForControllers")
 
-	if strings.HasPrefix(rule.Name, "autogen-") || controllers == "" {
+	if strings.HasPrefix(rule.Name, "autogen-") || len(kinds) == 0 {
 		logger.V(5).Info("skip generateRuleForControllers")
 		return kyvernoRule{}
 	}
@@ -439,36 +509,6 @@ func generateRuleForControllers(rule kyverno.Rule, controllers string, log logr.
 		return kyvernoRule{}
 	}
 
-	// Support backwards compatibility
-	skipAutoGeneration := false
-	var controllersValidated []string
-	if controllers == "all" {
-		skipAutoGeneration = true
-	} else if controllers != "none" && controllers != "all" {
-		controllersList := map[string]int{"DaemonSet": 1, "Deployment": 1, "Job": 1, "StatefulSet": 1}
-		for _, value := range strings.Split(controllers, ",") {
-			if _, ok := controllersList[value]; ok {
-				controllersValidated = append(controllersValidated, value)
-			}
-		}
-		if len(controllersValidated) > 0 {
-			skipAutoGeneration = true
-		}
-	}
-
-	if skipAutoGeneration {
-		if controllers == "all" {
-			controllers = "DaemonSet,Deployment,Job,StatefulSet"
-		} else {
-			controllers = strings.Join(controllersValidated, ",")
-		}
-	}
-
-	name := fmt.Sprintf("autogen-%s", rule.Name)
-	if len(name) > 63 {
-		name = name[:63]
-	}
-
 	controllerRule := &kyvernoRule{
 		Name:           name,
 		MatchResources: match.DeepCopy(),
@@ -495,18 +535,15 @@ func generateRuleForControllers(rule kyverno.Rule, controllers string, log logr.
 	}
 
 	// overwrite Kinds by pod controllers defined in the annotation
-	controllerRule.MatchResources.Kinds = strings.Split(controllers, ",")
+	controllerRule.MatchResources.Kinds = append([]string{}, kinds...)
 	if len(exclude.Kinds) != 0 {
-		controllerRule.ExcludeResources.Kinds = strings.Split(controllers, ",")
+		controllerRule.ExcludeResources.Kinds = append([]string{}, kinds...)
 	}
 
+	shiftPath := strings.ReplaceAll(templatePath, ".", "/")
 	if rule.Mutation.Overlay != nil {
 		newMutation := &kyverno.Mutation{
-			PatchStrategicMerge: map[string]interface{}{
-				"spec": map[string]interface{}{
-					"template": rule.Mutation.Overlay,
-				},
-			},
+			PatchStrategicMerge: nestUnderPath(templatePath, rule.Mutation.Overlay),
 		}
 
 		controllerRule.Mutation = newMutation.DeepCopy()
@@ -515,25 +552,39 @@ func generateRuleForControllers(rule kyverno.Rule, controllers string, log logr.
 
 	if rule.Mutation.PatchStrategicMerge != nil {
 		newMutation := &kyverno.Mutation{
-			PatchStrategicMerge: map[string]interface{}{
-				"spec": map[string]interface{}{
-					"template": rule.Mutation.PatchStrategicMerge,
-				},
-			},
+			PatchStrategicMerge: nestUnderPath(templatePath, rule.Mutation.PatchStrategicMerge),
 		}
 
 		controllerRule.Mutation = newMutation.DeepCopy()
 		return *controllerRule
 	}
 
+	if len(rule.Mutation.Patches) > 0 {
+		patches, err := shiftPatches(rule.Mutation.Patches, "/"+shiftPath)
+		if err != nil {
+			logger.Error(err, "failed to generate patches for pod controllers", "rule", rule.Name)
+			return kyvernoRule{}
+		}
+
+		controllerRule.Mutation = &kyverno.Mutation{Patches: patches}
+		return *controllerRule
+	}
+
+	if rule.Mutation.PatchesJSON6902 != "" {
+		patchesJSON6902, err := shiftPatchesJSON6902(rule.Mutation.PatchesJSON6902, "/"+shiftPath)
+		if err != nil {
+			logger.Error(err, "failed to generate patchesJson6902 for pod controllers", "rule", rule.Name)
+			return kyvernoRule{}
+		}
+
+		controllerRule.Mutation = &kyverno.Mutation{PatchesJSON6902: patchesJSON6902}
+		return *controllerRule
+	}
+
 	if rule.Validation.Pattern != nil {
 		newValidate := &kyverno.Validation{
-			Message: variables.FindAndShiftReferences(log, rule.Validation.Message, "spec/template", "pattern"),
-			Pattern: map[string]interface{}{
-				"spec": map[string]interface{}{
-					"template": rule.Validation.Pattern,
-				},
-			},
+			Message: variables.FindAndShiftReferences(log, rule.Validation.Message, shiftPath, "pattern"),
+			Pattern: nestUnderPath(templatePath, rule.Validation.Pattern),
 		}
 		controllerRule.Validation = newValidate.DeepCopy()
 		return *controllerRule
@@ -547,22 +598,26 @@ func generateRuleForControllers(rule kyverno.Rule, controllers string, log logr.
 		}
 
 		for _, pattern := range anyPatterns {
-			newPattern := map[string]interface{}{
-				"spec": map[string]interface{}{
-					"template": pattern,
-				},
-			}
-
-			patterns = append(patterns, newPattern)
+			patterns = append(patterns, nestUnderPath(templatePath, pattern))
 		}
 
 		controllerRule.Validation = &kyverno.Validation{
-			Message:    variables.FindAndShiftReferences(log, rule.Validation.Message, "spec/template", "anyPattern"),
+			Message:    variables.FindAndShiftReferences(log, rule.Validation.Message, shiftPath, "anyPattern"),
 			AnyPattern: patterns,
 		}
 		return *controllerRule
 	}
 
+	// the variables of the deny conditions are shifted to the pod template with the rest of the rule,
+	// see updateGenRuleByteForTemplate
+	if rule.Validation.Deny != nil {
+		controllerRule.Validation = &kyverno.Validation{
+			Message: rule.Validation.Message,
+			Deny:    rule.Validation.Deny.DeepCopy(),
+		}
+		return *controllerRule
+	}
+
 	if rule.VerifyImages != nil {
 		newVerifyImages := make([]*kyverno.ImageVerification, len(rule.VerifyImages))
 		for i, vi := range rule.VerifyImages {
@@ -576,42 +631,73 @@ func generateRuleForControllers(rule kyverno.Rule, controllers string, log logr.
 	return kyvernoRule{}
 }
 
-// defaultPodControllerAnnotation inserts an annotation
-// "pod-policies.kyverno.io/autogen-controllers=<controllers>" to policy
-func defaultPodControllerAnnotation(ann map[string]string, controllers string) ([]byte, error) {
-	if ann == nil {
-		ann = make(map[string]string)
-		ann[engine.PodControllersAnnotation] = controllers
-		jsonPatch := struct {
-			Path  string      `json:"path"`
-			Op    string      `json:"op"`
-			Value interface{} `json:"value"`
-		}{
-			"/metadata/annotations",
-			"add",
-			ann,
-		}
+// nestUnderPath nests the value under the keys of the dot-separated path
+func nestUnderPath(path string, value interface{}) map[string]interface{} {
+	keys := strings.Split(path, ".")
+	nested := map[string]interface{}{keys[len(keys)-1]: value}
+	for i := len(keys) - 2; i >= 0; i-- {
+		nested = map[string]interface{}{keys[i]: nested}
+	}
 
-		patchByte, err := json.Marshal(jsonPatch)
+	return nested
+}
+
+// shiftJSONPointer moves a JSON pointer to a Pod field under the pod template at prefix,
+// only the metadata and spec of a Pod exist in a pod template
+func shiftJSONPointer(prefix, pointer string) (string, error) {
+	if pointer == "/spec" || pointer == "/metadata" || strings.HasPrefix(pointer, "/spec/") || strings.HasPrefix(pointer, "/metadata/") {
+		return prefix + pointer, nil
+	}
+
+	return "", fmt.Errorf("path %q is not in the pod template, expected a path under /metadata or /spec", pointer)
+}
+
+// shiftPatches moves the paths of the patches under the pod template at prefix
+func shiftPatches(patches []kyverno.Patch, prefix string) ([]kyverno.Patch, error) {
+	shifted := make([]kyverno.Patch, len(patches))
+	for i, patch := range patches {
+		path, err := shiftJSONPointer(prefix, patch.Path)
 		if err != nil {
 			return nil, err
 		}
-		return patchByte, nil
+
+		shifted[i] = patch
+		shifted[i].Path = path
+	}
+
+	return shifted, nil
+}
+
+// shiftPatchesJSON6902 moves the path and from fields of the JSON6902 operations under the pod template at prefix,
+// the operations are returned as YAML
+func shiftPatchesJSON6902(patchesJSON6902, prefix string) (string, error) {
+	operationsJSON, err := yaml.YAMLToJSON([]byte(patchesJSON6902))
+	if err != nil {
+		return "", fmt.Errorf("failed to convert patchesJson6902 to JSON: %v", err)
+	}
+
+	var operations []map[string]interface{}
+	if err := json.Unmarshal(operationsJSON, &operations); err != nil {
+		return "", fmt.Errorf("failed to decode patchesJson6902, expect a list of operations: %v", err)
 	}
 
-	jsonPatch := struct {
-		Path  string      `json:"path"`
-		Op    string      `json:"op"`
-		Value interface{} `json:"value"`
-	}{
-		"/metadata/annotations/pod-policies.kyverno.io~1autogen-controllers",
-		"add",
-		controllers,
+	for _, operation := range operations {
+		for _, key := range []string{"path", "from"} {
+			pointer, ok := operation[key].(string)
+			if !ok {
+				continue
+			}
+
+			if operation[key], err = shiftJSONPointer(prefix, pointer); err != nil {
+				return "", err
+			}
+		}
 	}
 
-	patchByte, err := json.Marshal(jsonPatch)
+	shifted, err := yaml.Marshal(operations)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	return patchByte, nil
+
+	return string(shifted), nil
 }
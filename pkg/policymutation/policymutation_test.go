@@ -41,17 +41,17 @@ func Test_Exclude(t *testing.T) {
 	policy := policies[0]
 	policy.Spec.Rules[0].ExcludeResources.Namespaces = []string{"fake-namespce"}
 
-	rulePatches, errs := generateRulePatches(*policy, engine.PodControllers, log.Log)
+	rules, errs := generateRules(*policy, engine.PodControllers, log.Log)
 	if len(errs) != 0 {
 		t.Log(errs)
 	}
 
-	expectedPatches := [][]byte{
-		[]byte(`{"path":"/spec/rules/1","op":"add","value":{"name":"autogen-validate-hostPath","match":{"resources":{"kinds":["DaemonSet","Deployment","Job","StatefulSet"]}},"exclude":{"resources":{"namespaces":["fake-namespce"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"template":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}}`),
-		[]byte(`{"path":"/spec/rules/2","op":"add","value":{"name":"autogen-cronjob-validate-hostPath","match":{"resources":{"kinds":["CronJob"]}},"exclude":{"resources":{"namespaces":["fake-namespce"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}}}}`),
+	expectedRules := [][]byte{
+		[]byte(`{"name":"autogen-validate-hostPath","match":{"resources":{"kinds":["DaemonSet","Deployment","Job","StatefulSet"]}},"exclude":{"resources":{"namespaces":["fake-namespce"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"template":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}`),
+		[]byte(`{"name":"autogen-cronjob-validate-hostPath","match":{"resources":{"kinds":["CronJob"]}},"exclude":{"resources":{"namespaces":["fake-namespce"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}}}`),
 	}
 
-	assert.DeepEqual(t, rulePatches, expectedPatches)
+	assert.DeepEqual(t, rules, expectedRules)
 }
 
 func Test_CronJobOnly(t *testing.T) {
@@ -74,16 +74,16 @@ func Test_CronJobOnly(t *testing.T) {
 		engine.PodControllersAnnotation: controllers,
 	})
 
-	rulePatches, errs := generateRulePatches(*policy, controllers, log.Log)
+	rules, errs := generateRules(*policy, controllers, log.Log)
 	if len(errs) != 0 {
 		t.Log(errs)
 	}
 
-	expectedPatches := [][]byte{
-		[]byte(`{"path":"/spec/rules/1","op":"add","value":{"name":"autogen-cronjob-validate-hostPath","match":{"resources":{"kinds":["CronJob"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}}}}`),
+	expectedRules := [][]byte{
+		[]byte(`{"name":"autogen-cronjob-validate-hostPath","match":{"resources":{"kinds":["CronJob"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}}}`),
 	}
 
-	assert.DeepEqual(t, rulePatches, expectedPatches)
+	assert.DeepEqual(t, rules, expectedRules)
 }
 
 func Test_CronJob_hasExclude(t *testing.T) {
@@ -112,16 +112,16 @@ func Test_CronJob_hasExclude(t *testing.T) {
 	rule.ExcludeResources.Namespaces = []string{"test"}
 	policy.Spec.Rules[0] = *rule
 
-	rulePatches, errs := generateRulePatches(*policy, controllers, log.Log)
+	rules, errs := generateRules(*policy, controllers, log.Log)
 	if len(errs) != 0 {
 		t.Log(errs)
 	}
 
-	expectedPatches := [][]byte{
-		[]byte(`{"path":"/spec/rules/1","op":"add","value":{"name":"autogen-cronjob-validate-hostPath","match":{"resources":{"kinds":["CronJob"]}},"exclude":{"resources":{"kinds":["CronJob"],"namespaces":["test"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}}}}`),
+	expectedRules := [][]byte{
+		[]byte(`{"name":"autogen-cronjob-validate-hostPath","match":{"resources":{"kinds":["CronJob"]}},"exclude":{"resources":{"kinds":["CronJob"],"namespaces":["test"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}}}`),
 	}
 
-	assert.DeepEqual(t, rulePatches, expectedPatches)
+	assert.DeepEqual(t, rules, expectedRules)
 }
 
 func Test_CronJobAndDeployment(t *testing.T) {
@@ -143,17 +143,17 @@ func Test_CronJobAndDeployment(t *testing.T) {
 		engine.PodControllersAnnotation: controllers,
 	})
 
-	rulePatches, errs := generateRulePatches(*policy, controllers, log.Log)
+	rules, errs := generateRules(*policy, controllers, log.Log)
 	if len(errs) != 0 {
 		t.Log(errs)
 	}
 
-	expectedPatches := [][]byte{
-		[]byte(`{"path":"/spec/rules/1","op":"add","value":{"name":"autogen-validate-hostPath","match":{"resources":{"kinds":["Deployment"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"template":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}}`),
-		[]byte(`{"path":"/spec/rules/2","op":"add","value":{"name":"autogen-cronjob-validate-hostPath","match":{"resources":{"kinds":["CronJob"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}}}}`),
+	expectedRules := [][]byte{
+		[]byte(`{"name":"autogen-validate-hostPath","match":{"resources":{"kinds":["Deployment"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"template":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}`),
+		[]byte(`{"name":"autogen-cronjob-validate-hostPath","match":{"resources":{"kinds":["CronJob"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}}}`),
 	}
 
-	assert.DeepEqual(t, rulePatches, expectedPatches)
+	assert.DeepEqual(t, rules, expectedRules)
 }
 
 func Test_getControllers(t *testing.T) {
@@ -185,7 +185,7 @@ func Test_getControllers(t *testing.T) {
 		{
 			name:                "rule-with-deny",
 			policy:              []byte(`{"apiVersion":"kyverno.io/v1","kind":"ClusterPolicy","metadata":{"name":"test"},"spec":{"rules":[{"name":"require-network-policy","match":{"resources":{"kinds":["Pod"]}},"validate":{"message":"testpolicy","deny":{"conditions":[{"key":"{{request.object.metadata.labels.foo}}","operator":"Equals","value":"bar"}]}}}]}}`),
-			expectedControllers: "none",
+			expectedControllers: engine.PodControllers,
 		},
 
 		{
@@ -211,7 +211,7 @@ func Test_getControllers(t *testing.T) {
 		{
 			name:                "rule-with-mutate-patches",
 			policy:              []byte(`{"apiVersion":"kyverno.io/v1","kind":"ClusterPolicy","metadata":{"name":"test"},"spec":{"rules":[{"name":"test","match":{"resources":{"kinds":["Pod"]}},"mutate":{"patchesJson6902":"-op:add\npath:/spec/containers/0/env/-1\nvalue:{\"name\":\"SERVICE\",\"value\":{{request.object.spec.template.metadata.labels.app}}}"}}]}}`),
-			expectedControllers: "none",
+			expectedControllers: engine.PodControllers,
 		},
 		{
 			name:                "rule-with-generate",
@@ -255,14 +255,205 @@ func Test_UpdateVariablePath(t *testing.T) {
 
 	policy := policies[0]
 
-	rulePatches, errs := generateRulePatches(*policy, engine.PodControllers, log.Log)
+	rules, errs := generateRules(*policy, engine.PodControllers, log.Log)
 	if len(errs) != 0 {
 		t.Log(errs)
 	}
-	expectedPatches := [][]byte{
-		[]byte(`{"path":"/spec/rules/1","op":"add","value":{"name":"autogen-select-secrets-from-volumes","match":{"resources":{"kinds":["DaemonSet","Deployment","Job","StatefulSet"]}},"context":[{"name":"volsecret","apiCall":{"urlPath":"/api/v1/namespaces/{{request.object.spec.template.metadata.namespace}}/secrets/{{request.object.spec.template.spec.volumes[0].secret.secretName}}","jmesPath":"metadata.labels.foo"}}],"preconditions":[{"key":"{{ request.operation }}","operator":"Equals","value":"CREATE"}],"validate":{"message":"The Secret named {{request.object.spec.template.spec.volumes[0].secret.secretName}} is restricted and may not be used.","pattern":{"spec":{"template":{"spec":{"containers":[{"image":"registry.domain.com/*"}]}}}}}}}`),
-		[]byte(`{"path":"/spec/rules/2","op":"add","value":{"name":"autogen-cronjob-select-secrets-from-volumes","match":{"resources":{"kinds":["CronJob"]}},"context":[{"name":"volsecret","apiCall":{"urlPath":"/api/v1/namespaces/{{request.object.spec.template.metadata.namespace}}/secrets/{{request.object.spec.jobTemplate.spec.template.spec.volumes[0].secret.secretName}}","jmesPath":"metadata.labels.foo"}}],"preconditions":[{"key":"{{ request.operation }}","operator":"Equals","value":"CREATE"}],"validate":{"message":"The Secret named {{request.object.spec.jobTemplate.spec.template.spec.volumes[0].secret.secretName}} is restricted and may not be used.","pattern":{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"containers":[{"image":"registry.domain.com/*"}]}}}}}}}}}`),
+	expectedRules := [][]byte{
+		[]byte(`{"name":"autogen-select-secrets-from-volumes","match":{"resources":{"kinds":["DaemonSet","Deployment","Job","StatefulSet"]}},"context":[{"name":"volsecret","apiCall":{"urlPath":"/api/v1/namespaces/{{request.object.spec.template.metadata.namespace}}/secrets/{{request.object.spec.template.spec.volumes[0].secret.secretName}}","jmesPath":"metadata.labels.foo"}}],"preconditions":[{"key":"{{ request.operation }}","operator":"Equals","value":"CREATE"}],"validate":{"message":"The Secret named {{request.object.spec.template.spec.volumes[0].secret.secretName}} is restricted and may not be used.","pattern":{"spec":{"template":{"spec":{"containers":[{"image":"registry.domain.com/*"}]}}}}}}`),
+		[]byte(`{"name":"autogen-cronjob-select-secrets-from-volumes","match":{"resources":{"kinds":["CronJob"]}},"context":[{"name":"volsecret","apiCall":{"urlPath":"/api/v1/namespaces/{{request.object.spec.jobTemplate.spec.template.metadata.namespace}}/secrets/{{request.object.spec.jobTemplate.spec.template.spec.volumes[0].secret.secretName}}","jmesPath":"metadata.labels.foo"}}],"preconditions":[{"key":"{{ request.operation }}","operator":"Equals","value":"CREATE"}],"validate":{"message":"The Secret named {{request.object.spec.jobTemplate.spec.template.spec.volumes[0].secret.secretName}} is restricted and may not be used.","pattern":{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"containers":[{"image":"registry.domain.com/*"}]}}}}}}}}`),
+	}
+
+	assert.DeepEqual(t, rules, expectedRules)
+}
+
+func Test_CustomPodControllers(t *testing.T) {
+	controllers := "Deployment,CloneSet,Foo=spec.podTemplate,Bar=spec.podTemplate"
+	dir, err := os.Getwd()
+	baseDir := filepath.Dir(filepath.Dir(dir))
+	assert.NilError(t, err)
+	file, err := ioutil.ReadFile(baseDir + "/test/best_practices/disallow_bind_mounts.yaml")
+	assert.NilError(t, err)
+	policies, err := utils.GetPolicy(file)
+	assert.NilError(t, err)
+
+	policy := policies[0]
+	policy.SetAnnotations(map[string]string{
+		engine.PodControllersAnnotation: controllers,
+	})
+
+	rules, errs := generateRules(*policy, controllers, log.Log)
+	assert.Equal(t, len(errs), 0)
+
+	expectedRules := [][]byte{
+		[]byte(`{"name":"autogen-validate-hostPath","match":{"resources":{"kinds":["Deployment","CloneSet"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"template":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}`),
+		[]byte(`{"name":"autogen-foo-validate-hostPath","match":{"resources":{"kinds":["Foo","Bar"]}},"validate":{"message":"Host path volumes are not allowed","pattern":{"spec":{"podTemplate":{"spec":{"=(volumes)":[{"X(hostPath)":"null"}]}}}}}}`),
 	}
 
-	assert.DeepEqual(t, rulePatches, expectedPatches)
+	assert.DeepEqual(t, rules, expectedRules)
+
+	_, errs = generateRules(*policy, "Deployment,CronJob=spec.template.foo", log.Log)
+	assert.Equal(t, len(errs), 1)
+}
+
+func Test_GeneratePodControllerRule_ConfiguredControllers(t *testing.T) {
+	dir, err := os.Getwd()
+	baseDir := filepath.Dir(filepath.Dir(dir))
+	assert.NilError(t, err)
+	file, err := ioutil.ReadFile(baseDir + "/test/best_practices/disallow_bind_mounts.yaml")
+	assert.NilError(t, err)
+	policies, err := utils.GetPolicy(file)
+	assert.NilError(t, err)
+
+	rules, err := GenerateRules(*policies[0], "ReplicaSet,Rollout", log.Log)
+	assert.NilError(t, err)
+	assert.Equal(t, len(rules), 1)
+	assert.DeepEqual(t, rules[0].MatchResources.Kinds, []string{"ReplicaSet", "Rollout"})
+
+	// the configured controllers are set on the expanded copy only
+	expanded := ExpandPolicy(policies[0], "ReplicaSet,Rollout", log.Log)
+	assert.Equal(t, expanded.GetAnnotations()[engine.PodControllersAnnotation], "ReplicaSet,Rollout")
+	_, ok := policies[0].GetAnnotations()[engine.PodControllersAnnotation]
+	assert.Assert(t, !ok)
+
+	// the controllers of the annotation take precedence over the configured ones
+	policies[0].SetAnnotations(map[string]string{engine.PodControllersAnnotation: "Deployment"})
+	rules, err = GenerateRules(*policies[0], "ReplicaSet,Rollout", log.Log)
+	assert.NilError(t, err)
+	assert.Equal(t, len(rules), 1)
+	assert.DeepEqual(t, rules[0].MatchResources.Kinds, []string{"Deployment"})
+}
+
+func Test_GenerateRules(t *testing.T) {
+	dir, err := os.Getwd()
+	baseDir := filepath.Dir(filepath.Dir(dir))
+	assert.NilError(t, err)
+	file, err := ioutil.ReadFile(baseDir + "/test/best_practices/disallow_bind_mounts.yaml")
+	assert.NilError(t, err)
+	policies, err := utils.GetPolicy(file)
+	assert.NilError(t, err)
+
+	policy := policies[0]
+	rules, err := GenerateRules(*policy, "", log.Log)
+	assert.NilError(t, err)
+	assert.Equal(t, len(rules), 2)
+	assert.Equal(t, rules[0].Name, "autogen-validate-hostPath")
+	assert.DeepEqual(t, rules[0].MatchResources.Kinds, []string{"DaemonSet", "Deployment", "Job", "StatefulSet"})
+	assert.Equal(t, rules[1].Name, "autogen-cronjob-validate-hostPath")
+	assert.DeepEqual(t, rules[1].MatchResources.Kinds, []string{"CronJob"})
+
+	// the annotation takes precedence over the default controllers
+	policy.SetAnnotations(map[string]string{engine.PodControllersAnnotation: "Deployment"})
+	rules, err = GenerateRules(*policy, "DaemonSet,CronJob", log.Log)
+	assert.NilError(t, err)
+	assert.Equal(t, len(rules), 1)
+	assert.DeepEqual(t, rules[0].MatchResources.Kinds, []string{"Deployment"})
+
+	policy.SetAnnotations(map[string]string{engine.PodControllersAnnotation: "none"})
+	rules, err = GenerateRules(*policy, "", log.Log)
+	assert.NilError(t, err)
+	assert.Equal(t, len(rules), 0)
+
+	policy.SetAnnotations(map[string]string{engine.PodControllersAnnotation: "Deployment,CronJob=spec.foo"})
+	_, err = GenerateRules(*policy, "", log.Log)
+	assert.ErrorContains(t, err, "invalid annotation")
+}
+
+func Test_ExpandPolicy(t *testing.T) {
+	dir, err := os.Getwd()
+	baseDir := filepath.Dir(filepath.Dir(dir))
+	assert.NilError(t, err)
+	file, err := ioutil.ReadFile(baseDir + "/test/best_practices/disallow_bind_mounts.yaml")
+	assert.NilError(t, err)
+	policies, err := utils.GetPolicy(file)
+	assert.NilError(t, err)
+
+	policy := policies[0]
+	policy.SetAnnotations(map[string]string{engine.PodControllersAnnotation: "Deployment"})
+	expanded := ExpandPolicy(policy, "", log.Log)
+
+	// the spec of the policy is left as written
+	assert.Equal(t, len(policy.Spec.Rules), 1)
+	assert.Equal(t, len(expanded.Spec.Rules), 2)
+	assert.Equal(t, expanded.Spec.Rules[0].Name, "validate-hostPath")
+	assert.Equal(t, expanded.Spec.Rules[1].Name, "autogen-validate-hostPath")
+
+	// rules generated by previous releases are replaced instead of duplicated
+	stale := policy.DeepCopy()
+	stale.Spec.Rules = append(stale.Spec.Rules, kyverno.Rule{Name: "autogen-validate-hostPath"})
+	expanded = ExpandPolicy(stale, "", log.Log)
+	assert.Equal(t, len(expanded.Spec.Rules), 2)
+	assert.DeepEqual(t, expanded.Spec.Rules[1].MatchResources.Kinds, []string{"Deployment"})
+
+	policy.SetAnnotations(map[string]string{engine.PodControllersAnnotation: "none"})
+	assert.Equal(t, ExpandPolicy(policy, "", log.Log), policy)
+}
+
+func Test_Deny(t *testing.T) {
+	controllers := "DaemonSet,Deployment,Job,StatefulSet,CronJob,Foo=spec.podTemplate"
+	policyRaw := []byte(`{"apiVersion":"kyverno.io/v1","kind":"ClusterPolicy","metadata":{"name":"test"},"spec":{"rules":[{"name":"deny-host-network","match":{"resources":{"kinds":["Pod"]}},"validate":{"message":"{{request.object.metadata.name}} uses the host network","deny":{"conditions":[{"key":"{{request.object.spec.hostNetwork}}","operator":"Equals","value":true},{"key":"{{request.object.metadata.namespace}}","operator":"NotEquals","value":"{{request.object.metadata.labels.namespace}}"}]}}}]}}`)
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+
+	applyAutoGen, _ := CanAutoGen(&policy, log.Log)
+	assert.Assert(t, applyAutoGen)
+
+	// the name and the namespace of the controller are kept, they are rarely set on pod templates
+	rules, errs := generateRules(policy, controllers, log.Log)
+	assert.Equal(t, len(errs), 0)
+
+	expectedRules := [][]byte{
+		[]byte(`{"name":"autogen-deny-host-network","match":{"resources":{"kinds":["DaemonSet","Deployment","Job","StatefulSet"]}},"validate":{"message":"{{request.object.metadata.name}} uses the host network","deny":{"conditions":[{"key":"{{request.object.spec.template.spec.hostNetwork}}","operator":"Equals","value":true},{"key":"{{request.object.metadata.namespace}}","operator":"NotEquals","value":"{{request.object.spec.template.metadata.labels.namespace}}"}]}}}`),
+		[]byte(`{"name":"autogen-cronjob-deny-host-network","match":{"resources":{"kinds":["CronJob"]}},"validate":{"message":"{{request.object.metadata.name}} uses the host network","deny":{"conditions":[{"key":"{{request.object.spec.jobTemplate.spec.template.spec.hostNetwork}}","operator":"Equals","value":true},{"key":"{{request.object.metadata.namespace}}","operator":"NotEquals","value":"{{request.object.spec.jobTemplate.spec.template.metadata.labels.namespace}}"}]}}}`),
+		[]byte(`{"name":"autogen-foo-deny-host-network","match":{"resources":{"kinds":["Foo"]}},"validate":{"message":"{{request.object.metadata.name}} uses the host network","deny":{"conditions":[{"key":"{{request.object.spec.podTemplate.spec.hostNetwork}}","operator":"Equals","value":true},{"key":"{{request.object.metadata.namespace}}","operator":"NotEquals","value":"{{request.object.spec.podTemplate.metadata.labels.namespace}}"}]}}}`),
+	}
+
+	assert.DeepEqual(t, rules, expectedRules)
+}
+
+func Test_PatchesJSON6902(t *testing.T) {
+	controllers := "DaemonSet,Deployment,Job,StatefulSet,CronJob,Foo=spec.podTemplate"
+	policyRaw := []byte(`{"apiVersion":"kyverno.io/v1","kind":"ClusterPolicy","metadata":{"name":"test"},"spec":{"rules":[{"name":"add-env","match":{"resources":{"kinds":["Pod"]}},"mutate":{"patchesJson6902":"- op: add\n  path: /spec/containers/0/env/-\n  value:\n    name: POD_NAME\n    value: \"{{request.object.metadata.name}}\"\n- op: copy\n  from: /metadata/labels/app\n  path: /metadata/labels/name\n"}}]}}`)
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+
+	applyAutoGen, _ := CanAutoGen(&policy, log.Log)
+	assert.Assert(t, applyAutoGen)
+
+	rules, errs := generateRules(policy, controllers, log.Log)
+	assert.Equal(t, len(errs), 0)
+
+	expectedRules := [][]byte{
+		[]byte(`{"name":"autogen-add-env","match":{"resources":{"kinds":["DaemonSet","Deployment","Job","StatefulSet"]}},"mutate":{"patchesJson6902":"- op: add\n  path: /spec/template/spec/containers/0/env/-\n  value:\n    name: POD_NAME\n    value: '{{request.object.spec.template.metadata.name}}'\n- from: /spec/template/metadata/labels/app\n  op: copy\n  path: /spec/template/metadata/labels/name\n"}}`),
+		[]byte(`{"name":"autogen-cronjob-add-env","match":{"resources":{"kinds":["CronJob"]}},"mutate":{"patchesJson6902":"- op: add\n  path: /spec/jobTemplate/spec/template/spec/containers/0/env/-\n  value:\n    name: POD_NAME\n    value: '{{request.object.spec.jobTemplate.spec.template.metadata.name}}'\n- from: /spec/jobTemplate/spec/template/metadata/labels/app\n  op: copy\n  path: /spec/jobTemplate/spec/template/metadata/labels/name\n"}}`),
+		[]byte(`{"name":"autogen-foo-add-env","match":{"resources":{"kinds":["Foo"]}},"mutate":{"patchesJson6902":"- op: add\n  path: /spec/podTemplate/spec/containers/0/env/-\n  value:\n    name: POD_NAME\n    value: '{{request.object.spec.podTemplate.metadata.name}}'\n- from: /spec/podTemplate/metadata/labels/app\n  op: copy\n  path: /spec/podTemplate/metadata/labels/name\n"}}`),
+	}
+
+	assert.DeepEqual(t, rules, expectedRules)
+}
+
+func Test_Patches(t *testing.T) {
+	policyRaw := []byte(`{"apiVersion":"kyverno.io/v1","kind":"ClusterPolicy","metadata":{"name":"test"},"spec":{"rules":[{"name":"add-label","match":{"resources":{"kinds":["Pod"]}},"mutate":{"patches":[{"path":"/metadata/labels/team","op":"add","value":"kyverno"}]}}]}}`)
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+
+	rules, errs := generateRules(policy, engine.PodControllers, log.Log)
+	assert.Equal(t, len(errs), 0)
+
+	expectedRules := [][]byte{
+		[]byte(`{"name":"autogen-add-label","match":{"resources":{"kinds":["DaemonSet","Deployment","Job","StatefulSet"]}},"mutate":{"patches":[{"path":"/spec/template/metadata/labels/team","op":"add","value":"kyverno"}]}}`),
+		[]byte(`{"name":"autogen-cronjob-add-label","match":{"resources":{"kinds":["CronJob"]}},"mutate":{"patches":[{"path":"/spec/jobTemplate/spec/template/metadata/labels/team","op":"add","value":"kyverno"}]}}`),
+	}
+
+	assert.DeepEqual(t, rules, expectedRules)
+}
+
+func Test_PatchesJSON6902_OutsidePodTemplate(t *testing.T) {
+	policyRaw := []byte(`{"apiVersion":"kyverno.io/v1","kind":"ClusterPolicy","metadata":{"name":"test"},"spec":{"rules":[{"name":"set-status","match":{"resources":{"kinds":["Pod"]}},"mutate":{"patchesJson6902":"- op: add\n  path: /status/phase\n  value: Running\n"}}]}}`)
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+
+	rules, errs := generateRules(policy, engine.PodControllers, log.Log)
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, len(rules), 0)
 }
@@ -29,6 +29,9 @@ const (
 	// there would be a problem if use labels as the value could exceed 63 chars
 	deletedAnnotationResourceName string = "kyverno.io/delete.resource.name"
 	deletedAnnotationResourceKind string = "kyverno.io/delete.resource.kind"
+
+	// resultSource is the source of the results created by Kyverno
+	resultSource string = "kyverno"
 )
 
 func generatePolicyReportName(ns string) string {
@@ -133,6 +136,7 @@ func (builder *requestBuilder) build(info Info) (req *unstructured.Unstructured,
 
 func (builder *requestBuilder) buildRCRResult(policy string, resource response.ResourceSpec, rule kyverno.ViolatedRule) *report.PolicyReportResult {
 	av := builder.fetchAnnotationValues(policy, resource.Namespace)
+	now := metav1.Now()
 
 	result := &report.PolicyReportResult{
 		Policy: policy,
@@ -145,9 +149,13 @@ func (builder *requestBuilder) buildRCRResult(policy string, resource response.R
 				UID:        types.UID(resource.UID),
 			},
 		},
-		Scored:   av.scored,
-		Category: av.category,
-		Severity: av.severity,
+		Scored:             av.scored,
+		Category:           av.category,
+		Severity:           av.severity,
+		Source:             resultSource,
+		Properties:         rule.Properties,
+		Timestamp:          &now,
+		FirstSeenTimestamp: &now,
 	}
 
 	result.Rule = rule.Name
@@ -246,9 +254,10 @@ func buildViolatedRules(er *response.EngineResponse) []kyverno.ViolatedRule {
 	var violatedRules []kyverno.ViolatedRule
 	for _, rule := range er.PolicyResponse.Rules {
 		vrule := kyverno.ViolatedRule{
-			Name:    rule.Name,
-			Type:    rule.Type,
-			Message: rule.Message,
+			Name:       rule.Name,
+			Type:       rule.Type,
+			Message:    rule.Message,
+			Properties: rule.Properties,
 		}
 		vrule.Check = report.StatusFail
 		if rule.Success {
@@ -84,8 +84,11 @@ func updateResults(oldReport, newReport map[string]interface{}, aggregatedReques
 				continue
 			}
 			if key, ok := generateHashKey(resMap, deletedResource{}); ok {
-				if _, exist := oldResults.Get(key); exist {
+				if old, exist := oldResults.Get(key); exist {
 					hasDuplicate = exist
+					if oldMap, ok := old.(map[string]interface{}); ok {
+						preserveTimestamps(oldMap, resMap)
+					}
 				}
 
 				oldResults.Set(key, res)
@@ -105,6 +108,48 @@ func updateResults(oldReport, newReport map[string]interface{}, aggregatedReques
 	return newReport, hasDuplicate, nil
 }
 
+// preserveTimestamps keeps the timestamps of a result that is evaluated again, unless its status
+// changed or the resource was recreated. The timestamp of the evaluation is also updated when the
+// message or the rule of the result changed.
+func preserveTimestamps(oldResult, newResult map[string]interface{}) {
+	if oldResult["status"] != newResult["status"] {
+		return
+	}
+
+	// the UID is unknown when the resource is evaluated before it is created
+	oldUID, newUID := resourceUID(oldResult), resourceUID(newResult)
+	if oldUID != "" && newUID != "" && oldUID != newUID {
+		return
+	}
+
+	if firstSeen, ok := oldResult["firstSeenTimestamp"]; ok {
+		newResult["firstSeenTimestamp"] = firstSeen
+	}
+
+	if oldResult["message"] != newResult["message"] || oldResult["rule"] != newResult["rule"] {
+		return
+	}
+
+	if timestamp, ok := oldResult["timestamp"]; ok {
+		newResult["timestamp"] = timestamp
+	}
+}
+
+func resourceUID(result map[string]interface{}) string {
+	resources, ok := result["resources"].([]interface{})
+	if !ok || len(resources) == 0 {
+		return ""
+	}
+
+	resource, ok := resources[0].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	uid, _ := resource["uid"].(string)
+	return uid
+}
+
 func hashResults(policyReport map[string]interface{}, deleteResources []deletedResource) *hashmap.HashMap {
 	resultsHash := &hashmap.HashMap{}
 
@@ -114,21 +159,26 @@ func hashResults(policyReport map[string]interface{}, deleteResources []deletedR
 	}
 
 	for _, result := range results.([]interface{}) {
-		if len(deleteResources) != 0 {
-			for _, dr := range deleteResources {
-				if key, ok := generateHashKey(result.(map[string]interface{}), dr); ok {
-					resultsHash.Set(key, result)
-				}
-			}
-		} else {
-			if key, ok := generateHashKey(result.(map[string]interface{}), deletedResource{}); ok {
-				resultsHash.Set(key, result)
-			}
+		resMap := result.(map[string]interface{})
+		key, ok := generateHashKey(resMap, deletedResource{})
+		if !ok || isDeletedResult(resMap, deleteResources) {
+			continue
 		}
+		resultsHash.Set(key, result)
 	}
 	return resultsHash
 }
 
+// isDeletedResult checks if the result belongs to one of the deleted resources
+func isDeletedResult(result map[string]interface{}, deleteResources []deletedResource) bool {
+	for _, dr := range deleteResources {
+		if _, ok := generateHashKey(result, dr); !ok {
+			return true
+		}
+	}
+	return false
+}
+
 func getResultsFromHash(resHash *hashmap.HashMap) []interface{} {
 	results := make([]interface{}, 0)
 
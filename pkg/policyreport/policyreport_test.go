@@ -0,0 +1,89 @@
+package policyreport
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func newTimedResult(name, uid, status, timestamp string) map[string]interface{} {
+	return map[string]interface{}{
+		"policy":             "require-labels",
+		"rule":               "check-team",
+		"status":             status,
+		"source":             resultSource,
+		"timestamp":          timestamp,
+		"firstSeenTimestamp": timestamp,
+		"resources": []interface{}{
+			map[string]interface{}{"kind": "Pod", "namespace": "default", "name": name, "uid": uid},
+		},
+	}
+}
+
+func Test_UpdateResults_PreservesTimestamps(t *testing.T) {
+	oldReport := map[string]interface{}{
+		"results": []interface{}{
+			newTimedResult("pod-0", "uid-0", "fail", "2021-01-01T00:00:00Z"),
+			newTimedResult("pod-1", "uid-1", "fail", "2021-01-01T00:00:00Z"),
+			newTimedResult("pod-2", "uid-2", "fail", "2021-01-01T00:00:00Z"),
+			newTimedResult("pod-3", "uid-3", "fail", "2021-01-01T00:00:00Z"),
+			newTimedResult("pod-4", "uid-4", "fail", "2021-01-01T00:00:00Z"),
+		},
+	}
+
+	newReport := map[string]interface{}{
+		"results": []interface{}{
+			// evaluated again with the same status
+			newTimedResult("pod-0", "uid-0", "fail", "2021-01-02T00:00:00Z"),
+			// evaluated before it was created
+			newTimedResult("pod-1", "", "fail", "2021-01-02T00:00:00Z"),
+			// the status changed
+			newTimedResult("pod-2", "uid-2", "pass", "2021-01-02T00:00:00Z"),
+			// the resource was recreated
+			newTimedResult("pod-3", "uid-5", "fail", "2021-01-02T00:00:00Z"),
+			// the message changed
+			newTimedResult("pod-4", "uid-4", "fail", "2021-01-02T00:00:00Z"),
+		},
+	}
+	newReport["results"].([]interface{})[4].(map[string]interface{})["message"] = "label 'team' is required"
+
+	merged, _, err := updateResults(oldReport, newReport, nil)
+	assert.NilError(t, err)
+
+	firstSeen, timestamps := map[string]string{}, map[string]string{}
+	for _, result := range merged["results"].([]interface{}) {
+		resMap := result.(map[string]interface{})
+		resource := resMap["resources"].([]interface{})[0].(map[string]interface{})
+		firstSeen[resource["name"].(string)] = resMap["firstSeenTimestamp"].(string)
+		timestamps[resource["name"].(string)] = resMap["timestamp"].(string)
+	}
+
+	assert.DeepEqual(t, firstSeen, map[string]string{
+		"pod-0": "2021-01-01T00:00:00Z",
+		"pod-1": "2021-01-01T00:00:00Z",
+		"pod-2": "2021-01-02T00:00:00Z",
+		"pod-3": "2021-01-02T00:00:00Z",
+		"pod-4": "2021-01-01T00:00:00Z",
+	})
+
+	assert.DeepEqual(t, timestamps, map[string]string{
+		"pod-0": "2021-01-01T00:00:00Z",
+		"pod-1": "2021-01-01T00:00:00Z",
+		"pod-2": "2021-01-02T00:00:00Z",
+		"pod-3": "2021-01-02T00:00:00Z",
+		"pod-4": "2021-01-02T00:00:00Z",
+	})
+}
+
+func Test_HasResultsChanged_IgnoresTimestamp(t *testing.T) {
+	old := map[string]interface{}{
+		"results": []interface{}{newTimedResult("pod-0", "uid-0", "fail", "2021-01-01T00:00:00Z")},
+	}
+
+	reevaluated := newTimedResult("pod-0", "uid-0", "fail", "2021-01-01T00:00:00Z")
+	reevaluated["timestamp"] = "2021-01-02T00:00:00Z"
+	assert.Assert(t, !hasResultsChanged(old, map[string]interface{}{"results": []interface{}{reevaluated}}))
+
+	changed := newTimedResult("pod-0", "uid-0", "pass", "2021-01-02T00:00:00Z")
+	assert.Assert(t, hasResultsChanged(old, map[string]interface{}{"results": []interface{}{changed}}))
+}
@@ -0,0 +1,279 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+)
+
+// DefaultLimit is the number of results returned when the query does not set a limit
+const DefaultLimit = 100
+
+// Query filters the results of the policy reports, a result matches the query when it matches one of
+// the values of every filter that is set. Results are sorted by report and returned by pages of Limit results.
+type Query struct {
+	Policies   []string
+	Rules      []string
+	Statuses   []string
+	Severities []string
+	Categories []string
+	Namespaces []string
+	Kinds      []string
+
+	// Limit is the maximum number of results returned, 0 returns all the results
+	Limit int
+
+	// Offset is the number of matching results skipped
+	Offset int
+}
+
+// Result is a policy report result along with the report it belongs to
+type Result struct {
+	// Report is the name of the report containing the result
+	Report string `json:"report"`
+
+	// Namespace is the namespace of the report, it is empty for cluster reports
+	Namespace string `json:"namespace,omitempty"`
+
+	report.PolicyReportResult `json:",inline"`
+}
+
+// Response holds a page of the results matching a query, and the counts of all matching results
+type Response struct {
+	// Results is the requested page of the matching results
+	Results []Result `json:"results"`
+
+	// Total is the number of matching results
+	Total int `json:"total"`
+
+	// Offset is the index of the first returned result
+	Offset int `json:"offset"`
+
+	// Next is the offset of the next page, it is 0 when there are no more results
+	Next int `json:"next,omitempty"`
+
+	// Summary counts the matching results by status
+	Summary report.PolicyReportSummary `json:"summary"`
+
+	// Policies counts the matching results by policy and status
+	Policies map[string]report.PolicyReportSummary `json:"policies"`
+}
+
+// ParseQuery builds a query from URL parameters, the filters accept repeated and comma-separated values
+func ParseQuery(values url.Values) (Query, error) {
+	q := Query{
+		Policies:   splitValues(values["policy"]),
+		Rules:      splitValues(values["rule"]),
+		Statuses:   splitValues(values["status"]),
+		Severities: splitValues(values["severity"]),
+		Categories: splitValues(values["category"]),
+		Namespaces: splitValues(values["namespace"]),
+		Kinds:      splitValues(values["kind"]),
+		Limit:      DefaultLimit,
+	}
+
+	var err error
+	if limit := values.Get("limit"); limit != "" {
+		if q.Limit, err = strconv.Atoi(limit); err != nil || q.Limit < 0 {
+			return q, fmt.Errorf("invalid limit %q", limit)
+		}
+	}
+
+	if offset := values.Get("offset"); offset != "" {
+		if q.Offset, err = strconv.Atoi(offset); err != nil || q.Offset < 0 {
+			return q, fmt.Errorf("invalid offset %q", offset)
+		}
+	}
+
+	return q, nil
+}
+
+// Values encodes the query as URL parameters
+func (q Query) Values() url.Values {
+	values := url.Values{}
+	setValues(values, "policy", q.Policies)
+	setValues(values, "rule", q.Rules)
+	setValues(values, "status", q.Statuses)
+	setValues(values, "severity", q.Severities)
+	setValues(values, "category", q.Categories)
+	setValues(values, "namespace", q.Namespaces)
+	setValues(values, "kind", q.Kinds)
+	values.Set("limit", strconv.Itoa(q.Limit))
+	if q.Offset > 0 {
+		values.Set("offset", strconv.Itoa(q.Offset))
+	}
+
+	return values
+}
+
+// Run returns the results of the reports that match the query
+func Run(q Query, polrs []*report.PolicyReport, cpolrs []*report.ClusterPolicyReport) Response {
+	var results []Result
+	for _, cpolr := range sortClusterReports(cpolrs) {
+		results = appendMatches(results, q, cpolr.GetName(), "", cpolr.Results)
+	}
+
+	for _, polr := range sortReports(polrs) {
+		results = appendMatches(results, q, polr.GetName(), polr.GetNamespace(), polr.Results)
+	}
+
+	resp := Response{
+		Results:  []Result{},
+		Total:    len(results),
+		Offset:   q.Offset,
+		Policies: map[string]report.PolicyReportSummary{},
+	}
+
+	for _, result := range results {
+		countStatus(&resp.Summary, result.Status)
+		summary := resp.Policies[result.Policy]
+		countStatus(&summary, result.Status)
+		resp.Policies[result.Policy] = summary
+	}
+
+	if q.Offset >= len(results) {
+		return resp
+	}
+
+	end := len(results)
+	if q.Limit > 0 && q.Offset+q.Limit < end {
+		end = q.Offset + q.Limit
+		resp.Next = end
+	}
+
+	resp.Results = results[q.Offset:end]
+	return resp
+}
+
+// Matches checks if the result of the report in the given namespace matches the filters of the query
+func (q Query) Matches(namespace string, result *report.PolicyReportResult) bool {
+	if !matchValue(q.Policies, result.Policy) ||
+		!matchValue(q.Rules, result.Rule) ||
+		!matchValue(q.Statuses, string(result.Status)) ||
+		!matchValue(q.Severities, string(result.Severity)) ||
+		!matchValue(q.Categories, result.Category) {
+		return false
+	}
+
+	if len(q.Namespaces) > 0 && !matchValue(q.Namespaces, namespace) {
+		matched := false
+		for _, resource := range result.Resources {
+			if resource != nil && matchValue(q.Namespaces, resource.Namespace) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	if len(q.Kinds) > 0 {
+		for _, resource := range result.Resources {
+			if resource != nil && matchKind(q.Kinds, resource.Kind) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+func appendMatches(matches []Result, q Query, name, namespace string, results []*report.PolicyReportResult) []Result {
+	for _, result := range results {
+		if result == nil || !q.Matches(namespace, result) {
+			continue
+		}
+
+		matches = append(matches, Result{Report: name, Namespace: namespace, PolicyReportResult: *result})
+	}
+
+	return matches
+}
+
+func sortReports(polrs []*report.PolicyReport) []*report.PolicyReport {
+	sorted := append([]*report.PolicyReport{}, polrs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].GetNamespace() != sorted[j].GetNamespace() {
+			return sorted[i].GetNamespace() < sorted[j].GetNamespace()
+		}
+		return sorted[i].GetName() < sorted[j].GetName()
+	})
+
+	return sorted
+}
+
+func sortClusterReports(cpolrs []*report.ClusterPolicyReport) []*report.ClusterPolicyReport {
+	sorted := append([]*report.ClusterPolicyReport{}, cpolrs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GetName() < sorted[j].GetName()
+	})
+
+	return sorted
+}
+
+func countStatus(summary *report.PolicyReportSummary, status report.PolicyStatus) {
+	switch status {
+	case report.StatusPass:
+		summary.Pass++
+	case report.StatusFail:
+		summary.Fail++
+	case report.StatusWarn:
+		summary.Warn++
+	case report.StatusError:
+		summary.Error++
+	case report.StatusSkip:
+		summary.Skip++
+	}
+}
+
+func matchValue(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchKind compares kinds case-insensitively, so that "pod" matches "Pod"
+func matchKind(kinds []string, kind string) bool {
+	for _, k := range kinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func splitValues(values []string) []string {
+	var split []string
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				split = append(split, v)
+			}
+		}
+	}
+
+	return split
+}
+
+func setValues(values url.Values, key string, list []string) {
+	if len(list) > 0 {
+		values.Set(key, strings.Join(list, ","))
+	}
+}
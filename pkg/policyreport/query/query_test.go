@@ -0,0 +1,214 @@
+package query
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	policyreportlister "github.com/kyverno/kyverno/pkg/client/listers/policyreport/v1alpha1"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func newResult(policy, rule, status, severity, kind, namespace, name string) *report.PolicyReportResult {
+	return &report.PolicyReportResult{
+		Policy:   policy,
+		Rule:     rule,
+		Status:   report.PolicyStatus(status),
+		Severity: report.PolicySeverity(severity),
+		Category: "Pod Security",
+		Resources: []*corev1.ObjectReference{
+			{Kind: kind, Namespace: namespace, Name: name},
+		},
+	}
+}
+
+func newReports() ([]*report.PolicyReport, []*report.ClusterPolicyReport) {
+	polrs := []*report.PolicyReport{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "polr-ns-test", Namespace: "test"},
+			Results: []*report.PolicyReportResult{
+				newResult("require-labels", "check-team", "fail", "medium", "Pod", "test", "pod-1"),
+				newResult("disallow-latest", "check-tag", "pass", "high", "Deployment", "test", "deploy-1"),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "polr-ns-default", Namespace: "default"},
+			Results: []*report.PolicyReportResult{
+				newResult("require-labels", "check-team", "fail", "medium", "Pod", "default", "pod-0"),
+				newResult("require-labels", "check-team", "pass", "medium", "Pod", "default", "pod-1"),
+				newResult("disallow-latest", "check-tag", "error", "high", "Pod", "default", "pod-0"),
+			},
+		},
+	}
+
+	cpolrs := []*report.ClusterPolicyReport{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "clusterpolicyreport"},
+			Results: []*report.PolicyReportResult{
+				newResult("require-labels", "check-team", "fail", "medium", "Namespace", "", "test"),
+			},
+		},
+	}
+
+	return polrs, cpolrs
+}
+
+func resultNames(resp Response) []string {
+	var names []string
+	for _, result := range resp.Results {
+		names = append(names, result.Namespace+"/"+result.Resources[0].Name+"/"+result.Policy)
+	}
+	return names
+}
+
+func Test_Run_Filters(t *testing.T) {
+	polrs, cpolrs := newReports()
+
+	testCases := []struct {
+		name     string
+		query    Query
+		expected []string
+	}{
+		{
+			name:  "no filter",
+			query: Query{},
+			expected: []string{
+				"/test/require-labels",
+				"default/pod-0/require-labels",
+				"default/pod-1/require-labels",
+				"default/pod-0/disallow-latest",
+				"test/pod-1/require-labels",
+				"test/deploy-1/disallow-latest",
+			},
+		},
+		{
+			name:     "status and policy",
+			query:    Query{Policies: []string{"require-labels"}, Statuses: []string{"fail"}},
+			expected: []string{"/test/require-labels", "default/pod-0/require-labels", "test/pod-1/require-labels"},
+		},
+		{
+			name:     "namespace",
+			query:    Query{Namespaces: []string{"test"}},
+			expected: []string{"test/pod-1/require-labels", "test/deploy-1/disallow-latest"},
+		},
+		{
+			name:     "kind is case insensitive",
+			query:    Query{Kinds: []string{"deployment", "namespace"}},
+			expected: []string{"/test/require-labels", "test/deploy-1/disallow-latest"},
+		},
+		{
+			name:     "severity and rule",
+			query:    Query{Severities: []string{"high"}, Rules: []string{"check-tag"}, Statuses: []string{"error", "fail"}},
+			expected: []string{"default/pod-0/disallow-latest"},
+		},
+		{
+			name:     "category",
+			query:    Query{Categories: []string{"Best Practices"}},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := Run(tc.query, polrs, cpolrs)
+			assert.DeepEqual(t, resultNames(resp), tc.expected)
+			assert.Equal(t, resp.Total, len(tc.expected))
+		})
+	}
+}
+
+func Test_Run_Pagination(t *testing.T) {
+	polrs, cpolrs := newReports()
+
+	resp := Run(Query{Limit: 4}, polrs, cpolrs)
+	assert.Equal(t, len(resp.Results), 4)
+	assert.Equal(t, resp.Total, 6)
+	assert.Equal(t, resp.Next, 4)
+	assert.DeepEqual(t, resp.Summary, report.PolicyReportSummary{Pass: 2, Fail: 3, Error: 1})
+	assert.DeepEqual(t, resp.Policies, map[string]report.PolicyReportSummary{
+		"require-labels":  {Pass: 1, Fail: 3},
+		"disallow-latest": {Pass: 1, Error: 1},
+	})
+
+	resp = Run(Query{Limit: 4, Offset: resp.Next}, polrs, cpolrs)
+	assert.DeepEqual(t, resultNames(resp), []string{"test/pod-1/require-labels", "test/deploy-1/disallow-latest"})
+	assert.Equal(t, resp.Next, 0)
+
+	resp = Run(Query{Limit: 4, Offset: 10}, polrs, cpolrs)
+	assert.Equal(t, len(resp.Results), 0)
+	assert.Equal(t, resp.Total, 6)
+}
+
+func Test_ParseQuery(t *testing.T) {
+	values, err := url.ParseQuery("policy=require-labels,disallow-latest&status=fail&status=error&kind=Pod&limit=10&offset=20")
+	assert.NilError(t, err)
+
+	q, err := ParseQuery(values)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, q, Query{
+		Policies: []string{"require-labels", "disallow-latest"},
+		Statuses: []string{"fail", "error"},
+		Kinds:    []string{"Pod"},
+		Limit:    10,
+		Offset:   20,
+	})
+
+	parsed, err := ParseQuery(q.Values())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, parsed, q)
+
+	q, err = ParseQuery(url.Values{})
+	assert.NilError(t, err)
+	assert.Equal(t, q.Limit, DefaultLimit)
+
+	_, err = ParseQuery(url.Values{"limit": []string{"-1"}})
+	assert.ErrorContains(t, err, "invalid limit")
+
+	_, err = ParseQuery(url.Values{"offset": []string{"first"}})
+	assert.ErrorContains(t, err, "invalid offset")
+}
+
+func Test_Handler(t *testing.T) {
+	polrs, cpolrs := newReports()
+
+	polrIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, polr := range polrs {
+		assert.NilError(t, polrIndexer.Add(polr))
+	}
+
+	cpolrIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, cpolr := range cpolrs {
+		assert.NilError(t, cpolrIndexer.Add(cpolr))
+	}
+
+	handler := NewHandler(policyreportlister.NewPolicyReportLister(polrIndexer), policyreportlister.NewClusterPolicyReportLister(cpolrIndexer), log.Log)
+	server := httptest.NewServer(handler.ServeMux())
+	defer server.Close()
+
+	httpResp, err := http.Get(server.URL + ResultsPath + "?namespace=default&status=fail,error")
+	assert.NilError(t, err)
+	defer httpResp.Body.Close()
+	assert.Equal(t, httpResp.StatusCode, http.StatusOK)
+
+	var resp Response
+	assert.NilError(t, json.NewDecoder(httpResp.Body).Decode(&resp))
+	assert.DeepEqual(t, resultNames(resp), []string{"default/pod-0/require-labels", "default/pod-0/disallow-latest"})
+	assert.Equal(t, resp.Results[0].Report, "polr-ns-default")
+
+	httpResp, err = http.Get(server.URL + ResultsPath + "?limit=all")
+	assert.NilError(t, err)
+	httpResp.Body.Close()
+	assert.Equal(t, httpResp.StatusCode, http.StatusBadRequest)
+
+	httpResp, err = http.Post(server.URL+ResultsPath, "application/json", nil)
+	assert.NilError(t, err)
+	httpResp.Body.Close()
+	assert.Equal(t, httpResp.StatusCode, http.StatusMethodNotAllowed)
+}
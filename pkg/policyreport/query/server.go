@@ -0,0 +1,96 @@
+package query
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	policyreportlister "github.com/kyverno/kyverno/pkg/client/listers/policyreport/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ResultsPath is the path at which the results of the policy reports are served
+const ResultsPath = "/api/v1/results"
+
+// Handler serves the results of the policy reports from the informer caches, it is read-only.
+// The requests are not authenticated and the results of all namespaces are served, regardless
+// of the RBAC on the reports, so the handler must only be served on the loopback interface.
+type Handler struct {
+	reportLister        policyreportlister.PolicyReportLister
+	clusterReportLister policyreportlister.ClusterPolicyReportLister
+	log                 logr.Logger
+}
+
+// NewHandler returns a handler serving the results of the policy reports
+func NewHandler(reportLister policyreportlister.PolicyReportLister, clusterReportLister policyreportlister.ClusterPolicyReportLister, log logr.Logger) *Handler {
+	return &Handler{
+		reportLister:        reportLister,
+		clusterReportLister: clusterReportLister,
+		log:                 log,
+	}
+}
+
+// ServeMux returns a mux serving the results at ResultsPath
+func (h *Handler) ServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle(ResultsPath, h)
+	return mux
+}
+
+// ServeHTTP handles a query passed as URL parameters, see ParseQuery
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q, err := ParseQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	polrs, cpolrs, err := h.list(q)
+	if err != nil {
+		h.log.Error(err, "failed to list policy reports")
+		http.Error(w, "failed to list policy reports", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(Run(q, polrs, cpolrs)); err != nil {
+		h.log.Error(err, "failed to write query response")
+	}
+}
+
+// list returns the reports that may hold matching results, cluster reports are skipped
+// when the query filters namespaces as their resources are not namespaced
+func (h *Handler) list(q Query) ([]*report.PolicyReport, []*report.ClusterPolicyReport, error) {
+	var polrs []*report.PolicyReport
+	if len(q.Namespaces) == 0 {
+		all, err := h.reportLister.List(labels.Everything())
+		if err != nil {
+			return nil, nil, err
+		}
+		polrs = all
+	} else {
+		for _, ns := range q.Namespaces {
+			nsPolrs, err := h.reportLister.PolicyReports(ns).List(labels.Everything())
+			if err != nil {
+				return nil, nil, err
+			}
+			polrs = append(polrs, nsPolrs...)
+		}
+
+		return polrs, nil, nil
+	}
+
+	cpolrs, err := h.clusterReportLister.List(labels.Everything())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return polrs, cpolrs, nil
+}
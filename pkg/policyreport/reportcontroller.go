@@ -63,6 +63,9 @@ type ReportGenerator struct {
 	nsLister       listerv1.NamespaceLister
 	nsListerSynced cache.InformerSynced
 
+	// configHandler provides the report limits, it is set by Run
+	configHandler config.Interface
+
 	queue workqueue.RateLimitingInterface
 
 	// ReconcileCh sends a signal to policy controller to force the reconciliation of policy report
@@ -175,20 +178,28 @@ func (g *ReportGenerator) updateClusterReportChangeRequest(old interface{}, cur
 	g.queue.Add("")
 }
 
+// deletePolicyReport triggers the reconciliation of the reports when the first shard of a policyReport is deleted,
+// the additional shards are deleted by the controller when the number of results decreases
 func (g *ReportGenerator) deletePolicyReport(obj interface{}) {
 	report := obj.(*report.PolicyReport)
 	g.log.V(2).Info("PolicyReport deleted", "name", report.GetName())
-	g.ReconcileCh <- false
+	if isFirstShard(report) {
+		g.ReconcileCh <- false
+	}
 }
 
 func (g *ReportGenerator) deleteClusterPolicyReport(obj interface{}) {
 	g.log.V(2).Info("ClusterPolicyReport deleted")
+	if cpolr, ok := obj.(*report.ClusterPolicyReport); ok && !isFirstShard(cpolr) {
+		return
+	}
 	g.ReconcileCh <- false
 }
 
 // Run starts the workers
-func (g *ReportGenerator) Run(workers int, stopCh <-chan struct{}) {
+func (g *ReportGenerator) Run(workers int, configHandler config.Interface, stopCh <-chan struct{}) {
 	logger := g.log
+	g.configHandler = configHandler
 	defer utilruntime.HandleCrash()
 	defer g.queue.ShutDown()
 
@@ -275,8 +286,8 @@ func (g *ReportGenerator) handleErr(err error, key interface{}, aggregatedReques
 	}
 }
 
-// syncHandler reconciles clusterPolicyReport if namespace == ""
-// otherwise it updates policyReport
+// syncHandler reconciles the clusterPolicyReport shards if namespace == ""
+// otherwise it reconciles the policyReport shards of the namespace
 func (g *ReportGenerator) syncHandler(key string) (aggregatedRequests interface{}, err error) {
 	g.log.V(4).Info("syncing policy report", "key", key)
 
@@ -290,80 +301,37 @@ func (g *ReportGenerator) syncHandler(key string) (aggregatedRequests interface{
 		return aggregatedRequests, fmt.Errorf("failed to aggregate reportChangeRequest results %v", err)
 	}
 
-	var old interface{}
-	if old, err = g.createReportIfNotPresent(namespace, new, aggregatedRequests); err != nil {
-		return aggregatedRequests, err
-	}
-	if old == nil {
-		g.cleanupReportRequests(aggregatedRequests)
-		return nil, nil
-	}
-
-	if err := g.updateReport(old, new, aggregatedRequests); err != nil {
-		return aggregatedRequests, err
-	}
-
-	g.cleanupReportRequests(aggregatedRequests)
-	return nil, nil
-}
-
-// createReportIfNotPresent creates cluster / policyReport if not present
-// return the existing report if exist
-func (g *ReportGenerator) createReportIfNotPresent(namespace string, new *unstructured.Unstructured, aggregatedRequests interface{}) (report interface{}, err error) {
-	log := g.log.WithName("createReportIfNotPresent")
-	obj, hasDuplicate, err := updateResults(new.UnstructuredContent(), new.UnstructuredContent(), nil)
-	if hasDuplicate && err != nil {
-		g.log.Error(err, "failed to remove duplicate results", "policy report", new.GetName())
-	} else {
-		new.Object = obj
-	}
-
 	if namespace != "" {
-		ns, err := g.nsLister.Get(namespace)
+		active, err := g.isNamespaceActive(namespace)
 		if err != nil {
-			if apierrors.IsNotFound(err) {
-				return nil, nil
-			}
-			return nil, fmt.Errorf("failed to fetch namespace: %v", err)
+			return aggregatedRequests, err
 		}
 
-		if ns.GetDeletionTimestamp() != nil {
+		if !active {
+			g.cleanupReportRequests(aggregatedRequests)
 			return nil, nil
 		}
+	}
 
-		report, err = g.reportLister.PolicyReports(namespace).Get(generatePolicyReportName((namespace)))
-		if err != nil {
-			if apierrors.IsNotFound(err) && new != nil {
-				if _, err := g.dclient.CreateResource(new.GetAPIVersion(), new.GetKind(), new.GetNamespace(), new, false); err != nil {
-					return nil, fmt.Errorf("failed to create policyReport: %v", err)
-				}
+	if err := g.updateReportShards(new, aggregatedRequests); err != nil {
+		return aggregatedRequests, err
+	}
 
-				log.V(2).Info("successfully created policyReport", "namespace", new.GetNamespace(), "name", new.GetName())
-				g.cleanupReportRequests(aggregatedRequests)
-				return nil, nil
-			}
+	g.cleanupReportRequests(aggregatedRequests)
+	return nil, nil
+}
 
-			return nil, fmt.Errorf("unable to get policyReport: %v", err)
-		}
-	} else {
-		report, err = g.clusterReportLister.Get(generatePolicyReportName((namespace)))
-		if err != nil {
-			if apierrors.IsNotFound(err) {
-				if new != nil {
-					if _, err := g.dclient.CreateResource(new.GetAPIVersion(), new.GetKind(), new.GetNamespace(), new, false); err != nil {
-						return nil, fmt.Errorf("failed to create ClusterPolicyReport: %v", err)
-					}
-
-					log.V(2).Info("successfully created ClusterPolicyReport")
-					g.cleanupReportRequests(aggregatedRequests)
-					return nil, nil
-				}
-				return nil, nil
-			}
-			return nil, fmt.Errorf("unable to get ClusterPolicyReport: %v", err)
+// isNamespaceActive checks if the namespace exists and is not being deleted
+func (g *ReportGenerator) isNamespaceActive(namespace string) (bool, error) {
+	ns, err := g.nsLister.Get(namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
 		}
+		return false, fmt.Errorf("failed to fetch namespace: %v", err)
 	}
-	return report, nil
+
+	return ns.GetDeletionTimestamp() == nil, nil
 }
 
 func (g *ReportGenerator) removePolicyEntryFromReport(policyName, ruleName string) (aggregatedRequests interface{}, err error) {
@@ -397,16 +365,13 @@ func (g *ReportGenerator) removeFromClusterPolicyReport(policyName, ruleName str
 		return fmt.Errorf("failed to list clusterPolicyReport %v", err)
 	}
 
+	updated := false
 	for _, cpolr := range cpolrs {
-		newRes := []*report.PolicyReportResult{}
-		for _, result := range cpolr.Results {
-			if ruleName != "" && result.Rule == ruleName && result.Policy == policyName {
-				continue
-			} else if ruleName == "" && result.Policy == policyName {
-				continue
-			}
-			newRes = append(newRes, result)
+		newRes, removed := removePolicyResults(cpolr.Results, policyName, ruleName)
+		if !removed {
+			continue
 		}
+
 		cpolr.Results = newRes
 		cpolr.Summary = calculateSummary(newRes)
 		gv := report.SchemeGroupVersion
@@ -414,34 +379,27 @@ func (g *ReportGenerator) removeFromClusterPolicyReport(policyName, ruleName str
 		if _, err := g.dclient.UpdateResource("", "ClusterPolicyReport", "", cpolr, false); err != nil {
 			return fmt.Errorf("failed to update clusterPolicyReport %s %v", cpolr.Name, err)
 		}
+		updated = true
+	}
+
+	// rebalance the results of the shards
+	if updated {
+		g.queue.Add("")
 	}
 	return nil
 }
 
 func (g *ReportGenerator) removeFromPolicyReport(policyName, ruleName string) error {
-	namespaces, err := g.dclient.ListResource("", "Namespace", "", nil)
+	policyReports, err := g.reportLister.List(labels.Everything())
 	if err != nil {
-		return fmt.Errorf("unable to list namespace %v", err)
-	}
-
-	policyReports := []*report.PolicyReport{}
-	for _, ns := range namespaces.Items {
-		reports, err := g.reportLister.PolicyReports(ns.GetName()).List(labels.Everything())
-		if err != nil {
-			return fmt.Errorf("unable to list policyReport for namespace %s %v", ns.GetName(), err)
-		}
-		policyReports = append(policyReports, reports...)
+		return fmt.Errorf("unable to list policyReport %v", err)
 	}
 
+	namespaces := map[string]bool{}
 	for _, r := range policyReports {
-		newRes := []*report.PolicyReportResult{}
-		for _, result := range r.Results {
-			if ruleName != "" && result.Rule == ruleName && result.Policy == policyName {
-				continue
-			} else if ruleName == "" && result.Policy == policyName {
-				continue
-			}
-			newRes = append(newRes, result)
+		newRes, removed := removePolicyResults(r.Results, policyName, ruleName)
+		if !removed {
+			continue
 		}
 
 		r.Results = newRes
@@ -452,10 +410,32 @@ func (g *ReportGenerator) removeFromPolicyReport(policyName, ruleName string) er
 		if _, err := g.dclient.UpdateResource("", "PolicyReport", r.GetNamespace(), r, false); err != nil {
 			return fmt.Errorf("failed to update PolicyReport %s %v", r.GetName(), err)
 		}
+		namespaces[r.GetNamespace()] = true
+	}
+
+	// rebalance the results of the shards
+	for ns := range namespaces {
+		g.queue.Add(ns)
 	}
 	return nil
 }
 
+// removePolicyResults removes the results of the policy, or of the rule if ruleName is set,
+// it returns false when there is nothing to remove
+func removePolicyResults(results []*report.PolicyReportResult, policyName, ruleName string) ([]*report.PolicyReportResult, bool) {
+	newRes := []*report.PolicyReportResult{}
+	for _, result := range results {
+		if ruleName != "" && result.Rule == ruleName && result.Policy == policyName {
+			continue
+		} else if ruleName == "" && result.Policy == policyName {
+			continue
+		}
+		newRes = append(newRes, result)
+	}
+
+	return newRes, len(newRes) != len(results)
+}
+
 // aggregateReports aggregates cluster / report change requests to a policy report
 func (g *ReportGenerator) aggregateReports(namespace string) (
 	report *unstructured.Unstructured, aggregatedRequests interface{}, err error) {
@@ -585,56 +565,6 @@ func setReport(reportUnstructured *unstructured.Unstructured, ns *v1.Namespace)
 	})
 }
 
-func (g *ReportGenerator) updateReport(old interface{}, new *unstructured.Unstructured, aggregatedRequests interface{}) (err error) {
-	if new == nil {
-		g.log.V(4).Info("empty report to update")
-		return nil
-	}
-
-	oldUnstructured := make(map[string]interface{})
-
-	if oldTyped, ok := old.(*report.ClusterPolicyReport); ok {
-		if oldTyped.GetDeletionTimestamp() != nil {
-			return g.dclient.DeleteResource(oldTyped.APIVersion, "ClusterPolicyReport", oldTyped.Namespace, oldTyped.Name, false)
-		}
-
-		if oldUnstructured, err = runtime.DefaultUnstructuredConverter.ToUnstructured(oldTyped); err != nil {
-			return fmt.Errorf("unable to convert clusterPolicyReport: %v", err)
-		}
-		new.SetUID(oldTyped.GetUID())
-		new.SetResourceVersion(oldTyped.GetResourceVersion())
-	} else if oldTyped, ok := old.(*report.PolicyReport); ok {
-		if oldTyped.GetDeletionTimestamp() != nil {
-			return g.dclient.DeleteResource(oldTyped.APIVersion, "PolicyReport", oldTyped.Namespace, oldTyped.Name, false)
-		}
-
-		if oldUnstructured, err = runtime.DefaultUnstructuredConverter.ToUnstructured(oldTyped); err != nil {
-			return fmt.Errorf("unable to convert policyReport: %v", err)
-		}
-
-		new.SetUID(oldTyped.GetUID())
-		new.SetResourceVersion(oldTyped.GetResourceVersion())
-	}
-
-	obj, _, err := updateResults(oldUnstructured, new.UnstructuredContent(), aggregatedRequests)
-	if err != nil {
-		return fmt.Errorf("failed to update results entry: %v", err)
-	}
-	new.Object = obj
-
-	if !hasResultsChanged(oldUnstructured, new.UnstructuredContent()) {
-		g.log.V(4).Info("unchanged policy report", "kind", new.GetKind(), "namespace", new.GetNamespace(), "name", new.GetName())
-		return nil
-	}
-
-	if _, err = g.dclient.UpdateResource(new.GetAPIVersion(), new.GetKind(), new.GetNamespace(), new, false); err != nil {
-		return fmt.Errorf("failed to update policy report: %v", err)
-	}
-
-	g.log.V(3).Info("successfully updated policy report", "kind", new.GetKind(), "namespace", new.GetNamespace(), "name", new.GetName())
-	return
-}
-
 func (g *ReportGenerator) cleanupReportRequests(requestsGeneral interface{}) {
 	defer g.log.V(5).Info("successfully cleaned up report requests")
 	if requests, ok := requestsGeneral.([]*changerequest.ReportChangeRequest); ok {
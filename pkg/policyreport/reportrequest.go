@@ -288,5 +288,29 @@ func hasResultsChanged(old, new map[string]interface{}) bool {
 		return true
 	}
 
-	return !reflect.DeepEqual(oldRes, newRes)
+	for i := range oldRes {
+		if !reflect.DeepEqual(withoutTimestamp(oldRes[i]), withoutTimestamp(newRes[i])) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withoutTimestamp returns a copy of the result without the timestamp of its last evaluation,
+// the timestamp alone does not require to update the report
+func withoutTimestamp(result interface{}) interface{} {
+	resMap, ok := result.(map[string]interface{})
+	if !ok {
+		return result
+	}
+
+	copied := make(map[string]interface{}, len(resMap))
+	for k, v := range resMap {
+		if k != "timestamp" {
+			copied[k] = v
+		}
+	}
+
+	return copied
 }
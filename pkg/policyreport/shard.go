@@ -0,0 +1,332 @@
+package policyreport
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// the following labels link the shards of a polr / cpolr,
+	// reportLabelName is the name of the first shard and reportLabelShard the index of the shard
+	reportLabelName  string = "kyverno.io/report.name"
+	reportLabelShard string = "kyverno.io/report.shard"
+)
+
+// shardName returns the name of a shard, the first shard keeps the name of the report
+func shardName(name string, index int) string {
+	if index == 0 {
+		return name
+	}
+
+	return fmt.Sprintf("%s-%d", name, index)
+}
+
+// isReportShard checks if the report is a shard of the report with the given name,
+// reports created before sharding was introduced have no labels and are the first shard
+func isReportShard(obj metav1.Object, name string) bool {
+	return obj.GetName() == name || obj.GetLabels()[reportLabelName] == name
+}
+
+// isFirstShard checks if the report is not an additional shard created when the results exceed the limit
+func isFirstShard(obj metav1.Object) bool {
+	index, ok := obj.GetLabels()[reportLabelShard]
+	return !ok || index == "0"
+}
+
+// shardResult is a result with its hash key and policy
+type shardResult struct {
+	key    string
+	policy string
+	result interface{}
+}
+
+// shardResults spreads the results over shards of at most maxResults results, the results are not split
+// when maxResults is not positive. A result stays in the shard it is assigned to, the index of the shard of
+// each result hash key is given by assigned, so that adding or removing results only updates the shards of
+// these results. New results are added to the last shard holding results of the same policy when it has room,
+// otherwise to the first shard with room or to a new shard. The results of a shard are sorted by policy,
+// rule and resource, there is always a first shard and the other shards left without results are nil or omitted.
+// All the results are kept in the first shard when they do not exceed maxResults.
+func shardResults(results []interface{}, assigned map[string]int, maxResults int) [][]interface{} {
+	sorted := make([]shardResult, len(results))
+	for i, result := range results {
+		sorted[i].result = result
+		if resMap, ok := result.(map[string]interface{}); ok {
+			sorted[i].key, _ = generateHashKey(resMap, deletedResource{})
+			sorted[i].policy, _ = resMap["policy"].(string)
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].key < sorted[j].key
+	})
+
+	if maxResults <= 0 || len(sorted) <= maxResults {
+		return [][]interface{}{shardResultValues(sorted)}
+	}
+
+	shards := make([][]shardResult, 1)
+	var unassigned []shardResult
+	for _, result := range sorted {
+		index, ok := assigned[result.key]
+		if !ok || index < 0 || index > len(results) {
+			unassigned = append(unassigned, result)
+			continue
+		}
+
+		for len(shards) <= index {
+			shards = append(shards, nil)
+		}
+
+		if len(shards[index]) >= maxResults {
+			unassigned = append(unassigned, result)
+			continue
+		}
+
+		shards[index] = append(shards[index], result)
+	}
+
+	policyShards := make(map[string]int)
+	for index, shard := range shards {
+		for _, result := range shard {
+			policyShards[result.policy] = index
+		}
+	}
+
+	for _, result := range unassigned {
+		index, ok := policyShards[result.policy]
+		if !ok || len(shards[index]) >= maxResults {
+			index = 0
+			for index < len(shards) && len(shards[index]) >= maxResults {
+				index++
+			}
+
+			if index == len(shards) {
+				shards = append(shards, nil)
+			}
+		}
+
+		shards[index] = append(shards[index], result)
+		policyShards[result.policy] = index
+	}
+
+	chunks := make([][]interface{}, len(shards))
+	for index, shard := range shards {
+		sort.SliceStable(shard, func(i, j int) bool {
+			return shard[i].key < shard[j].key
+		})
+
+		if index == 0 || len(shard) > 0 {
+			chunks[index] = shardResultValues(shard)
+		}
+	}
+
+	return chunks
+}
+
+func shardResultValues(results []shardResult) []interface{} {
+	values := make([]interface{}, len(results))
+	for i, result := range results {
+		values[i] = result.result
+	}
+
+	return values
+}
+
+// shardIndex returns the index of the shard, reports created before sharding was introduced are the first shard
+func shardIndex(obj metav1.Object) int {
+	index, err := strconv.Atoi(obj.GetLabels()[reportLabelShard])
+	if err != nil {
+		return 0
+	}
+
+	return index
+}
+
+// buildReportShard builds the shard of the report with the given index and results
+func buildReportShard(new *unstructured.Unstructured, index int, results []interface{}) *unstructured.Unstructured {
+	shard := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	shard.SetAPIVersion(new.GetAPIVersion())
+	shard.SetKind(new.GetKind())
+	shard.SetNamespace(new.GetNamespace())
+	shard.SetName(shardName(new.GetName(), index))
+	shard.SetOwnerReferences(new.GetOwnerReferences())
+	shard.SetLabels(map[string]string{
+		reportLabelName:  new.GetName(),
+		reportLabelShard: strconv.Itoa(index),
+	})
+
+	shard.Object["results"] = results
+	shard.Object["summary"] = updateSummary(results)
+	return shard
+}
+
+// listReportShards returns the shards of the cluster / policyReport with the given name
+func (g *ReportGenerator) listReportShards(namespace, name string) ([]*unstructured.Unstructured, error) {
+	var shards []*unstructured.Unstructured
+	gv := report.SchemeGroupVersion
+
+	if namespace == "" {
+		cpolrs, err := g.clusterReportLister.List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("unable to list ClusterPolicyReport: %v", err)
+		}
+
+		for _, cpolr := range cpolrs {
+			if !isReportShard(cpolr, name) {
+				continue
+			}
+
+			obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cpolr)
+			if err != nil {
+				return nil, fmt.Errorf("unable to convert clusterPolicyReport: %v", err)
+			}
+
+			shard := &unstructured.Unstructured{Object: obj}
+			shard.SetAPIVersion(gv.String())
+			shard.SetKind("ClusterPolicyReport")
+			shards = append(shards, shard)
+		}
+
+		return shards, nil
+	}
+
+	polrs, err := g.reportLister.PolicyReports(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("unable to list policyReport for namespace %s: %v", namespace, err)
+	}
+
+	for _, polr := range polrs {
+		if !isReportShard(polr, name) {
+			continue
+		}
+
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(polr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert policyReport: %v", err)
+		}
+
+		shard := &unstructured.Unstructured{Object: obj}
+		shard.SetAPIVersion(gv.String())
+		shard.SetKind("PolicyReport")
+		shards = append(shards, shard)
+	}
+
+	return shards, nil
+}
+
+// maxReportResults returns the maximum number of results in a shard, 0 disables sharding
+func (g *ReportGenerator) maxReportResults() int {
+	if g.configHandler == nil {
+		return 0
+	}
+
+	return g.configHandler.GetMaxReportResults()
+}
+
+// updateReportShards merges the results of the aggregated requests into the results of the existing
+// shards of the report, and spreads the merged results over shards holding at most the configured
+// number of results. The results stay in their shards, shards that are no longer needed are deleted.
+func (g *ReportGenerator) updateReportShards(new *unstructured.Unstructured, aggregatedRequests interface{}) error {
+	shards, err := g.listReportShards(new.GetNamespace(), new.GetName())
+	if err != nil {
+		return err
+	}
+
+	oldResults := []interface{}{}
+	assigned := make(map[string]int)
+	existing := make(map[string]*unstructured.Unstructured, len(shards))
+	for _, shard := range shards {
+		existing[shard.GetName()] = shard
+		results, ok := shard.Object["results"].([]interface{})
+		if !ok {
+			continue
+		}
+
+		oldResults = append(oldResults, results...)
+		for _, result := range results {
+			if resMap, ok := result.(map[string]interface{}); ok {
+				if key, ok := generateHashKey(resMap, deletedResource{}); ok {
+					assigned[key] = shardIndex(shard)
+				}
+			}
+		}
+	}
+
+	merged, _, err := updateResults(map[string]interface{}{"results": oldResults}, new.UnstructuredContent(), aggregatedRequests)
+	if err != nil {
+		return fmt.Errorf("failed to update results entry: %v", err)
+	}
+
+	results, _ := merged["results"].([]interface{})
+	for i, chunk := range shardResults(results, assigned, g.maxReportResults()) {
+		if chunk == nil {
+			continue
+		}
+
+		shard := buildReportShard(new, i, chunk)
+		if err := g.updateReportShard(existing[shard.GetName()], shard); err != nil {
+			return err
+		}
+		delete(existing, shard.GetName())
+	}
+
+	for _, shard := range existing {
+		if err := g.dclient.DeleteResource(shard.GetAPIVersion(), shard.GetKind(), shard.GetNamespace(), shard.GetName(), false); err != nil {
+			return fmt.Errorf("failed to delete policy report shard %s: %v", shard.GetName(), err)
+		}
+
+		g.log.V(3).Info("successfully deleted policy report shard", "kind", shard.GetKind(), "namespace", shard.GetNamespace(), "name", shard.GetName())
+	}
+
+	return nil
+}
+
+// updateReportShard creates the shard if it does not exist, or updates it when its results have changed
+func (g *ReportGenerator) updateReportShard(old, new *unstructured.Unstructured) error {
+	if old == nil {
+		if _, err := g.dclient.CreateResource(new.GetAPIVersion(), new.GetKind(), new.GetNamespace(), new, false); err != nil {
+			return fmt.Errorf("failed to create %s %s: %v", new.GetKind(), new.GetName(), err)
+		}
+
+		g.log.V(2).Info("successfully created policy report", "kind", new.GetKind(), "namespace", new.GetNamespace(), "name", new.GetName())
+		return nil
+	}
+
+	if old.GetDeletionTimestamp() != nil {
+		return fmt.Errorf("%s %s is being deleted", old.GetKind(), old.GetName())
+	}
+
+	oldLabels := old.GetLabels()
+	if !hasResultsChanged(old.UnstructuredContent(), new.UnstructuredContent()) &&
+		oldLabels[reportLabelName] == new.GetLabels()[reportLabelName] &&
+		oldLabels[reportLabelShard] == new.GetLabels()[reportLabelShard] {
+		g.log.V(4).Info("unchanged policy report", "kind", new.GetKind(), "namespace", new.GetNamespace(), "name", new.GetName())
+		return nil
+	}
+
+	newLabels := old.GetLabels()
+	if newLabels == nil {
+		newLabels = map[string]string{}
+	}
+	for k, v := range new.GetLabels() {
+		newLabels[k] = v
+	}
+	new.SetLabels(newLabels)
+	new.SetUID(old.GetUID())
+	new.SetResourceVersion(old.GetResourceVersion())
+
+	if _, err := g.dclient.UpdateResource(new.GetAPIVersion(), new.GetKind(), new.GetNamespace(), new, false); err != nil {
+		return fmt.Errorf("failed to update policy report: %v", err)
+	}
+
+	g.log.V(3).Info("successfully updated policy report", "kind", new.GetKind(), "namespace", new.GetNamespace(), "name", new.GetName())
+	return nil
+}
@@ -0,0 +1,154 @@
+package policyreport
+
+import (
+	"fmt"
+	"testing"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newResult(policy, rule, name, status string) interface{} {
+	return map[string]interface{}{
+		"policy": policy,
+		"rule":   rule,
+		"status": status,
+		"resources": []interface{}{
+			map[string]interface{}{"kind": "Pod", "namespace": "default", "name": name},
+		},
+	}
+}
+
+func Test_ShardName(t *testing.T) {
+	assert.Equal(t, shardName("polr-ns-default", 0), "polr-ns-default")
+	assert.Equal(t, shardName("polr-ns-default", 2), "polr-ns-default-2")
+	assert.Equal(t, shardName(clusterpolicyreport, 1), "clusterpolicyreport-1")
+}
+
+func Test_IsReportShard(t *testing.T) {
+	legacy := &metav1.ObjectMeta{Name: "polr-ns-default"}
+	shard := &metav1.ObjectMeta{Name: "polr-ns-default-1", Labels: map[string]string{reportLabelName: "polr-ns-default", reportLabelShard: "1"}}
+	other := &metav1.ObjectMeta{Name: "custom-report"}
+
+	assert.Assert(t, isReportShard(legacy, "polr-ns-default"))
+	assert.Assert(t, isReportShard(shard, "polr-ns-default"))
+	assert.Assert(t, !isReportShard(other, "polr-ns-default"))
+
+	assert.Assert(t, isFirstShard(legacy))
+	assert.Assert(t, !isFirstShard(shard))
+}
+
+func Test_ShardResults(t *testing.T) {
+	var results []interface{}
+	for i := 4; i >= 0; i-- {
+		results = append(results, newResult("require-labels", "check-team", fmt.Sprintf("pod-%d", i), "pass"))
+	}
+	results = append(results, newResult("disallow-latest", "check-tag", "pod-0", "fail"))
+
+	chunks := shardResults(results, nil, 2)
+	assert.Equal(t, len(chunks), 3)
+	assert.Equal(t, chunks[0][0].(map[string]interface{})["policy"], "disallow-latest")
+	assert.Equal(t, chunks[0][1].(map[string]interface{})["policy"], "require-labels")
+	assert.Equal(t, len(chunks[2]), 2)
+
+	// results are sorted, so the shards do not depend on the order of the results
+	reversed := make([]interface{}, len(results))
+	for i := range results {
+		reversed[i] = results[len(results)-1-i]
+	}
+	assert.DeepEqual(t, shardResults(reversed, nil, 2), chunks)
+
+	assert.Equal(t, len(shardResults(results, nil, 0)), 1)
+	assert.Equal(t, len(shardResults(results, nil, 10)), 1)
+	assert.Equal(t, len(shardResults(nil, nil, 10)), 1)
+}
+
+func Test_ShardResults_Stable(t *testing.T) {
+	results := []interface{}{
+		newResult("disallow-latest", "check-tag", "pod-0", "fail"),
+		newResult("require-labels", "check-team", "pod-0", "pass"),
+		newResult("require-labels", "check-team", "pod-1", "pass"),
+		newResult("require-labels", "check-team", "pod-2", "pass"),
+		newResult("require-labels", "check-team", "pod-3", "pass"),
+	}
+
+	chunks := shardResults(results, nil, 2)
+	assert.Equal(t, len(chunks), 3)
+	assigned := make(map[string]int)
+	for index, chunk := range chunks {
+		for _, result := range chunk {
+			key, _ := generateHashKey(result.(map[string]interface{}), deletedResource{})
+			assigned[key] = index
+		}
+	}
+
+	// a result sorted before the others is added to the shard with room, the other shards are unchanged
+	added := append([]interface{}{newResult("check-probes", "check-liveness", "pod-0", "fail")}, results...)
+	updated := shardResults(added, assigned, 2)
+	assert.Equal(t, len(updated), 3)
+	assert.DeepEqual(t, updated[:2], chunks[:2])
+	assert.Equal(t, updated[2][0].(map[string]interface{})["policy"], "check-probes")
+
+	// removing a result only changes its shard
+	removed := []interface{}{results[0], results[1], results[3], results[4]}
+	updated = shardResults(removed, assigned, 2)
+	assert.Equal(t, len(updated), 3)
+	assert.DeepEqual(t, updated[0], chunks[0])
+	assert.DeepEqual(t, updated[2], chunks[2])
+	assert.Equal(t, len(updated[1]), 1)
+
+	// the shards left without results are not returned
+	updated = shardResults(results[:4], assigned, 2)
+	assert.DeepEqual(t, updated, chunks[:2])
+	updated = shardResults([]interface{}{results[0], results[1], results[4]}, assigned, 2)
+	assert.Equal(t, len(updated), 3)
+	assert.Assert(t, updated[1] == nil)
+}
+
+func Test_ShardIndex(t *testing.T) {
+	assert.Equal(t, shardIndex(&metav1.ObjectMeta{Name: "polr-ns-default"}), 0)
+	assert.Equal(t, shardIndex(&metav1.ObjectMeta{Name: "polr-ns-default-2", Labels: map[string]string{reportLabelShard: "2"}}), 2)
+}
+
+func Test_BuildReportShard(t *testing.T) {
+	new := &unstructured.Unstructured{}
+	new.SetAPIVersion("wgpolicyk8s.io/v1alpha1")
+	new.SetKind("PolicyReport")
+	new.SetNamespace("default")
+	new.SetName("polr-ns-default")
+
+	results := []interface{}{
+		newResult("require-labels", "check-team", "pod-0", "pass"),
+		newResult("require-labels", "check-team", "pod-1", "fail"),
+	}
+
+	shard := buildReportShard(new, 1, results)
+	assert.Equal(t, shard.GetName(), "polr-ns-default-1")
+	assert.Equal(t, shard.GetNamespace(), "default")
+	assert.DeepEqual(t, shard.GetLabels(), map[string]string{reportLabelName: "polr-ns-default", reportLabelShard: "1"})
+
+	summary := shard.Object["summary"].(map[string]interface{})
+	assert.Equal(t, summary["pass"], int64(1))
+	assert.Equal(t, summary["fail"], int64(1))
+}
+
+func Test_HashResults_DeletedResources(t *testing.T) {
+	old := map[string]interface{}{
+		"results": []interface{}{
+			newResult("require-labels", "check-team", "pod-0", "pass"),
+			newResult("require-labels", "check-team", "pod-1", "pass"),
+			newResult("require-labels", "check-team", "pod-2", "pass"),
+		},
+	}
+
+	deleted := []deletedResource{
+		{kind: "Pod", ns: "default", name: "pod-0"},
+		{kind: "Pod", ns: "default", name: "pod-1"},
+	}
+
+	results := getResultsFromHash(hashResults(old, deleted))
+	assert.Equal(t, len(results), 1)
+	resource := results[0].(map[string]interface{})["resources"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, resource["name"], "pod-2")
+}
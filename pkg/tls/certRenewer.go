@@ -8,11 +8,11 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/cenkalti/backoff"
 	"github.com/go-logr/logr"
 	"github.com/kyverno/kyverno/pkg/config"
 	client "github.com/kyverno/kyverno/pkg/dclient"
 	"github.com/pkg/errors"
+	prom "github.com/prometheus/client_golang/prometheus"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -23,9 +23,12 @@ const (
 	// ManagedByLabel is added to Kyverno managed secrets
 	ManagedByLabel string = "cert.kyverno.io/managed-by"
 
-	SelfSignedAnnotation    string = "self-signed-cert"
-	RootCAKey               string = "rootCA.crt"
-	rollingUpdateAnnotation string = "update.kyverno.io/force-rolling-update"
+	SelfSignedAnnotation string = "self-signed-cert"
+	RootCAKey            string = "rootCA.crt"
+
+	// label values of the certificate expiry metric
+	caCertificate  string = "ca"
+	tlsCertificate string = "tls"
 )
 
 // CertRenewer creates rootCA and pem pair to register
@@ -40,17 +43,26 @@ type CertRenewer struct {
 	// IP address where Kyverno controller runs. Only required if out-of-cluster.
 	serverIP string
 
+	// tlsSecret is the name of an externally managed secret holding the TLS pair and the CA,
+	// the self-signed certificates are neither generated nor renewed when it is set
+	tlsSecret string
+
+	// certExpiry records the expiry time of the CA and TLS certificates, it is nil when metrics are disabled
+	certExpiry *prom.GaugeVec
+
 	log logr.Logger
 }
 
 // NewCertRenewer returns an instance of CertRenewer
-func NewCertRenewer(client *client.Client, clientConfig *rest.Config, certRenewalInterval, certValidityDuration time.Duration, serverIP string, log logr.Logger) *CertRenewer {
+func NewCertRenewer(client *client.Client, clientConfig *rest.Config, certRenewalInterval, certValidityDuration time.Duration, serverIP, tlsSecret string, certExpiry *prom.GaugeVec, log logr.Logger) *CertRenewer {
 	return &CertRenewer{
 		client:               client,
 		clientConfig:         clientConfig,
 		certRenewalInterval:  certRenewalInterval,
 		certValidityDuration: certValidityDuration,
 		serverIP:             serverIP,
+		tlsSecret:            tlsSecret,
+		certExpiry:           certExpiry,
 		log:                  log,
 	}
 }
@@ -63,11 +75,64 @@ func (c *CertRenewer) ClientConfig() *rest.Config {
 	return c.clientConfig
 }
 
+// External returns true if the TLS pair and the CA are read from an externally managed secret
+func (c *CertRenewer) External() bool {
+	return c.tlsSecret != ""
+}
+
+// TLSSecretName returns the name of the secret holding the TLS pair served by the webhook server
+func (c *CertRenewer) TLSSecretName() (string, error) {
+	if c.External() {
+		return c.tlsSecret, nil
+	}
+
+	certProps, err := GetTLSCertProps(c.clientConfig)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get TLS Cert Properties")
+	}
+
+	return generateTLSPairSecretName(certProps), nil
+}
+
+// ReadRootCA returns the CA certificate of the webhook server
+func (c *CertRenewer) ReadRootCA() ([]byte, error) {
+	if c.External() {
+		_, caPEM, err := ReadExternalSecret(c.client, config.KyvernoNamespace, c.tlsSecret)
+		return caPEM, err
+	}
+
+	return ReadRootCASecret(c.clientConfig, c.client)
+}
+
+// ReadTLSPair returns the TLS pair of the webhook server
+func (c *CertRenewer) ReadTLSPair() (*PemPair, error) {
+	if c.External() {
+		tlsPair, _, err := ReadExternalSecret(c.client, config.KyvernoNamespace, c.tlsSecret)
+		return tlsPair, err
+	}
+
+	return ReadTLSPair(c.clientConfig, c.client)
+}
+
 // InitTLSPemPair Loads or creates PEM private key and TLS certificate for webhook server.
 // Created pair is stored in cluster's secret.
 // Returns struct with key/certificate pair.
 func (c *CertRenewer) InitTLSPemPair() (*PemPair, error) {
 	logger := c.log.WithName("InitTLSPemPair")
+	if c.External() {
+		tlsPair, err := c.ReadTLSPair()
+		if err != nil {
+			return nil, err
+		}
+
+		if valid, err := c.ValidCert(); err != nil || !valid {
+			logger.Info("the externally managed TLS key/certificate pair is invalid or about to expire", "secret", c.tlsSecret)
+		}
+
+		logger.Info("using externally managed TLS key/certificate pair", "secret", c.tlsSecret)
+		return tlsPair, nil
+	}
+
 	certProps, err := GetTLSCertProps(c.clientConfig)
 	if err != nil {
 		return nil, err
@@ -107,9 +172,25 @@ func (c *CertRenewer) buildTLSPemPairAndWriteToSecrets(props CertificateProps, s
 		return nil, fmt.Errorf("unable to save TLS pair to the cluster: %v", err)
 	}
 
+	c.recordExpiry(caCertificate, caCert.Cert)
+	if certPem, _ := pem.Decode(tlsPair.Certificate); certPem != nil {
+		if cert, err := x509.ParseCertificate(certPem.Bytes); err == nil {
+			c.recordExpiry(tlsCertificate, cert)
+		}
+	}
+
 	return tlsPair, nil
 }
 
+// recordExpiry updates the certificate expiry metric
+func (c *CertRenewer) recordExpiry(certificate string, cert *x509.Certificate) {
+	if c.certExpiry == nil || cert == nil {
+		return
+	}
+
+	c.certExpiry.With(prom.Labels{"certificate": certificate}).Set(float64(cert.NotAfter.Unix()))
+}
+
 // ReadTLSPair Reads the pair of TLS certificate and key from the specified secret.
 
 // WriteCACertToSecret stores the CA cert in secret
@@ -220,67 +301,68 @@ func (c *CertRenewer) WriteTLSPairToSecret(props CertificateProps, pemPair *PemP
 	return nil
 }
 
-// RollingUpdate triggers a rolling update of Kyverno pod.
-// It is used when the rootCA is renewed, the restart of
-// Kyverno pod will register webhook server with new cert
-func (c *CertRenewer) RollingUpdate() error {
+// RenewTLSPemPair renews the self-signed CA and TLS pair without restarting Kyverno.
+// The CA secret holds the new CA followed by the previous one, updateCABundle is called
+// to publish both to the webhook configurations before the TLS pair signed by the new CA
+// is written, so that the certificate served by every instance is trusted during the switch
+func (c *CertRenewer) RenewTLSPemPair(updateCABundle func() error) error {
+	if c.External() {
+		return errors.New("the certificate is managed externally")
+	}
 
-	update := func() error {
-		deploy, err := c.client.GetResource("", "Deployment", config.KyvernoNamespace, config.KyvernoDeploymentName)
-		if err != nil {
-			return errors.Wrap(err, "failed to find Kyverno")
-		}
+	props, err := GetTLSCertProps(c.clientConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to get TLS Cert Properties")
+	}
 
-		if IsKyvernoInRollingUpdate(deploy.UnstructuredContent(), c.log) {
-			return nil
-		}
+	caCert, caPEM, err := GenerateCACert(c.certValidityDuration)
+	if err != nil {
+		return err
+	}
 
-		annotations, ok, err := unstructured.NestedStringMap(deploy.UnstructuredContent(), "spec", "template", "metadata", "annotations")
-		if err != nil {
-			return errors.Wrap(err, "bad annotations")
+	bundle := &PemPair{Certificate: caPEM.Certificate, PrivateKey: caPEM.PrivateKey}
+	if previous, err := ReadRootCASecret(c.clientConfig, c.client); err == nil {
+		if block, _ := pem.Decode(previous); block != nil {
+			bundle.Certificate = append(append([]byte{}, caPEM.Certificate...), pem.EncodeToMemory(block)...)
 		}
+	}
 
-		if !ok {
-			annotations = map[string]string{}
-		}
+	if err := c.WriteCACertToSecret(bundle, props); err != nil {
+		return errors.Wrap(err, "failed to write CA cert to secret")
+	}
 
-		annotations[rollingUpdateAnnotation] = time.Now().String()
-		if err = unstructured.SetNestedStringMap(deploy.UnstructuredContent(),
-			annotations,
-			"spec", "template", "metadata", "annotations",
-		); err != nil {
-			return errors.Wrapf(err, "set annotation %s", rollingUpdateAnnotation)
-		}
+	if err := updateCABundle(); err != nil {
+		return errors.Wrap(err, "failed to update the CA bundle of the webhook configurations")
+	}
 
-		if _, err = c.client.UpdateResource("", "Deployment", config.KyvernoNamespace, deploy, false); err != nil {
-			return errors.Wrap(err, "update Kyverno deployment")
-		}
-		return nil
+	tlsPair, err := GenerateCertPem(caCert, props, c.serverIP, c.certValidityDuration)
+	if err != nil {
+		return err
 	}
 
-	exbackoff := &backoff.ExponentialBackOff{
-		InitialInterval:     500 * time.Millisecond,
-		RandomizationFactor: 0.5,
-		Multiplier:          1.5,
-		MaxInterval:         time.Second,
-		MaxElapsedTime:      3 * time.Second,
-		Clock:               backoff.SystemClock,
+	if err := c.WriteTLSPairToSecret(props, tlsPair); err != nil {
+		return errors.Wrap(err, "unable to save TLS pair to the cluster")
 	}
 
-	exbackoff.Reset()
-	return backoff.Retry(update, exbackoff)
+	c.recordExpiry(caCertificate, caCert.Cert)
+	return nil
+}
+
+// RecordTLSExpiry updates the expiry metric of the TLS certificate served by this instance
+func (c *CertRenewer) RecordTLSExpiry(cert *x509.Certificate) {
+	c.recordExpiry(tlsCertificate, cert)
 }
 
 // ValidCert validates the CA Cert
 func (c *CertRenewer) ValidCert() (bool, error) {
 	logger := c.log.WithName("ValidCert")
 
-	rootCA, err := ReadRootCASecret(c.clientConfig, c.client)
+	rootCA, err := c.ReadRootCA()
 	if err != nil {
 		return false, errors.Wrap(err, "unable to read CA from secret")
 	}
 
-	tlsPair, err := ReadTLSPair(c.clientConfig, c.client)
+	tlsPair, err := c.ReadTLSPair()
 	if err != nil {
 		// wait till next reconcile
 		logger.Info("unable to read TLS PEM Pair from secret", "reason", err.Error())
@@ -301,6 +383,7 @@ func (c *CertRenewer) ValidCert() (bool, error) {
 		return false, nil
 	}
 	pool.AddCert(cac)
+	c.recordExpiry(caCertificate, cac)
 
 	// valid PEM pair
 	_, err = tls.X509KeyPair(tlsPair.Certificate, tlsPair.PrivateKey)
@@ -320,6 +403,7 @@ func (c *CertRenewer) ValidCert() (bool, error) {
 		logger.Error(err, "failed to parse cert")
 		return false, nil
 	}
+	c.recordExpiry(tlsCertificate, cert)
 
 	if _, err = cert.Verify(x509.VerifyOptions{
 		Roots:       pool,
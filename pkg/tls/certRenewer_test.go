@@ -0,0 +1,125 @@
+package tls
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func newTestCertRenewer(t *testing.T) (*CertRenewer, CertificateProps) {
+	gvrToListKind := map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "secrets"}: "SecretList",
+	}
+
+	dclient, err := client.NewMockClient(runtime.NewScheme(), gvrToListKind)
+	assert.NilError(t, err)
+	dclient.SetDiscovery(client.NewFakeDiscoveryClient(nil))
+
+	restConfig := &rest.Config{Host: "https://127.0.0.1:6443"}
+	props, err := GetTLSCertProps(restConfig)
+	assert.NilError(t, err)
+
+	renewer := NewCertRenewer(dclient, restConfig, CertRenewalInterval, CertValidityDuration, "", "", nil, log.Log)
+
+	// the CA and the TLS pair written at startup
+	caCert, caPEM, err := GenerateCACert(CertValidityDuration)
+	assert.NilError(t, err)
+	assert.NilError(t, renewer.WriteCACertToSecret(caPEM, props))
+
+	tlsPair, err := GenerateCertPem(caCert, props, "", CertValidityDuration)
+	assert.NilError(t, err)
+	assert.NilError(t, renewer.WriteTLSPairToSecret(props, tlsPair))
+
+	return renewer, props
+}
+
+// decodeCertificates returns the certificates of the PEM bundle in order
+func decodeCertificates(t *testing.T, bundle []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for block, remaining := pem.Decode(bundle); block != nil; block, remaining = pem.Decode(remaining) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		assert.NilError(t, err)
+		certs = append(certs, cert)
+	}
+
+	return certs
+}
+
+func Test_RenewTLSPemPair(t *testing.T) {
+	renewer, props := newTestCertRenewer(t)
+
+	previousCA, err := ReadRootCASecret(renewer.clientConfig, renewer.client)
+	assert.NilError(t, err)
+	previousPair, err := ReadTLSPair(renewer.clientConfig, renewer.client)
+	assert.NilError(t, err)
+
+	var bundle []byte
+	err = renewer.RenewTLSPemPair(func() error {
+		// the CA bundle is published while the previous TLS pair is still served
+		bundle, err = ReadRootCASecret(renewer.clientConfig, renewer.client)
+		assert.NilError(t, err)
+
+		pair, err := ReadTLSPair(renewer.clientConfig, renewer.client)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, pair.Certificate, previousPair.Certificate)
+		return nil
+	})
+	assert.NilError(t, err)
+
+	// the CA secret holds the new CA followed by the previous one
+	cas := decodeCertificates(t, bundle)
+	assert.Equal(t, len(cas), 2)
+	assert.DeepEqual(t, cas[1].Raw, decodeCertificates(t, previousCA)[0].Raw)
+	assert.Assert(t, !cas[0].Equal(cas[1]))
+
+	// the new TLS pair is signed by the new CA
+	pair, err := ReadTLSPair(renewer.clientConfig, renewer.client)
+	assert.NilError(t, err)
+	assert.Assert(t, string(pair.Certificate) != string(previousPair.Certificate))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cas[0])
+	_, err = decodeCertificates(t, pair.Certificate)[0].Verify(x509.VerifyOptions{
+		Roots:   roots,
+		DNSName: generateInClusterServiceName(props),
+	})
+	assert.NilError(t, err)
+}
+
+func Test_RenewTLSPemPair_UpdateCABundleError(t *testing.T) {
+	renewer, _ := newTestCertRenewer(t)
+
+	previousPair, err := ReadTLSPair(renewer.clientConfig, renewer.client)
+	assert.NilError(t, err)
+
+	err = renewer.RenewTLSPemPair(func() error {
+		return errors.New("webhook configurations not updated")
+	})
+	assert.ErrorContains(t, err, "webhook configurations not updated")
+
+	// the TLS pair is not replaced as the new CA may not be trusted yet
+	pair, err := ReadTLSPair(renewer.clientConfig, renewer.client)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pair.Certificate, previousPair.Certificate)
+	assert.DeepEqual(t, pair.PrivateKey, previousPair.PrivateKey)
+}
+
+func Test_RenewTLSPemPair_External(t *testing.T) {
+	renewer := NewCertRenewer(nil, &rest.Config{}, CertRenewalInterval, CertValidityDuration, "", "webhook-tls", nil, log.Log)
+
+	called := false
+	err := renewer.RenewTLSPemPair(func() error {
+		called = true
+		return nil
+	})
+	assert.ErrorContains(t, err, "managed externally")
+	assert.Assert(t, !called)
+}
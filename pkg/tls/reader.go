@@ -15,6 +15,9 @@ import (
 
 var ErrorsNotFound = "root CA certificate not found"
 
+// ExternalCAKey is the key of the CA certificate in an externally managed secret
+const ExternalCAKey string = "ca.crt"
+
 // ReadRootCASecret returns the RootCA from the pre-defined secret
 func ReadRootCASecret(restConfig *rest.Config, client *client.Client) (result []byte, err error) {
 	certProps, err := GetTLSCertProps(restConfig)
@@ -84,6 +87,39 @@ func ReadTLSPair(restConfig *rest.Config, client *client.Client) (*PemPair, erro
 	return &pemPair, nil
 }
 
+// ReadExternalSecret returns the pem pair and the CA certificate from an externally managed secret,
+// like the secrets of the certificates issued by cert-manager
+func ReadExternalSecret(client *client.Client, namespace, name string) (*PemPair, []byte, error) {
+	unstrSecret, err := client.GetResource("", "Secret", namespace, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get secret %s/%s: %v", namespace, name, err)
+	}
+
+	secret, err := convertToSecret(unstrSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pemPair := PemPair{
+		Certificate: secret.Data[v1.TLSCertKey],
+		PrivateKey:  secret.Data[v1.TLSPrivateKeyKey],
+	}
+
+	if len(pemPair.Certificate) == 0 {
+		return nil, nil, fmt.Errorf("TLS Certificate not found in secret %s/%s", namespace, name)
+	}
+	if len(pemPair.PrivateKey) == 0 {
+		return nil, nil, fmt.Errorf("TLS PrivateKey not found in secret %s/%s", namespace, name)
+	}
+
+	caPEM := secret.Data[ExternalCAKey]
+	if len(caPEM) == 0 {
+		return nil, nil, errors.Errorf("%s in secret %s/%s", ErrorsNotFound, namespace, name)
+	}
+
+	return &pemPair, caPEM, nil
+}
+
 //GetTLSCertProps provides the TLS Certificate Properties
 func GetTLSCertProps(configuration *rest.Config) (certProps CertificateProps, err error) {
 	apiServerURL, err := url.Parse(configuration.Host)
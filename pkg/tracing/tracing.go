@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "kyverno"
+
+// Attribute keys used by the Kyverno spans
+const (
+	PolicyKey            = attribute.Key("kyverno.policy")
+	PolicyNamespaceKey   = attribute.Key("kyverno.policy.namespace")
+	RuleKey              = attribute.Key("kyverno.rule")
+	ResourceKindKey      = attribute.Key("kyverno.resource.kind")
+	ResourceNamespaceKey = attribute.Key("kyverno.resource.namespace")
+	ResourceNameKey      = attribute.Key("kyverno.resource.name")
+	OperationKey         = attribute.Key("kyverno.request.operation")
+	RequestUIDKey        = attribute.Key("kyverno.request.uid")
+	ResultKey            = attribute.Key("kyverno.result")
+	ImageKey             = attribute.Key("kyverno.image")
+	ContextEntryKey      = attribute.Key("kyverno.context.entry")
+	GenerateRequestKey   = attribute.Key("kyverno.generaterequest")
+)
+
+// Init sets up the global tracer provider to export spans with OTLP over gRPC to the
+// collector at address, e.g. 127.0.0.1:4317. The spans are discarded until Init is called.
+// The returned function flushes the pending spans and stops the exporter.
+func Init(address string, log logr.Logger) (func(context.Context) error, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(address), otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(
+		semconv.ServiceNameKey.String(tracerName),
+		semconv.ServiceVersionKey.String(version.BuildVersion),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Error(err, "failed to export spans")
+	}))
+
+	log.Info("tracing enabled", "address", address)
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span as a child of the span in ctx, a nil ctx starts a new trace
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// SetError records err on the span and marks the span as failed
+func SetError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Logger adds the trace and span IDs of the span in ctx to the logger
+func Logger(ctx context.Context, logger logr.Logger) logr.Logger {
+	if ctx == nil {
+		return logger
+	}
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return logger
+	}
+
+	return logger.WithValues("traceID", spanContext.TraceID().String(), "spanID", spanContext.SpanID().String())
+}
+
+// ResourceAttributes returns the attributes identifying a resource
+func ResourceAttributes(kind, namespace, name string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		ResourceKindKey.String(kind),
+		ResourceNamespaceKey.String(namespace),
+		ResourceNameKey.String(name),
+	}
+}
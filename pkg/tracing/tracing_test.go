@@ -0,0 +1,29 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"gotest.tools/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_Logger(t *testing.T) {
+	// spans are not recorded without a tracer provider, the logger is returned as is
+	ctx, span := StartSpan(nil, "test")
+	assert.Assert(t, !span.SpanContext().IsValid())
+	logger := log.Log.WithName("test")
+	assert.Equal(t, Logger(ctx, logger), logger)
+	assert.Equal(t, Logger(nil, logger), logger)
+	span.End()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	ctx, span = StartSpan(context.Background(), "test")
+	defer span.End()
+	assert.Assert(t, span.SpanContext().IsValid())
+	assert.Assert(t, Logger(ctx, logger) != logger)
+}
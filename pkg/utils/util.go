@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
+	"unicode"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -72,6 +74,11 @@ func CRDsInstalled(discovery client.IDiscovery) bool {
 	return true
 }
 
+// KyvernoConfigInstalled checks if the optional KyvernoConfig CRD is installed
+func KyvernoConfigInstalled(discovery client.IDiscovery) bool {
+	return isCRDInstalled(discovery, "KyvernoConfig")
+}
+
 func isCRDInstalled(discoveryClient client.IDiscovery, kind string) bool {
 	gvr, err := discoveryClient.GetGVRFromKind(kind)
 	if gvr.Empty() {
@@ -104,6 +111,11 @@ func ExtractResources(newRaw []byte, request *v1beta1.AdmissionRequest) (unstruc
 		if err != nil {
 			return emptyResource, emptyResource, fmt.Errorf("failed to convert new raw to unstructured: %v", err)
 		}
+
+		// options of subresources like PodExecOptions have no metadata, use the name of the parent resource
+		if newResource.GetName() == "" && request.SubResource != "" {
+			newResource.SetName(request.Name)
+		}
 	}
 
 	// Old Resource
@@ -244,3 +256,30 @@ func ApiextensionsJsonToKyvernoConditions(original apiextensions.JSON) (interfac
 	}
 	return nil, fmt.Errorf("error occurred while parsing %s: %+v", path, err)
 }
+
+// ParseKindSelector parses a kind selector of the form [[group/]version/]Kind[/subresource],
+// for example Pod, v1/Pod, apps/v1/Deployment, Pod/exec or apps/v1/Deployment/scale.
+// The kind is the first part starting with an upper case letter or a wildcard.
+func ParseKindSelector(selector string) (group, version, kind, subresource string) {
+	parts := strings.Split(selector, "/")
+	index := len(parts) - 1
+	for i, part := range parts {
+		if part != "" && (unicode.IsUpper(rune(part[0])) || part[0] == '*') {
+			index = i
+			break
+		}
+	}
+
+	kind = parts[index]
+	subresource = strings.Join(parts[index+1:], "/")
+	switch index {
+	case 0:
+	case 1:
+		version = parts[0]
+	default:
+		group = strings.Join(parts[:index-1], "/")
+		version = parts[index-1]
+	}
+
+	return group, version, kind, subresource
+}
@@ -165,3 +165,27 @@ func Test_ConvertResource(t *testing.T) {
 	}
 
 }
+
+func Test_ParseKindSelector(t *testing.T) {
+	testCases := []struct {
+		selector                          string
+		group, version, kind, subresource string
+	}{
+		{selector: "Pod", kind: "Pod"},
+		{selector: "v1/Pod", version: "v1", kind: "Pod"},
+		{selector: "apps/v1/Deployment", group: "apps", version: "v1", kind: "Deployment"},
+		{selector: "rbac.authorization.k8s.io/v1beta1/ClusterRole", group: "rbac.authorization.k8s.io", version: "v1beta1", kind: "ClusterRole"},
+		{selector: "Pod/exec", kind: "Pod", subresource: "exec"},
+		{selector: "v1/Pod/ephemeralcontainers", version: "v1", kind: "Pod", subresource: "ephemeralcontainers"},
+		{selector: "apps/v1/Deployment/scale", group: "apps", version: "v1", kind: "Deployment", subresource: "scale"},
+		{selector: "*", kind: "*"},
+	}
+
+	for _, test := range testCases {
+		group, version, kind, subresource := ParseKindSelector(test.selector)
+		assert.Equal(t, group, test.group, test.selector)
+		assert.Equal(t, version, test.version, test.selector)
+		assert.Equal(t, kind, test.kind, test.selector)
+		assert.Equal(t, subresource, test.subresource, test.selector)
+	}
+}
@@ -1,9 +1,13 @@
 package webhookconfig
 
 import (
+	cryptotls "crypto/tls"
+	"crypto/x509"
+	"errors"
 	"os"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -19,6 +23,7 @@ import (
 
 type Interface interface {
 	// Run starts the certManager
+	// it should be invoked by the leader
 	Run(stopCh <-chan struct{})
 
 	// InitTLSPemPair initializes the TLSPemPair
@@ -27,22 +32,50 @@ type Interface interface {
 
 	// GetTLSPemPair gets the existing TLSPemPair from the secret
 	GetTLSPemPair() (*ktls.PemPair, error)
+
+	// GetCertificate returns the latest certificate served by the webhook server,
+	// it is read from the secret informer so that a renewed certificate is served on every instance
+	GetCertificate(*cryptotls.ClientHelloInfo) (*cryptotls.Certificate, error)
 }
 type certManager struct {
 	renewer        *tls.CertRenewer
+	register       *Register
 	secretInformer informerv1.SecretInformer
 	secretQueue    chan bool
 	stopCh         <-chan struct{}
 	log            logr.Logger
+
+	// tlsSecretName is the name of the secret holding the TLS pair
+	tlsSecretName string
+
+	// certificate stores the *servedCertificate of the webhook server
+	certificate atomic.Value
+
+	// invalidResourceVersion stores the resource version of the secret whose TLS pair failed to load,
+	// the secret is not parsed again until it is updated
+	invalidResourceVersion atomic.Value
+}
+
+// servedCertificate is the certificate parsed from a version of the secret holding the TLS pair
+type servedCertificate struct {
+	resourceVersion string
+	certificate     *cryptotls.Certificate
 }
 
-func NewCertManager(secretInformer informerv1.SecretInformer, kubeClient kubernetes.Interface, certRenewer *tls.CertRenewer, log logr.Logger, stopCh <-chan struct{}) (Interface, error) {
+func NewCertManager(secretInformer informerv1.SecretInformer, kubeClient kubernetes.Interface, certRenewer *tls.CertRenewer, register *Register, log logr.Logger, stopCh <-chan struct{}) (Interface, error) {
+	tlsSecretName, err := certRenewer.TLSSecretName()
+	if err != nil {
+		return nil, err
+	}
+
 	manager := &certManager{
 		renewer:        certRenewer,
+		register:       register,
 		secretInformer: secretInformer,
 		secretQueue:    make(chan bool, 1),
 		stopCh:         stopCh,
 		log:            log,
+		tlsSecretName:  tlsSecretName,
 	}
 
 	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -59,12 +92,11 @@ func (m *certManager) addSecretFunc(obj interface{}) {
 		return
 	}
 
-	val, ok := secret.GetAnnotations()[tls.SelfSignedAnnotation]
-	if !ok || val != "true" {
+	if !m.watched(secret) {
 		return
 	}
 
-	m.secretQueue <- true
+	m.enqueueSecret()
 }
 
 func (m *certManager) updateSecretFunc(oldObj interface{}, newObj interface{}) {
@@ -74,19 +106,85 @@ func (m *certManager) updateSecretFunc(oldObj interface{}, newObj interface{}) {
 		return
 	}
 
-	val, ok := new.GetAnnotations()[tls.SelfSignedAnnotation]
-	if !ok || val != "true" {
+	if reflect.DeepEqual(old.DeepCopy().Data, new.DeepCopy().Data) {
 		return
 	}
 
-	if reflect.DeepEqual(old.DeepCopy().Data, new.DeepCopy().Data) {
+	if !m.watched(new) {
 		return
 	}
 
-	m.secretQueue <- true
+	m.enqueueSecret()
 	m.log.V(4).Info("secret updated, reconciling webhook configurations")
 }
 
+// watched returns true if the changes of the secret are reconciled by the leader, these are
+// the externally managed secret or the secrets of the self-signed certificates
+func (m *certManager) watched(secret *v1.Secret) bool {
+	if m.renewer.External() {
+		return secret.GetName() == m.tlsSecretName
+	}
+
+	val, ok := secret.GetAnnotations()[tls.SelfSignedAnnotation]
+	return ok && val == "true"
+}
+
+// enqueueSecret queues a secret check without blocking, the queue is only consumed
+// by the leader and a pending check covers all the secret changes
+func (m *certManager) enqueueSecret() {
+	select {
+	case m.secretQueue <- true:
+	default:
+	}
+}
+
+// setCertificate parses the TLS pair and stores the certificate served by the webhook server
+func (m *certManager) setCertificate(tlsPair *ktls.PemPair, resourceVersion string) (*cryptotls.Certificate, error) {
+	certificate, err := cryptotls.X509KeyPair(tlsPair.Certificate, tlsPair.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if leaf, err := x509.ParseCertificate(certificate.Certificate[0]); err == nil {
+		certificate.Leaf = leaf
+		m.renewer.RecordTLSExpiry(leaf)
+	}
+
+	m.certificate.Store(&servedCertificate{resourceVersion: resourceVersion, certificate: &certificate})
+	return &certificate, nil
+}
+
+// GetCertificate returns the certificate of the latest TLS pair in the secret informer,
+// the certificate read at startup or the last valid one is returned until the pair is available and valid
+func (m *certManager) GetCertificate(*cryptotls.ClientHelloInfo) (*cryptotls.Certificate, error) {
+	served, _ := m.certificate.Load().(*servedCertificate)
+
+	secret, err := m.secretInformer.Lister().Secrets(config.KyvernoNamespace).Get(m.tlsSecretName)
+	if err == nil && (served == nil || served.resourceVersion != secret.GetResourceVersion()) {
+		invalid, _ := m.invalidResourceVersion.Load().(string)
+		if invalid != secret.GetResourceVersion() {
+			certificate, err := m.setCertificate(&ktls.PemPair{
+				Certificate: secret.Data[v1.TLSCertKey],
+				PrivateKey:  secret.Data[v1.TLSPrivateKeyKey],
+			}, secret.GetResourceVersion())
+
+			if err == nil {
+				m.log.Info("loaded the TLS key/certificate pair", "secret", secret.GetName(), "resourceVersion", secret.GetResourceVersion())
+				return certificate, nil
+			}
+
+			m.invalidResourceVersion.Store(secret.GetResourceVersion())
+			m.log.Error(err, "failed to load the TLS key/certificate pair", "secret", secret.GetName(), "resourceVersion", secret.GetResourceVersion())
+		}
+	}
+
+	if served == nil {
+		return nil, errors.New("TLS key/certificate pair is not loaded")
+	}
+
+	return served.certificate, nil
+}
+
 func (m *certManager) InitTLSPemPair() {
 	_, err := m.renewer.InitTLSPemPair()
 	if err != nil {
@@ -100,11 +198,15 @@ func (m *certManager) GetTLSPemPair() (*ktls.PemPair, error) {
 	var err error
 
 	retryReadTLS := func() error {
-		tls, err = ktls.ReadTLSPair(m.renewer.ClientConfig(), m.renewer.Client())
+		tls, err = m.renewer.ReadTLSPair()
 		if err != nil {
 			return err
 		}
 
+		if _, err := m.setCertificate(tls, ""); err != nil {
+			return err
+		}
+
 		m.log.Info("read TLS pem pair from the secret")
 		return nil
 	}
@@ -115,17 +217,39 @@ func (m *certManager) GetTLSPemPair() (*ktls.PemPair, error) {
 	return tls, err
 }
 
+// updateCABundle updates the webhook configurations with the CA of the externally managed secret
+func (m *certManager) updateCABundle() {
+	if valid, err := m.renewer.ValidCert(); err != nil || !valid {
+		m.log.Info("the externally managed certificate is invalid or about to expire", "secret", m.tlsSecretName)
+	}
+
+	if err := m.register.UpdateCABundle(); err != nil {
+		m.log.Error(err, "failed to update the CA bundle of the webhook configurations, retrying", "after", tickerInterval.String())
+		time.AfterFunc(tickerInterval, m.enqueueSecret)
+		return
+	}
+
+	m.log.Info("updated the CA bundle of the webhook configurations", "secret", m.tlsSecretName)
+}
+
+// renew renews the self-signed certificates without restarting the webhook server,
+// the CA bundle of the webhook configurations is updated before the served certificate changes
+func (m *certManager) renew() {
+	if err := m.renewer.RenewTLSPemPair(m.register.UpdateCABundle); err != nil {
+		m.log.Error(err, "failed to renew the certificates, retrying", "after", tickerInterval.String())
+		time.AfterFunc(tickerInterval, m.enqueueSecret)
+		return
+	}
+
+	m.log.Info("renewed the certificates")
+}
+
 func (m *certManager) Run(stopCh <-chan struct{}) {
 	if !cache.WaitForCacheSync(stopCh, m.secretInformer.Informer().HasSynced) {
 		m.log.Info("failed to sync informer cache")
 		return
 	}
 
-	m.secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    m.addSecretFunc,
-		UpdateFunc: m.updateSecretFunc,
-	})
-
 	m.log.Info("start managing certificate")
 	certsRenewalTicker := time.NewTicker(tls.CertRenewalInterval)
 	defer certsRenewalTicker.Stop()
@@ -146,13 +270,20 @@ func (m *certManager) Run(stopCh <-chan struct{}) {
 				continue
 			}
 
-			m.log.Info("rootCA is about to expire, trigger a rolling update to renew the cert")
-			if err := m.renewer.RollingUpdate(); err != nil {
-				m.log.Error(err, "unable to trigger a rolling update to renew rootCA, force restarting")
-				os.Exit(1)
+			if m.renewer.External() {
+				m.log.Info("the externally managed certificate is invalid or about to expire, it is not renewed by Kyverno", "secret", m.tlsSecretName)
+				continue
 			}
 
+			m.log.Info("rootCA is about to expire, renewing the certificates")
+			m.renew()
+
 		case <-m.secretQueue:
+			if m.renewer.External() {
+				m.updateCABundle()
+				continue
+			}
+
 			valid, err := m.renewer.ValidCert()
 			if err != nil {
 				m.log.Error(err, "failed to validate cert")
@@ -166,11 +297,8 @@ func (m *certManager) Run(stopCh <-chan struct{}) {
 				continue
 			}
 
-			m.log.Info("rootCA has changed, updating webhook configurations")
-			if err := m.renewer.RollingUpdate(); err != nil {
-				m.log.Error(err, "unable to trigger a rolling update to re-register webhook server, force restarting")
-				os.Exit(1)
-			}
+			m.log.Info("rootCA has changed, renewing the certificates")
+			m.renew()
 
 		case <-m.stopCh:
 			m.log.V(2).Info("stopping cert renewer")
@@ -0,0 +1,131 @@
+package webhookconfig
+
+import (
+	cryptotls "crypto/tls"
+	"testing"
+
+	"github.com/kyverno/kyverno/pkg/config"
+	"github.com/kyverno/kyverno/pkg/tls"
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	rest "k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func newTLSSecret(t *testing.T, name, resourceVersion string) *v1.Secret {
+	caCert, caPEM, err := tls.GenerateCACert(tls.CertValidityDuration)
+	assert.NilError(t, err)
+
+	tlsPair, err := tls.GenerateCertPem(caCert, tls.CertificateProps{Service: config.KyvernoServiceName, Namespace: config.KyvernoNamespace}, "", tls.CertValidityDuration)
+	assert.NilError(t, err)
+
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       config.KyvernoNamespace,
+			ResourceVersion: resourceVersion,
+		},
+		Data: map[string][]byte{
+			v1.TLSCertKey:       tlsPair.Certificate,
+			v1.TLSPrivateKeyKey: tlsPair.PrivateKey,
+			tls.ExternalCAKey:   caPEM.Certificate,
+		},
+		Type: v1.SecretTypeTLS,
+	}
+}
+
+func newExternalCertManager(secretName string) *certManager {
+	renewer := tls.NewCertRenewer(nil, &rest.Config{}, tls.CertRenewalInterval, tls.CertValidityDuration, "", secretName, nil, log.Log)
+	return &certManager{
+		secretInformer: informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Core().V1().Secrets(),
+		renewer:        renewer,
+		secretQueue:    make(chan bool, 1),
+		log:            log.Log,
+		tlsSecretName:  secretName,
+	}
+}
+
+// addSecret adds the secret to the informer cache and notifies the cert manager
+func addSecret(t *testing.T, m *certManager, secret *v1.Secret) {
+	assert.NilError(t, m.secretInformer.Informer().GetIndexer().Add(secret))
+	m.addSecretFunc(secret)
+}
+
+// updateSecret updates the secret in the informer cache and notifies the cert manager
+func updateSecret(t *testing.T, m *certManager, old, new *v1.Secret) {
+	assert.NilError(t, m.secretInformer.Informer().GetIndexer().Update(new))
+	m.updateSecretFunc(old, new)
+}
+
+func Test_CertManager_ExternalSecret_Reload(t *testing.T) {
+	m := newExternalCertManager("webhook-tls")
+
+	_, err := m.GetCertificate(&cryptotls.ClientHelloInfo{})
+	assert.ErrorContains(t, err, "not loaded")
+
+	secret := newTLSSecret(t, "webhook-tls", "1")
+	addSecret(t, m, secret)
+
+	certificate, err := m.GetCertificate(&cryptotls.ClientHelloInfo{})
+	assert.NilError(t, err)
+	expected, err := cryptotls.X509KeyPair(secret.Data[v1.TLSCertKey], secret.Data[v1.TLSPrivateKeyKey])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, certificate.Certificate, expected.Certificate)
+	assert.Equal(t, len(m.secretQueue), 1)
+
+	// the renewed certificate is served without a restart, pending checks are collapsed
+	renewed := newTLSSecret(t, "webhook-tls", "2")
+	updateSecret(t, m, secret, renewed)
+
+	certificate, err = m.GetCertificate(&cryptotls.ClientHelloInfo{})
+	assert.NilError(t, err)
+	expected, err = cryptotls.X509KeyPair(renewed.Data[v1.TLSCertKey], renewed.Data[v1.TLSPrivateKeyKey])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, certificate.Certificate, expected.Certificate)
+	assert.Equal(t, len(m.secretQueue), 1)
+}
+
+func Test_CertManager_ExternalSecret_IgnoreOtherSecrets(t *testing.T) {
+	m := newExternalCertManager("webhook-tls")
+
+	addSecret(t, m, newTLSSecret(t, "other-tls", "1"))
+	_, err := m.GetCertificate(&cryptotls.ClientHelloInfo{})
+	assert.ErrorContains(t, err, "not loaded")
+	assert.Equal(t, len(m.secretQueue), 0)
+
+	// an invalid pair does not replace the served certificate
+	secret := newTLSSecret(t, "webhook-tls", "2")
+	addSecret(t, m, secret)
+	_, err = m.GetCertificate(&cryptotls.ClientHelloInfo{})
+	assert.NilError(t, err)
+
+	invalid := secret.DeepCopy()
+	invalid.ResourceVersion = "3"
+	invalid.Data[v1.TLSPrivateKeyKey] = []byte("invalid")
+	updateSecret(t, m, secret, invalid)
+
+	certificate, err := m.GetCertificate(&cryptotls.ClientHelloInfo{})
+	assert.NilError(t, err)
+	expected, err := cryptotls.X509KeyPair(secret.Data[v1.TLSCertKey], secret.Data[v1.TLSPrivateKeyKey])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, certificate.Certificate, expected.Certificate)
+
+	// the invalid pair is not parsed again on the next handshakes
+	assert.Equal(t, m.invalidResourceVersion.Load(), "3")
+	certificate, err = m.GetCertificate(&cryptotls.ClientHelloInfo{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, certificate.Certificate, expected.Certificate)
+
+	// the pair is loaded once the secret is fixed
+	fixed := newTLSSecret(t, "webhook-tls", "4")
+	updateSecret(t, m, invalid, fixed)
+
+	certificate, err = m.GetCertificate(&cryptotls.ClientHelloInfo{})
+	assert.NilError(t, err)
+	expected, err = cryptotls.X509KeyPair(fixed.Data[v1.TLSCertKey], fixed.Data[v1.TLSPrivateKeyKey])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, certificate.Certificate, expected.Certificate)
+}
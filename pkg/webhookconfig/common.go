@@ -4,7 +4,6 @@ import (
 	"io/ioutil"
 
 	"github.com/kyverno/kyverno/pkg/config"
-	"github.com/kyverno/kyverno/pkg/tls"
 	admregapi "k8s.io/api/admissionregistration/v1beta1"
 	apps "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -19,7 +18,7 @@ func (wrc *Register) readCaData() []byte {
 
 	// Check if ca is defined in the secret tls-ca
 	// assume the key and signed cert have been defined in secret tls.kyverno
-	if caData, err = tls.ReadRootCASecret(wrc.clientConfig, wrc.client); err == nil {
+	if caData, err = wrc.certRenewer.ReadRootCA(); err == nil {
 		logger.V(4).Info("read CA from secret")
 		return caData
 	}
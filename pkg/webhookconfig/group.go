@@ -0,0 +1,128 @@
+package webhookconfig
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// WebhookGroup identifies the resource webhooks that apply the policies sharing a failure policy and a timeout.
+// The default group applies the policies with the failure policy of the Kyverno configuration and the
+// --webhooktimeout flag, each other group is registered as an additional webhook of the resource webhook configurations.
+type WebhookGroup struct {
+	FailurePolicy  string
+	TimeoutSeconds int32
+}
+
+// PolicyWebhookGroup returns the webhook group of the policy, the defaults apply to the settings that are not set in the policy
+func PolicyWebhookGroup(policy *kyverno.ClusterPolicy, defaultFailurePolicy string, defaultTimeoutSeconds int32) WebhookGroup {
+	group := WebhookGroup{
+		FailurePolicy:  defaultFailurePolicy,
+		TimeoutSeconds: defaultTimeoutSeconds,
+	}
+
+	if policy.Spec.FailurePolicy != nil {
+		group.FailurePolicy = *policy.Spec.FailurePolicy
+	}
+
+	if policy.Spec.WebhookTimeoutSeconds != nil {
+		group.TimeoutSeconds = *policy.Spec.WebhookTimeoutSeconds
+	}
+
+	return group
+}
+
+// ParseWebhookGroup returns the webhook group from the parameters of its service path
+func ParseWebhookGroup(failurePolicy, timeoutSeconds string) (WebhookGroup, error) {
+	group := WebhookGroup{}
+	switch failurePolicy {
+	case "ignore":
+		group.FailurePolicy = "Ignore"
+	case "fail":
+		group.FailurePolicy = "Fail"
+	default:
+		return group, errors.Errorf("invalid failure policy %s", failurePolicy)
+	}
+
+	timeout, err := strconv.ParseInt(timeoutSeconds, 10, 32)
+	if err != nil {
+		return group, errors.Wrapf(err, "invalid timeout %s", timeoutSeconds)
+	}
+
+	group.TimeoutSeconds = int32(timeout)
+	return group, nil
+}
+
+// path returns the service path of the group webhook, e.g. /mutate/fail/10
+func (g WebhookGroup) path(servicePath string) string {
+	return fmt.Sprintf("%s/%s/%d", servicePath, strings.ToLower(g.FailurePolicy), g.TimeoutSeconds)
+}
+
+// name returns the name of the group webhook, e.g. mutate-fail-10s.kyverno.svc
+func (g WebhookGroup) name(webhookName string) string {
+	return strings.Replace(webhookName, ".", fmt.Sprintf("-%s-%ds.", strings.ToLower(g.FailurePolicy), g.TimeoutSeconds), 1)
+}
+
+// registeredWebhookGroups returns the groups of the webhooks calling a group path of the service path,
+// the default webhook and the webhooks of other paths are skipped
+func registeredWebhookGroups(webhooks []interface{}, servicePath string) map[WebhookGroup]bool {
+	groups := make(map[WebhookGroup]bool)
+	for _, w := range webhooks {
+		webhook, ok := w.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		path, _, _ := unstructured.NestedString(webhook, "clientConfig", "service", "path")
+		if webhookURL, ok, _ := unstructured.NestedString(webhook, "clientConfig", "url"); ok {
+			if parsed, err := url.Parse(webhookURL); err == nil {
+				path = parsed.Path
+			}
+		}
+
+		params := strings.Split(strings.TrimPrefix(path, servicePath+"/"), "/")
+		if !strings.HasPrefix(path, servicePath+"/") || len(params) != 2 {
+			continue
+		}
+
+		if group, err := ParseWebhookGroup(params[0], params[1]); err == nil {
+			groups[group] = true
+		}
+	}
+
+	return groups
+}
+
+// policyWebhookGroups returns the sorted webhook groups of the selected policies, except the default group
+func policyWebhookGroups(policies []*kyverno.ClusterPolicy, defaultGroup WebhookGroup, selected func(*kyverno.ClusterPolicy) bool) []WebhookGroup {
+	groups := make([]WebhookGroup, 0)
+	found := map[WebhookGroup]bool{defaultGroup: true}
+	for _, policy := range policies {
+		if !selected(policy) {
+			continue
+		}
+
+		group := PolicyWebhookGroup(policy, defaultGroup.FailurePolicy, defaultGroup.TimeoutSeconds)
+		if found[group] {
+			continue
+		}
+
+		found[group] = true
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].FailurePolicy != groups[j].FailurePolicy {
+			return groups[i].FailurePolicy < groups[j].FailurePolicy
+		}
+		return groups[i].TimeoutSeconds < groups[j].TimeoutSeconds
+	})
+
+	return groups
+}
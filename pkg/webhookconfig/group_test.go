@@ -0,0 +1,166 @@
+package webhookconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newGroupPolicy(t *testing.T, spec string) *kyverno.ClusterPolicy {
+	policy := &kyverno.ClusterPolicy{}
+	assert.NilError(t, json.Unmarshal([]byte(spec), &policy.Spec))
+	return policy
+}
+
+func Test_PolicyWebhookGroup(t *testing.T) {
+	testCases := []struct {
+		spec     string
+		expected WebhookGroup
+	}{
+		{
+			spec:     `{}`,
+			expected: WebhookGroup{FailurePolicy: "Ignore", TimeoutSeconds: 10},
+		},
+		{
+			spec:     `{"failurePolicy": "Fail"}`,
+			expected: WebhookGroup{FailurePolicy: "Fail", TimeoutSeconds: 10},
+		},
+		{
+			spec:     `{"failurePolicy": "Fail", "webhookTimeoutSeconds": 30}`,
+			expected: WebhookGroup{FailurePolicy: "Fail", TimeoutSeconds: 30},
+		},
+	}
+
+	for _, testCase := range testCases {
+		group := PolicyWebhookGroup(newGroupPolicy(t, testCase.spec), "Ignore", 10)
+		assert.Equal(t, group, testCase.expected)
+	}
+}
+
+func Test_WebhookGroup_Path(t *testing.T) {
+	group := WebhookGroup{FailurePolicy: "Fail", TimeoutSeconds: 15}
+	assert.Equal(t, group.path("/mutate"), "/mutate/fail/15")
+	assert.Equal(t, group.name("mutate.kyverno.svc"), "mutate-fail-15s.kyverno.svc")
+
+	parsed, err := ParseWebhookGroup("fail", "15")
+	assert.NilError(t, err)
+	assert.Equal(t, parsed, group)
+
+	_, err = ParseWebhookGroup("retry", "15")
+	assert.ErrorContains(t, err, "invalid failure policy")
+
+	_, err = ParseWebhookGroup("ignore", "ten")
+	assert.ErrorContains(t, err, "invalid timeout")
+}
+
+func Test_PolicyWebhookGroups(t *testing.T) {
+	mutate := `"rules": [{"name": "mutate", "mutate": {"patchStrategicMerge": {"metadata": {"labels": {"app": "test"}}}}}]`
+	validate := `"rules": [{"name": "validate", "validate": {"message": "test", "pattern": {"metadata": {"name": "?*"}}}}]`
+
+	policies := []*kyverno.ClusterPolicy{
+		newGroupPolicy(t, `{`+validate+`}`),
+		newGroupPolicy(t, `{"webhookTimeoutSeconds": 10, `+validate+`}`),
+		newGroupPolicy(t, `{"failurePolicy": "Fail", "webhookTimeoutSeconds": 20, `+validate+`}`),
+		newGroupPolicy(t, `{"failurePolicy": "Fail", `+validate+`}`),
+		newGroupPolicy(t, `{"failurePolicy": "Fail", "webhookTimeoutSeconds": 20, `+validate+`}`),
+		newGroupPolicy(t, `{"webhookTimeoutSeconds": 5, `+mutate+`}`),
+	}
+
+	defaultGroup := WebhookGroup{FailurePolicy: "Ignore", TimeoutSeconds: 10}
+	groups := policyWebhookGroups(policies, defaultGroup, (*kyverno.ClusterPolicy).HasValidate)
+	assert.DeepEqual(t, groups, []WebhookGroup{
+		{FailurePolicy: "Fail", TimeoutSeconds: 10},
+		{FailurePolicy: "Fail", TimeoutSeconds: 20},
+	})
+
+	groups = policyWebhookGroups(policies, defaultGroup, (*kyverno.ClusterPolicy).HasMutate)
+	assert.DeepEqual(t, groups, []WebhookGroup{
+		{FailurePolicy: "Ignore", TimeoutSeconds: 5},
+	})
+}
+
+func Test_RegisteredWebhookGroups(t *testing.T) {
+	webhooks := []interface{}{
+		map[string]interface{}{
+			"name":         "validate.kyverno.svc",
+			"clientConfig": map[string]interface{}{"service": map[string]interface{}{"path": "/validate"}},
+		},
+		map[string]interface{}{
+			"name":         "validate-fail-15s.kyverno.svc",
+			"clientConfig": map[string]interface{}{"service": map[string]interface{}{"path": "/validate/fail/15"}},
+		},
+		map[string]interface{}{
+			"name":         "validate-ignore-5s.kyverno.svc",
+			"clientConfig": map[string]interface{}{"url": "https://10.0.0.1:9443/validate/ignore/5"},
+		},
+		map[string]interface{}{
+			"name":         "mutate-fail-15s.kyverno.svc",
+			"clientConfig": map[string]interface{}{"service": map[string]interface{}{"path": "/mutate/fail/15"}},
+		},
+		map[string]interface{}{
+			"name":         "validate-invalid.kyverno.svc",
+			"clientConfig": map[string]interface{}{"service": map[string]interface{}{"path": "/validate/retry/15"}},
+		},
+	}
+
+	groups := registeredWebhookGroups(webhooks, "/validate")
+	assert.DeepEqual(t, groups, map[WebhookGroup]bool{
+		{FailurePolicy: "Fail", TimeoutSeconds: 15}:  true,
+		{FailurePolicy: "Ignore", TimeoutSeconds: 5}: true,
+	})
+
+	assert.Equal(t, len(registeredWebhookGroups(webhooks[:1], "/validate")), 0)
+}
+
+func Test_GroupWebhook(t *testing.T) {
+	webhook := map[string]interface{}{
+		"name":           "validate.kyverno.svc",
+		"failurePolicy":  "Ignore",
+		"timeoutSeconds": int64(10),
+		"clientConfig": map[string]interface{}{
+			"caBundle": "Y2E=",
+			"service": map[string]interface{}{
+				"name":      "kyverno-svc",
+				"namespace": "kyverno",
+				"path":      "/validate",
+			},
+		},
+	}
+
+	group := WebhookGroup{FailurePolicy: "Fail", TimeoutSeconds: 20}
+	wrc := &Register{}
+	groupWebhook, err := wrc.groupWebhook(webhook, group, "validate.kyverno.svc", "/validate")
+	assert.NilError(t, err)
+
+	name, _, _ := unstructured.NestedString(groupWebhook, "name")
+	assert.Equal(t, name, "validate-fail-20s.kyverno.svc")
+	failurePolicy, _, _ := unstructured.NestedString(groupWebhook, "failurePolicy")
+	assert.Equal(t, failurePolicy, "Fail")
+	timeout, _, _ := unstructured.NestedInt64(groupWebhook, "timeoutSeconds")
+	assert.Equal(t, timeout, int64(20))
+	path, _, _ := unstructured.NestedString(groupWebhook, "clientConfig", "service", "path")
+	assert.Equal(t, path, "/validate/fail/20")
+	caBundle, _, _ := unstructured.NestedString(groupWebhook, "clientConfig", "caBundle")
+	assert.Equal(t, caBundle, "Y2E=")
+
+	// the default webhook is not modified
+	path, _, _ = unstructured.NestedString(webhook, "clientConfig", "service", "path")
+	assert.Equal(t, path, "/validate")
+
+	// the webhooks of a debug configuration call the server by URL
+	debugWebhook := map[string]interface{}{
+		"name": "validate.kyverno.svc",
+		"clientConfig": map[string]interface{}{
+			"url": "https://10.0.0.1:9443/validate",
+		},
+	}
+
+	wrc.serverIP = "10.0.0.1:9443"
+	groupWebhook, err = wrc.groupWebhook(debugWebhook, group, "validate.kyverno.svc", "/validate")
+	assert.NilError(t, err)
+	url, _, _ := unstructured.NestedString(groupWebhook, "clientConfig", "url")
+	assert.Equal(t, url, "https://10.0.0.1:9443/validate/fail/20")
+}
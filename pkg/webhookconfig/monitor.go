@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/kyverno/kyverno/pkg/event"
+	"github.com/kyverno/kyverno/pkg/leaderelection"
 	"github.com/kyverno/kyverno/pkg/tls"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -34,7 +35,8 @@ const (
 // annotation update; otherwise lastSeenRequestTime is updated to latestTimestamp.
 //
 //
-// Webhook configurations are checked every tickerInterval across all instances.
+// Webhook configurations are checked every tickerInterval by the leader only,
+// which also re-registers the webhooks and records the webhook status.
 // Currently the check only queries for the expected resource name, and does
 // not compare other details like the webhook settings.
 //
@@ -73,7 +75,7 @@ func (t *Monitor) SetTime(tm time.Time) {
 }
 
 // Run runs the checker and verify the resource update
-func (t *Monitor) Run(register *Register, certRenewer *tls.CertRenewer, eventGen event.Interface, stopCh <-chan struct{}) {
+func (t *Monitor) Run(register *Register, certRenewer *tls.CertRenewer, eventGen event.Interface, leader leaderelection.Interface, stopCh <-chan struct{}) {
 	logger := t.log
 
 	logger.V(4).Info("starting webhook monitor", "interval", idleCheckInterval.String())
@@ -85,10 +87,12 @@ func (t *Monitor) Run(register *Register, certRenewer *tls.CertRenewer, eventGen
 	for {
 		select {
 		case <-ticker.C:
-
-			err := registerWebhookIfNotPresent(register, t.log.WithName("registerWebhookIfNotPresent"))
-			if err != nil {
-				t.log.Error(err, "")
+			isLeader := leader.IsLeader()
+			if isLeader {
+				err := registerWebhookIfNotPresent(register, t.log.WithName("registerWebhookIfNotPresent"))
+				if err != nil {
+					t.log.Error(err, "")
+				}
 			}
 
 			timeDiff := time.Since(t.Time())
@@ -104,6 +108,10 @@ func (t *Monitor) Run(register *Register, certRenewer *tls.CertRenewer, eventGen
 
 			switch {
 			case timeDiff > idleDeadline:
+				if !isLeader {
+					break
+				}
+
 				err := fmt.Errorf("admission control configuration error")
 				logger.Error(err, "webhook check failed", "deadline", idleDeadline.String())
 				if err := status.failure(); err != nil {
@@ -136,6 +144,10 @@ func (t *Monitor) Run(register *Register, certRenewer *tls.CertRenewer, eventGen
 				}
 			}
 
+			if !isLeader {
+				continue
+			}
+
 			// if the status was false before then we update it to true
 			// send request to update the Kyverno deployment
 			if err := status.success(); err != nil {
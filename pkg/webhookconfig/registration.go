@@ -1,13 +1,18 @@
 package webhookconfig
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v1"
+	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/config"
 	client "github.com/kyverno/kyverno/pkg/dclient"
 	"github.com/kyverno/kyverno/pkg/resourcecache"
@@ -18,8 +23,10 @@ import (
 	errorsapi "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	rest "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 )
 
 const (
@@ -33,15 +40,23 @@ const (
 // 3. Resource Validation
 // 4. Resource Mutation
 // 5. Webhook Status Mutation
+//
+// The resource webhook configurations hold an additional webhook for each group of policies
+// that set a failurePolicy or webhookTimeoutSeconds different from the defaults, see WebhookGroup.
 type Register struct {
 	client         *client.Client
 	clientConfig   *rest.Config
 	resCache       resourcecache.ResourceCache
+	pLister        kyvernolister.ClusterPolicyLister
+	npLister       kyvernolister.PolicyLister
 	serverIP       string // when running outside a cluster
 	timeoutSeconds int32
+	certRenewer    *tls.CertRenewer
 	log            logr.Logger
 	debug          bool
 
+	// UpdateWebhookChan receives the signals to update the resource webhook configurations,
+	// pending signals are collapsed
 	UpdateWebhookChan chan bool
 }
 
@@ -50,20 +65,110 @@ func NewRegister(
 	clientConfig *rest.Config,
 	client *client.Client,
 	resCache resourcecache.ResourceCache,
+	pInformer kyvernoinformer.ClusterPolicyInformer,
+	npInformer kyvernoinformer.PolicyInformer,
 	serverIP string,
 	webhookTimeout int32,
+	certRenewer *tls.CertRenewer,
 	debug bool,
 	log logr.Logger) *Register {
-	return &Register{
+	wrc := &Register{
 		clientConfig:      clientConfig,
 		client:            client,
 		resCache:          resCache,
+		pLister:           pInformer.Lister(),
+		npLister:          npInformer.Lister(),
 		serverIP:          serverIP,
 		timeoutSeconds:    webhookTimeout,
+		certRenewer:       certRenewer,
 		log:               log.WithName("Register"),
 		debug:             debug,
-		UpdateWebhookChan: make(chan bool),
+		UpdateWebhookChan: make(chan bool, 1),
+	}
+
+	policyHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    wrc.addPolicy,
+		UpdateFunc: wrc.updatePolicy,
+		DeleteFunc: wrc.deletePolicy,
+	}
+
+	pInformer.Informer().AddEventHandler(policyHandler)
+	npInformer.Informer().AddEventHandler(policyHandler)
+	return wrc
+}
+
+// enqueueWebhookUpdate signals an update of the resource webhook configurations, it does not block
+// when an update is already pending or when this instance does not update the webhook configurations
+func (wrc *Register) enqueueWebhookUpdate() {
+	select {
+	case wrc.UpdateWebhookChan <- true:
+	default:
+	}
+}
+
+func (wrc *Register) addPolicy(obj interface{}) {
+	if hasWebhookSettings(obj) {
+		wrc.enqueueWebhookUpdate()
+	}
+}
+
+func (wrc *Register) updatePolicy(old, cur interface{}) {
+	oldSpec, curSpec := policySpec(old), policySpec(cur)
+	if oldSpec == nil || curSpec == nil || reflect.DeepEqual(oldSpec, curSpec) {
+		return
+	}
+
+	if hasWebhookSettings(old) || hasWebhookSettings(cur) {
+		wrc.enqueueWebhookUpdate()
+	}
+}
+
+func (wrc *Register) deletePolicy(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	if hasWebhookSettings(obj) {
+		wrc.enqueueWebhookUpdate()
+	}
+}
+
+// policySpec returns the spec of a Policy or ClusterPolicy
+func policySpec(obj interface{}) *kyverno.Spec {
+	switch policy := obj.(type) {
+	case *kyverno.ClusterPolicy:
+		return &policy.Spec
+	case *kyverno.Policy:
+		return &policy.Spec
+	}
+
+	return nil
+}
+
+// hasWebhookSettings returns true if the policy sets the failure policy or the timeout of its webhook
+func hasWebhookSettings(obj interface{}) bool {
+	spec := policySpec(obj)
+	return spec != nil && (spec.FailurePolicy != nil || spec.WebhookTimeoutSeconds != nil)
+}
+
+// listPolicies returns the cluster policies and the namespaced policies
+func (wrc *Register) listPolicies() ([]*kyverno.ClusterPolicy, error) {
+	policies, err := wrc.pLister.List(labels.Everything())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cluster policies")
+	}
+
+	nsPolicies, err := wrc.npLister.List(labels.Everything())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list policies")
+	}
+
+	for _, nsPolicy := range nsPolicies {
+		policy := kyverno.ClusterPolicy(*nsPolicy)
+		policies = append(policies, &policy)
 	}
+
+	return policies, nil
 }
 
 // Register clean up the old webhooks and re-creates admission webhooks configs on cluster
@@ -109,6 +214,74 @@ func (wrc *Register) Register() error {
 		return fmt.Errorf("%s", strings.Join(errors, ","))
 	}
 
+	// restore the configured settings and the webhook groups of the resource webhooks
+	wrc.enqueueWebhookUpdate()
+	return nil
+}
+
+// UpdateCABundle updates the CA bundle of the registered webhook configurations in place,
+// the webhooks keep serving while the CA changes
+func (wrc *Register) UpdateCABundle() error {
+	caData := wrc.readCaData()
+	if caData == nil {
+		return errors.New("Unable to extract CA data from configuration")
+	}
+
+	configurations := map[string]string{
+		wrc.getVerifyWebhookMutatingWebhookName():         kindMutating,
+		wrc.getPolicyValidatingWebhookConfigurationName(): kindValidating,
+		wrc.getPolicyMutatingWebhookConfigurationName():   kindMutating,
+		wrc.getResourceValidatingWebhookConfigName():      kindValidating,
+		wrc.getResourceMutatingWebhookConfigName():        kindMutating,
+	}
+
+	errs := make([]string, 0)
+	for name, kind := range configurations {
+		if err := wrc.updateCABundle(kind, name, caData); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, ","))
+	}
+
+	return nil
+}
+
+func (wrc *Register) updateCABundle(kind, name string, caData []byte) error {
+	logger := wrc.log.WithValues("kind", kind, "name", name)
+	webhookConfig, err := wrc.client.GetResource("", kind, "", name)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get %s %s", kind, name)
+	}
+
+	webhooks, _, err := unstructured.NestedSlice(webhookConfig.UnstructuredContent(), "webhooks")
+	if err != nil {
+		return errors.Wrapf(err, "unable to load %s.webhooks", kind)
+	}
+
+	for i := range webhooks {
+		webhook, ok := webhooks[i].(map[string]interface{})
+		if !ok {
+			return errors.Errorf("type mismatched, expected map[string]interface{}, got %T", webhooks[i])
+		}
+
+		// caBundle is encoded as a base64 string in the unstructured object
+		if err := unstructured.SetNestedField(webhook, base64.StdEncoding.EncodeToString(caData), "clientConfig", "caBundle"); err != nil {
+			return errors.Wrapf(err, "unable to set %s.webhooks[%d].clientConfig.caBundle", kind, i)
+		}
+	}
+
+	if err := unstructured.SetNestedSlice(webhookConfig.UnstructuredContent(), webhooks, "webhooks"); err != nil {
+		return errors.Wrapf(err, "unable to set %s.webhooks", kind)
+	}
+
+	if _, err := wrc.client.UpdateResource(webhookConfig.GetAPIVersion(), kind, "", webhookConfig, false); err != nil {
+		return err
+	}
+
+	logger.V(3).Info("updated the CA bundle")
 	return nil
 }
 
@@ -152,9 +325,9 @@ func (wrc *Register) Remove(cleanUp chan<- struct{}) {
 }
 
 // UpdateWebhookConfigurations updates resource webhook configurations dynamically
-// base on the UPDATEs of Kyverno init-config ConfigMap
+// base on the UPDATEs of Kyverno init-config ConfigMap and of the policy webhook settings
 //
-// it currently updates namespaceSelector only, can be extend to update other fieids
+// it currently updates namespaceSelector, failurePolicy and the webhook groups only, can be extend to update other fieids
 func (wrc *Register) UpdateWebhookConfigurations(configHandler config.Interface) {
 	logger := wrc.log.WithName("UpdateWebhookConfigurations")
 	for {
@@ -163,7 +336,7 @@ func (wrc *Register) UpdateWebhookConfigurations(configHandler config.Interface)
 
 		var nsSelector map[string]interface{}
 		webhookCfgs := configHandler.GetWebhooks()
-		if webhookCfgs != nil {
+		if len(webhookCfgs) != 0 && webhookCfgs[0].NamespaceSelector != nil {
 			selector := webhookCfgs[0].NamespaceSelector
 			selectorBytes, err := json.Marshal(*selector)
 			if err != nil {
@@ -177,18 +350,34 @@ func (wrc *Register) UpdateWebhookConfigurations(configHandler config.Interface)
 			}
 		}
 
-		if err := wrc.updateResourceMutatingWebhookConfiguration(nsSelector); err != nil {
+		policies, err := wrc.listPolicies()
+		if err != nil {
+			logger.Error(err, "failed to list policies")
+			go func() { wrc.UpdateWebhookChan <- true }()
+			continue
+		}
+
+		defaultGroup := wrc.DefaultWebhookGroup(configHandler)
+		failurePolicy := defaultGroup.FailurePolicy
+		mutateGroups := policyWebhookGroups(policies, defaultGroup, func(policy *kyverno.ClusterPolicy) bool {
+			return policy.HasMutate() || policy.HasVerifyImages()
+		})
+		validateGroups := policyWebhookGroups(policies, defaultGroup, (*kyverno.ClusterPolicy).HasValidate)
+
+		if err := wrc.updateResourceWebhookConfiguration(kindMutating, wrc.getResourceMutatingWebhookConfigName(),
+			config.MutatingWebhookName, config.MutatingWebhookServicePath, nsSelector, failurePolicy, mutateGroups); err != nil {
 			logger.Error(err, "unable to update mutatingWebhookConfigurations", "name", wrc.getResourceMutatingWebhookConfigName())
 			go func() { wrc.UpdateWebhookChan <- true }()
 		} else {
-			logger.Info("successfully updated mutatingWebhookConfigurations", "name", wrc.getResourceMutatingWebhookConfigName())
+			logger.Info("successfully updated mutatingWebhookConfigurations", "name", wrc.getResourceMutatingWebhookConfigName(), "groups", len(mutateGroups))
 		}
 
-		if err := wrc.updateResourceValidatingWebhookConfiguration(nsSelector); err != nil {
+		if err := wrc.updateResourceWebhookConfiguration(kindValidating, wrc.getResourceValidatingWebhookConfigName(),
+			config.ValidatingWebhookName, config.ValidatingWebhookServicePath, nsSelector, failurePolicy, validateGroups); err != nil {
 			logger.Error(err, "unable to update validatingWebhookConfigurations", "name", wrc.getResourceValidatingWebhookConfigName())
 			go func() { wrc.UpdateWebhookChan <- true }()
 		} else {
-			logger.Info("successfully updated validatingWebhookConfigurations", "name", wrc.getResourceValidatingWebhookConfigName())
+			logger.Info("successfully updated validatingWebhookConfigurations", "name", wrc.getResourceValidatingWebhookConfigName(), "groups", len(validateGroups))
 		}
 	}
 }
@@ -543,6 +732,43 @@ func (wrc *Register) getVerifyWebhookMutatingWebhookName() string {
 	return mutatingConfig
 }
 
+// DefaultWebhookGroup returns the webhook group of the policies that do not set a failure policy or a timeout
+func (wrc *Register) DefaultWebhookGroup(configHandler config.Interface) WebhookGroup {
+	return WebhookGroup{
+		FailurePolicy:  configHandler.GetDefaultFailurePolicy(),
+		TimeoutSeconds: wrc.timeoutSeconds,
+	}
+}
+
+// MutateWebhookGroups returns the webhook groups registered in the resource mutating webhook configuration
+func (wrc *Register) MutateWebhookGroups() map[WebhookGroup]bool {
+	return wrc.webhookGroups(kindMutating, wrc.getResourceMutatingWebhookConfigName(), config.MutatingWebhookServicePath)
+}
+
+// ValidateWebhookGroups returns the webhook groups registered in the resource validating webhook configuration
+func (wrc *Register) ValidateWebhookGroups() map[WebhookGroup]bool {
+	return wrc.webhookGroups(kindValidating, wrc.getResourceValidatingWebhookConfigName(), config.ValidatingWebhookServicePath)
+}
+
+// webhookGroups returns the webhook groups registered in the webhook configuration, it is read from the informer
+// cache so that every instance knows the registered groups. No group is returned when the configuration cannot be
+// read, the default webhook then applies all the policies.
+func (wrc *Register) webhookGroups(kind, name, servicePath string) map[WebhookGroup]bool {
+	webhookCache, ok := wrc.resCache.GetGVRCache(kind)
+	if !ok {
+		return map[WebhookGroup]bool{}
+	}
+
+	resourceWebhook, err := webhookCache.Lister().Get(name)
+	if err != nil {
+		wrc.log.V(4).Info("unable to get the webhook configuration", "kind", kind, "name", name, "reason", err.Error())
+		return map[WebhookGroup]bool{}
+	}
+
+	webhooks, _, _ := unstructured.NestedSlice(resourceWebhook.UnstructuredContent(), "webhooks")
+	return registeredWebhookGroups(webhooks, servicePath)
+}
+
 // GetWebhookTimeOut returns the value of webhook timeout
 func (wrc *Register) GetWebhookTimeOut() time.Duration {
 	return time.Duration(wrc.timeoutSeconds)
@@ -618,74 +844,79 @@ func (wrc *Register) checkEndpoint() error {
 	return err
 }
 
-func (wrc *Register) updateResourceValidatingWebhookConfiguration(nsSelector map[string]interface{}) error {
-	validatingCache, _ := wrc.resCache.GetGVRCache(kindValidating)
+// updateResourceWebhookConfiguration sets the namespaceSelector and the failurePolicy of the default resource webhook,
+// and replaces the other webhooks of the configuration with a copy of the default webhook for each webhook group
+func (wrc *Register) updateResourceWebhookConfiguration(kind, name, webhookName, servicePath string, nsSelector map[string]interface{}, failurePolicy string, groups []WebhookGroup) error {
+	webhookCache, _ := wrc.resCache.GetGVRCache(kind)
 
-	resourceValidating, err := validatingCache.Lister().Get(wrc.getResourceValidatingWebhookConfigName())
+	resourceWebhook, err := webhookCache.Lister().Get(name)
 	if err != nil {
-		return errors.Wrapf(err, "unable to get validatingWebhookConfigurations")
+		return errors.Wrapf(err, "unable to get %s", kind)
 	}
 
-	webhooksUntyped, _, err := unstructured.NestedSlice(resourceValidating.UnstructuredContent(), "webhooks")
+	resourceWebhook = resourceWebhook.DeepCopy()
+	webhooksUntyped, _, err := unstructured.NestedSlice(resourceWebhook.UnstructuredContent(), "webhooks")
 	if err != nil {
-		return errors.Wrapf(err, "unable to load validatingWebhookConfigurations.webhooks")
+		return errors.Wrapf(err, "unable to load %s.webhooks", kind)
 	}
 
-	var webhooks map[string]interface{}
-	var ok bool
-	if webhooksUntyped != nil {
-		webhooks, ok = webhooksUntyped[0].(map[string]interface{})
-		if !ok {
-			return errors.Wrapf(err, "type mismatched, expected map[string]interface{}, got %T", webhooksUntyped[0])
-		}
+	if len(webhooksUntyped) == 0 {
+		return errors.Errorf("%s %s has no webhook", kind, name)
 	}
-	if err = unstructured.SetNestedMap(webhooks, nsSelector, "namespaceSelector"); err != nil {
-		return errors.Wrapf(err, "unable to set validatingWebhookConfigurations.webhooks[0].namespaceSelector")
+
+	webhook, ok := webhooksUntyped[0].(map[string]interface{})
+	if !ok {
+		return errors.Errorf("type mismatched, expected map[string]interface{}, got %T", webhooksUntyped[0])
 	}
 
-	if err = unstructured.SetNestedSlice(resourceValidating.UnstructuredContent(), []interface{}{webhooks}, "webhooks"); err != nil {
-		return errors.Wrapf(err, "unable to set validatingWebhookConfigurations.webhooks")
+	if err = unstructured.SetNestedMap(webhook, nsSelector, "namespaceSelector"); err != nil {
+		return errors.Wrapf(err, "unable to set %s.webhooks[0].namespaceSelector", kind)
 	}
 
-	if _, err := wrc.client.UpdateResource(resourceValidating.GetAPIVersion(), resourceValidating.GetKind(), "", resourceValidating, false); err != nil {
-		return err
+	if err = unstructured.SetNestedField(webhook, failurePolicy, "failurePolicy"); err != nil {
+		return errors.Wrapf(err, "unable to set %s.webhooks[0].failurePolicy", kind)
 	}
 
-	return nil
-}
+	webhooks := []interface{}{webhook}
+	for _, group := range groups {
+		groupWebhook, err := wrc.groupWebhook(webhook, group, webhookName, servicePath)
+		if err != nil {
+			return errors.Wrapf(err, "unable to build the %s webhook %s", kind, group.name(webhookName))
+		}
 
-func (wrc *Register) updateResourceMutatingWebhookConfiguration(nsSelector map[string]interface{}) error {
-	mutatingCache, _ := wrc.resCache.GetGVRCache(kindMutating)
+		webhooks = append(webhooks, groupWebhook)
+	}
 
-	resourceMutating, err := mutatingCache.Lister().Get(wrc.getResourceMutatingWebhookConfigName())
-	if err != nil {
-		return errors.Wrapf(err, "unable to get mutatingWebhookConfigurations")
+	if err = unstructured.SetNestedSlice(resourceWebhook.UnstructuredContent(), webhooks, "webhooks"); err != nil {
+		return errors.Wrapf(err, "unable to set %s.webhooks", kind)
 	}
 
-	webhooksUntyped, _, err := unstructured.NestedSlice(resourceMutating.UnstructuredContent(), "webhooks")
-	if err != nil {
-		return errors.Wrapf(err, "unable to load mutatingWebhookConfigurations.webhooks")
+	if _, err := wrc.client.UpdateResource(resourceWebhook.GetAPIVersion(), resourceWebhook.GetKind(), "", resourceWebhook, false); err != nil {
+		return err
 	}
 
-	var webhooks map[string]interface{}
-	var ok bool
-	if webhooksUntyped != nil {
-		webhooks, ok = webhooksUntyped[0].(map[string]interface{})
-		if !ok {
-			return errors.Wrapf(err, "type mismatched, expected map[string]interface{}, got %T", webhooksUntyped[0])
-		}
+	return nil
+}
+
+// groupWebhook returns a copy of the default resource webhook that calls the service path of the webhook group
+func (wrc *Register) groupWebhook(webhook map[string]interface{}, group WebhookGroup, webhookName, servicePath string) (map[string]interface{}, error) {
+	groupWebhook := runtime.DeepCopyJSON(webhook)
+	if err := unstructured.SetNestedField(groupWebhook, group.name(webhookName), "name"); err != nil {
+		return nil, err
 	}
-	if err = unstructured.SetNestedMap(webhooks, nsSelector, "namespaceSelector"); err != nil {
-		return errors.Wrapf(err, "unable to set mutatingWebhookConfigurations.webhooks[0].namespaceSelector")
+
+	if err := unstructured.SetNestedField(groupWebhook, group.FailurePolicy, "failurePolicy"); err != nil {
+		return nil, err
 	}
 
-	if err = unstructured.SetNestedSlice(resourceMutating.UnstructuredContent(), []interface{}{webhooks}, "webhooks"); err != nil {
-		return errors.Wrapf(err, "unable to set mutatingWebhookConfigurations.webhooks")
+	if err := unstructured.SetNestedField(groupWebhook, int64(group.TimeoutSeconds), "timeoutSeconds"); err != nil {
+		return nil, err
 	}
 
-	if _, err := wrc.client.UpdateResource(resourceMutating.GetAPIVersion(), resourceMutating.GetKind(), "", resourceMutating, false); err != nil {
-		return err
+	if _, ok, _ := unstructured.NestedString(groupWebhook, "clientConfig", "url"); ok {
+		url := fmt.Sprintf("https://%s%s", wrc.serverIP, group.path(servicePath))
+		return groupWebhook, unstructured.SetNestedField(groupWebhook, url, "clientConfig", "url")
 	}
 
-	return nil
+	return groupWebhook, unstructured.SetNestedField(groupWebhook, group.path(servicePath), "clientConfig", "service", "path")
 }
@@ -2,11 +2,14 @@ package webhooks
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/common"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/engine"
 	"github.com/kyverno/kyverno/pkg/engine/response"
 	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
 	yamlv2 "gopkg.in/yaml.v2"
@@ -39,6 +42,52 @@ func toBlockResource(engineReponses []*response.EngineResponse, log logr.Logger)
 	return false
 }
 
+// filterPoliciesByValidationFailureAction returns the policies whose validationFailureAction for the resource
+// is the given action, policies with per-namespace overrides are cached as both enforce and audit policies
+func filterPoliciesByValidationFailureAction(policies []*kyverno.ClusterPolicy, action string, resource unstructured.Unstructured, namespaceLabels map[string]string) []*kyverno.ClusterPolicy {
+	var filtered []*kyverno.ClusterPolicy
+	for _, policy := range policies {
+		if len(policy.Spec.ValidationFailureActionOverrides) == 0 || engine.GetValidationFailureAction(*policy, resource, namespaceLabels) == action {
+			filtered = append(filtered, policy)
+		}
+	}
+
+	return filtered
+}
+
+// filterPoliciesWithWarnings returns the policies that emit admission warnings
+func filterPoliciesWithWarnings(policies []*kyverno.ClusterPolicy) []*kyverno.ClusterPolicy {
+	var filtered []*kyverno.ClusterPolicy
+	for _, policy := range policies {
+		if policy.Spec.EmitWarning {
+			filtered = append(filtered, policy)
+		}
+	}
+
+	return filtered
+}
+
+// filterPoliciesWithoutWarnings returns the policies that do not emit admission warnings
+func filterPoliciesWithoutWarnings(policies []*kyverno.ClusterPolicy) []*kyverno.ClusterPolicy {
+	var filtered []*kyverno.ClusterPolicy
+	for _, policy := range policies {
+		if !policy.Spec.EmitWarning {
+			filtered = append(filtered, policy)
+		}
+	}
+
+	return filtered
+}
+
+// resourceForRequest returns the new resource, or the old one for delete requests
+func resourceForRequest(newResource, oldResource unstructured.Unstructured) unstructured.Unstructured {
+	if reflect.DeepEqual(newResource, unstructured.Unstructured{}) {
+		return oldResource
+	}
+
+	return newResource
+}
+
 // getEnforceFailureErrorMsg gets the error messages for failed enforce policy
 func getEnforceFailureErrorMsg(engineResponses []*response.EngineResponse) string {
 	policyToRule := make(map[string]interface{})
@@ -61,6 +110,37 @@ func getEnforceFailureErrorMsg(engineResponses []*response.EngineResponse) strin
 	return "\n\nresource " + resourceName + " was blocked due to the following policies\n\n" + string(result)
 }
 
+// getWarningMessages returns a warning for each failed validation rule and each applied mutation rule
+func getWarningMessages(er *response.EngineResponse) []string {
+	var warnings []string
+	for _, rule := range er.PolicyResponse.Rules {
+		validationFailed := rule.Type == engineutils.Validation.String() && !rule.Success
+		mutationApplied := rule.Type == engineutils.Mutation.String() && rule.Success && len(rule.Patches) > 0
+		if validationFailed || mutationApplied {
+			warnings = append(warnings, fmt.Sprintf("policy %s.%s: %s", er.PolicyResponse.Policy.Name, rule.Name, rule.Message))
+		}
+	}
+
+	return warnings
+}
+
+// limitWarnings keeps at most maxCount warnings and truncates warnings longer than maxSize characters
+func limitWarnings(warnings []string, maxCount, maxSize int) []string {
+	if len(warnings) > maxCount {
+		warnings = warnings[:maxCount]
+	}
+
+	var limited []string
+	for _, warning := range warnings {
+		if runes := []rune(warning); len(runes) > maxSize {
+			warning = string(runes[:maxSize])
+		}
+		limited = append(limited, warning)
+	}
+
+	return limited
+}
+
 // getErrorMsg gets all failed engine response message
 func getErrorMsg(engineReponses []*response.EngineResponse) string {
 	var str []string
@@ -188,3 +268,40 @@ func excludeKyvernoResources(kind string) bool {
 		return false
 	}
 }
+
+// getSubresource returns the subresource targeted by the admission request, the kind of the
+// parent resource is looked up with the discovery client and defaults to the kind of the request
+func getSubresource(request *v1beta1.AdmissionRequest, dclient *client.Client, logger logr.Logger) engine.Subresource {
+	if request.SubResource == "" {
+		return engine.Subresource{}
+	}
+
+	subresource := engine.Subresource{
+		Name:       request.SubResource,
+		ParentKind: schema.GroupVersionKind{Group: request.Kind.Group, Version: request.Kind.Version, Kind: request.Kind.Kind},
+	}
+
+	if dclient == nil || dclient.DiscoveryClient == nil {
+		return subresource
+	}
+
+	gv := schema.GroupVersion{Group: request.Resource.Group, Version: request.Resource.Version}
+	apiResource, _, err := dclient.DiscoveryClient.FindResource(gv.String(), request.Resource.Resource)
+	if err != nil {
+		logger.V(3).Info("failed to find the parent resource of the subresource", "resource", request.Resource.String(), "subresource", request.SubResource, "error", err.Error())
+		return subresource
+	}
+
+	subresource.ParentKind = gv.WithKind(apiResource.Kind)
+	return subresource
+}
+
+// getPolicyCacheKind returns the kind used to look up the policies of the admission request,
+// policies for subresources are indexed by Kind/subresource
+func getPolicyCacheKind(request *v1beta1.AdmissionRequest, subresource engine.Subresource) string {
+	if subresource.Name == "" {
+		return request.Kind.Kind
+	}
+
+	return subresource.ParentKind.Kind + "/" + subresource.Name
+}
@@ -0,0 +1,71 @@
+package webhooks
+
+import (
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"gotest.tools/assert"
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_getWarningMessages(t *testing.T) {
+	er := &response.EngineResponse{
+		PolicyResponse: response.PolicyResponse{
+			Policy: response.PolicySpec{Name: "require-labels"},
+			Rules: []response.RuleResponse{
+				{Name: "check-team", Type: "Validation", Message: "label 'team' is required", Success: false},
+				{Name: "check-app", Type: "Validation", Message: "validation rule 'check-app' passed.", Success: true},
+				{Name: "add-owner", Type: "Mutation", Message: "mutated Pod/test", Patches: [][]byte{[]byte(`{"op":"add"}`)}, Success: true},
+				{Name: "add-nothing", Type: "Mutation", Message: "mutated Pod/test", Success: true},
+			},
+		},
+	}
+
+	warnings := getWarningMessages(er)
+	assert.DeepEqual(t, warnings, []string{
+		"policy require-labels.check-team: label 'team' is required",
+		"policy require-labels.add-owner: mutated Pod/test",
+	})
+}
+
+func Test_limitWarnings(t *testing.T) {
+	warnings := []string{"first warning", "second warning", "third warning"}
+
+	assert.DeepEqual(t, limitWarnings(warnings, 2, 256), []string{"first warning", "second warning"})
+	assert.DeepEqual(t, limitWarnings(warnings, 10, 5), []string{"first", "secon", "third"})
+	assert.Assert(t, limitWarnings(warnings, 0, 256) == nil)
+}
+
+func Test_filterPoliciesWithWarnings(t *testing.T) {
+	warn := &kyverno.ClusterPolicy{Spec: kyverno.Spec{EmitWarning: true}}
+	audit := &kyverno.ClusterPolicy{}
+	policies := []*kyverno.ClusterPolicy{warn, audit}
+
+	// the policies with warnings are evaluated by the webhook, the others by the audit handler
+	assert.DeepEqual(t, filterPoliciesWithWarnings(policies), []*kyverno.ClusterPolicy{warn})
+	assert.DeepEqual(t, filterPoliciesWithoutWarnings(policies), []*kyverno.ClusterPolicy{audit})
+}
+
+func Test_getPolicyCacheKind(t *testing.T) {
+	request := &v1beta1.AdmissionRequest{
+		Kind:     metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		Resource: metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+	}
+	subresource := getSubresource(request, nil, log.Log)
+	assert.Equal(t, subresource, engine.Subresource{})
+	assert.Equal(t, getPolicyCacheKind(request, subresource), "Pod")
+
+	// without discovery the parent kind defaults to the kind of the request
+	request.SubResource = "status"
+	subresource = getSubresource(request, nil, log.Log)
+	assert.Equal(t, subresource.ParentKind, schema.GroupVersionKind{Version: "v1", Kind: "Pod"})
+	assert.Equal(t, getPolicyCacheKind(request, subresource), "Pod/status")
+
+	subresource = engine.Subresource{Name: "exec", ParentKind: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}}
+	assert.Equal(t, getPolicyCacheKind(request, subresource), "Pod/exec")
+}
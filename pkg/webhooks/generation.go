@@ -38,13 +38,14 @@ func (ws *WebhookServer) applyGeneratePolicies(request *v1beta1.AdmissionRequest
 	generateEngineResponsesSenderForAdmissionReviewDurationMetric := make(chan []*response.EngineResponse, 1)
 	generateEngineResponsesSenderForAdmissionRequestsCountMetric := make(chan []*response.EngineResponse, 1)
 
-	go ws.handleGenerate(request, policies, policyContext.JSONContext, policyContext.AdmissionInfo, ws.configHandler, ts, &admissionReviewCompletionLatencyChannel, &generateEngineResponsesSenderForAdmissionReviewDurationMetric, &generateEngineResponsesSenderForAdmissionRequestsCountMetric)
+	go ws.handleGenerate(policyContext.TraceContext, request, policies, policyContext.JSONContext, policyContext.AdmissionInfo, ws.configHandler, ts, &admissionReviewCompletionLatencyChannel, &generateEngineResponsesSenderForAdmissionReviewDurationMetric, &generateEngineResponsesSenderForAdmissionRequestsCountMetric)
 	go registerAdmissionReviewDurationMetricGenerate(logger, *ws.promConfig.Metrics, string(request.Operation), &admissionReviewCompletionLatencyChannel, &generateEngineResponsesSenderForAdmissionReviewDurationMetric)
 	go registerAdmissionRequestsMetricGenerate(logger, *ws.promConfig.Metrics, string(request.Operation), &generateEngineResponsesSenderForAdmissionRequestsCountMetric)
 }
 
 //handleGenerate handles admission-requests for policies with generate rules
 func (ws *WebhookServer) handleGenerate(
+	traceCtx contextdefault.Context,
 	request *v1beta1.AdmissionRequest,
 	policies []*kyverno.ClusterPolicy,
 	ctx *context.Context,
@@ -76,6 +77,7 @@ func (ws *WebhookServer) handleGenerate(
 			ResourceCache:       ws.resCache,
 			JSONContext:         ctx,
 			Client:              ws.client,
+			TraceContext:        traceCtx,
 		}
 
 		for _, policy := range policies {
@@ -469,6 +471,7 @@ func failedEvents(err error, gr kyverno.GenerateRequestSpec, resource unstructur
 	re.Reason = event.PolicyFailed.String()
 	re.Source = event.GeneratePolicyController
 	re.Message = fmt.Sprintf("policy %s failed to apply: %v", gr.Policy, err)
+	re.PolicyName = gr.Policy
 
 	return []event.Info{re}
 }
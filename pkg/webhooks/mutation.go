@@ -12,6 +12,7 @@ import (
 	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/engine"
 	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/event"
 	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
 	"github.com/kyverno/kyverno/pkg/metrics"
 	policyExecutionDuration "github.com/kyverno/kyverno/pkg/metrics/policyexecutionduration"
@@ -23,28 +24,28 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
-func (ws *WebhookServer) applyMutatePolicies(request *v1beta1.AdmissionRequest, policyContext *engine.PolicyContext, policies []*v1.ClusterPolicy, ts int64, logger logr.Logger) []byte {
+func (ws *WebhookServer) applyMutatePolicies(request *v1beta1.AdmissionRequest, policyContext *engine.PolicyContext, policies []*v1.ClusterPolicy, ts int64, logger logr.Logger) ([]byte, []*response.EngineResponse, []string) {
 	var mutateEngineResponses []*response.EngineResponse
 
-	mutatePatches, mutateEngineResponses := ws.handleMutation(request, policyContext, policies)
+	mutatePatches, mutateEngineResponses, warnings := ws.handleMutation(request, policyContext, policies)
 	logger.V(6).Info("", "generated patches", string(mutatePatches))
 
 	admissionReviewLatencyDuration := int64(time.Since(time.Unix(ts, 0)))
 	go registerAdmissionReviewDurationMetricMutate(logger, *ws.promConfig.Metrics, string(request.Operation), mutateEngineResponses, admissionReviewLatencyDuration)
 	go registerAdmissionRequestsMetricMutate(logger, *ws.promConfig.Metrics, string(request.Operation), mutateEngineResponses)
 
-	return mutatePatches
+	return mutatePatches, mutateEngineResponses, warnings
 }
 
 // handleMutation handles mutating webhook admission request
-// return value: generated patches, triggered policies, engine responses correspdonding to the triggered policies
+// return value: generated patches, engine responses correspdonding to the triggered policies, admission warnings
 func (ws *WebhookServer) handleMutation(
 	request *v1beta1.AdmissionRequest,
 	policyContext *engine.PolicyContext,
-	policies []*kyverno.ClusterPolicy) ([]byte, []*response.EngineResponse) {
+	policies []*kyverno.ClusterPolicy) ([]byte, []*response.EngineResponse, []string) {
 
 	if len(policies) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	resourceName := request.Kind.Kind + "/" + request.Name
@@ -59,7 +60,7 @@ func (ws *WebhookServer) handleMutation(
 	if err != nil {
 		// as resource cannot be parsed, we skip processing
 		logger.Error(err, "failed to extract resource")
-		return nil, nil
+		return nil, nil, nil
 	}
 	var deletionTimeStamp *metav1.Time
 	if reflect.DeepEqual(newR, unstructured.Unstructured{}) {
@@ -69,10 +70,11 @@ func (ws *WebhookServer) handleMutation(
 	}
 
 	if deletionTimeStamp != nil && request.Operation == v1beta1.Update {
-		return nil, nil
+		return nil, nil, nil
 	}
 	var patches [][]byte
 	var engineResponses []*response.EngineResponse
+	var warnings []string
 
 	for _, policy := range policies {
 		if !policy.HasMutate() {
@@ -85,6 +87,7 @@ func (ws *WebhookServer) handleMutation(
 		if err != nil {
 			// TODO report errors in engineResponse and record in metrics
 			logger.Error(err, "mutate error")
+			ws.eventGen.Add(generateErrorEvents(policy, policyContext.NewResource, err, logger)...)
 			continue
 		}
 
@@ -96,6 +99,9 @@ func (ws *WebhookServer) handleMutation(
 
 		policyContext.NewResource = engineResponse.PatchedResource
 		engineResponses = append(engineResponses, engineResponse)
+		if policy.Spec.EmitWarning {
+			warnings = append(warnings, getWarningMessages(engineResponse)...)
+		}
 
 		// registering the kyverno_policy_results_total metric concurrently
 		go ws.registerPolicyResultsMetricMutation(logger, string(request.Operation), *policy, *engineResponse)
@@ -117,7 +123,7 @@ func (ws *WebhookServer) handleMutation(
 	//   all policies were applied successfully.
 	//   create an event on the resource
 	// ADD EVENTS
-	events := generateEvents(engineResponses, false, (request.Operation == v1beta1.Update), logger)
+	events := generateEvents(engineResponses, false, (request.Operation == v1beta1.Update), event.ResourceMutated, logger)
 	ws.eventGen.Add(events...)
 
 	// debug info
@@ -133,7 +139,7 @@ func (ws *WebhookServer) handleMutation(
 	}()
 
 	// patches holds all the successful patches, if no patch is created, it returns nil
-	return engineutils.JoinPatches(patches), engineResponses
+	return engineutils.JoinPatches(patches), engineResponses, warnings
 }
 
 func (ws *WebhookServer) applyMutation(request *v1beta1.AdmissionRequest, policyContext *engine.PolicyContext, logger logr.Logger) (*response.EngineResponse, [][]byte, error) {
@@ -1,11 +1,12 @@
 package webhooks
 
 import (
+	"bytes"
 	"encoding/json"
 	"testing"
 
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
-	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/engine"
 	"github.com/kyverno/kyverno/pkg/policymutation"
 
 	assertnew "github.com/stretchr/testify/assert"
@@ -23,37 +24,84 @@ func compareJSONAsMap(t *testing.T, expected, actual []byte) {
 	}
 }
 
-func TestGeneratePodControllerRule_NilAnnotation(t *testing.T) {
-	policyRaw := []byte(`{
-		"apiVersion": "kyverno.io/v1",
-		"kind": "ClusterPolicy",
-		"metadata": {
-		  "name": "add-safe-to-evict"
-		}
-	  }`)
-
+// expandPolicy adds the rules generated for the pod controllers to the policy, the way the policy cache does
+func expandPolicy(t *testing.T, policyRaw []byte) []byte {
 	var policy kyverno.ClusterPolicy
-	assert.Assert(t, json.Unmarshal(policyRaw, &policy))
-	patches, errs := policymutation.GeneratePodControllerRule(policy, log.Log)
-	assert.Assert(t, len(errs) == 0)
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
 
-	p, err := utils.ApplyPatches(policyRaw, patches)
+	expanded, err := json.Marshal(policymutation.ExpandPolicy(&policy, engine.PodControllers, log.Log))
 	assert.NilError(t, err)
+	return expanded
+}
 
-	expectedPolicy := []byte(`{
+// assertNoAutogenPatch checks that the defaults neither set the auto-gen annotation nor add rules to the policy,
+// the controllers are read from the configuration when the policy is expanded
+func assertNoAutogenPatch(t *testing.T, policyRaw []byte) {
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+
+	patches, _ := policymutation.GenerateJSONPatchesForDefaults(&policy, log.Log)
+	assert.Assert(t, !bytes.Contains(patches, []byte("autogen")), string(patches))
+}
+
+// expandedAnnotations returns the annotations of the expanded policy
+func expandedAnnotations(t *testing.T, policyRaw []byte) map[string]string {
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(expandPolicy(t, policyRaw), &policy))
+	return policy.GetAnnotations()
+}
+
+// comparePolicies compares the policies once decoded, as empty rule fields are encoded when a policy is marshaled
+func comparePolicies(t *testing.T, expected, actual []byte) {
+	var expectedPolicy, actualPolicy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(expected, &expectedPolicy))
+	assert.NilError(t, json.Unmarshal(actual, &actualPolicy))
+
+	if !assertnew.Equal(t, expectedPolicy, actualPolicy) {
+		t.FailNow()
+	}
+}
+
+func TestExpandPolicy_NilAnnotation(t *testing.T) {
+	policyRaw := []byte(`{
 		"apiVersion": "kyverno.io/v1",
 		"kind": "ClusterPolicy",
 		"metadata": {
-		  "name": "add-safe-to-evict",
-		  "annotations": {
-			"pod-policies.kyverno.io/autogen-controllers": "DaemonSet,Deployment,Job,StatefulSet,CronJob"
-		  }
+		  "name": "add-safe-to-evict"
+		},
+		"spec": {
+		  "rules": [
+			{
+			  "name": "validate-runAsNonRoot",
+			  "match": {
+				"resources": {
+				  "kinds": [
+					"Pod"
+				  ]
+				}
+			  },
+			  "validate": {
+				"message": "Running as root user is not allowed. Set runAsNonRoot to true",
+				"pattern": {
+				  "spec": {
+					"securityContext": {
+					  "runAsNonRoot": true
+					}
+				  }
+				}
+			  }
+			}
+		  ]
 		}
 	  }`)
-	compareJSONAsMap(t, p, expectedPolicy)
+
+	assertNoAutogenPatch(t, policyRaw)
+	assert.DeepEqual(t, expandedAnnotations(t, policyRaw), map[string]string{
+		"pod-policies.kyverno.io/autogen-controllers": "DaemonSet,Deployment,Job,StatefulSet,CronJob",
+	})
 }
 
-func TestGeneratePodControllerRule_PredefinedAnnotation(t *testing.T) {
+func TestExpandPolicy_PredefinedAnnotation(t *testing.T) {
 	policyRaw := []byte(`{
 		"apiVersion": "kyverno.io/v1",
 		"kind": "ClusterPolicy",
@@ -65,14 +113,11 @@ func TestGeneratePodControllerRule_PredefinedAnnotation(t *testing.T) {
 		}
 	  }`)
 
-	var policy kyverno.ClusterPolicy
-	assert.Assert(t, json.Unmarshal(policyRaw, &policy))
-	patches, errs := policymutation.GeneratePodControllerRule(policy, log.Log)
-	assert.Assert(t, len(errs) == 0)
-	assert.Assert(t, len(patches) == 0)
+	assertNoAutogenPatch(t, policyRaw)
+	comparePolicies(t, policyRaw, expandPolicy(t, policyRaw))
 }
 
-func TestGeneratePodControllerRule_DisableFeature(t *testing.T) {
+func TestExpandPolicy_DisableFeature(t *testing.T) {
 	policyRaw := []byte(`{
 		"apiVersion": "kyverno.io/v1",
 		"kind": "ClusterPolicy",
@@ -116,14 +161,11 @@ func TestGeneratePodControllerRule_DisableFeature(t *testing.T) {
 		}
 	  }`)
 
-	var policy kyverno.ClusterPolicy
-	assert.Assert(t, json.Unmarshal(policyRaw, &policy))
-	patches, errs := policymutation.GeneratePodControllerRule(policy, log.Log)
-	assert.Assert(t, len(errs) == 0)
-	assert.Assert(t, len(patches) == 0)
+	assertNoAutogenPatch(t, policyRaw)
+	comparePolicies(t, policyRaw, expandPolicy(t, policyRaw))
 }
 
-func TestGeneratePodControllerRule_Mutate(t *testing.T) {
+func TestExpandPolicy_Mutate(t *testing.T) {
 	policyRaw := []byte(`{
 		"apiVersion": "kyverno.io/v1",
 		"kind": "ClusterPolicy",
@@ -167,13 +209,8 @@ func TestGeneratePodControllerRule_Mutate(t *testing.T) {
 		}
 	  }`)
 
-	var policy kyverno.ClusterPolicy
-	assert.Assert(t, json.Unmarshal(policyRaw, &policy))
-	patches, errs := policymutation.GeneratePodControllerRule(policy, log.Log)
-	assert.Assert(t, len(errs) == 0)
-
-	p, err := utils.ApplyPatches(policyRaw, patches)
-	assert.NilError(t, err)
+	assertNoAutogenPatch(t, policyRaw)
+	p := expandPolicy(t, policyRaw)
 
 	expectedPolicy := []byte(`{
 		"apiVersion": "kyverno.io/v1",
@@ -287,9 +324,10 @@ func TestGeneratePodControllerRule_Mutate(t *testing.T) {
 		}
 	  }`)
 
-	compareJSONAsMap(t, expectedPolicy, p)
+	comparePolicies(t, expectedPolicy, p)
 }
-func TestGeneratePodControllerRule_ExistOtherAnnotation(t *testing.T) {
+
+func TestExpandPolicy_ExistOtherAnnotation(t *testing.T) {
 	policyRaw := []byte(`{
 		"apiVersion": "kyverno.io/v1",
 		"kind": "ClusterPolicy",
@@ -298,32 +336,41 @@ func TestGeneratePodControllerRule_ExistOtherAnnotation(t *testing.T) {
 		  "annotations": {
 			"test": "annotation"
 		  }
+		},
+		"spec": {
+		  "rules": [
+			{
+			  "name": "validate-runAsNonRoot",
+			  "match": {
+				"resources": {
+				  "kinds": [
+					"Pod"
+				  ]
+				}
+			  },
+			  "validate": {
+				"message": "Running as root user is not allowed. Set runAsNonRoot to true",
+				"pattern": {
+				  "spec": {
+					"securityContext": {
+					  "runAsNonRoot": true
+					}
+				  }
+				}
+			  }
+			}
+		  ]
 		}
 	  }`)
 
-	var policy kyverno.ClusterPolicy
-	assert.Assert(t, json.Unmarshal(policyRaw, &policy))
-	patches, errs := policymutation.GeneratePodControllerRule(policy, log.Log)
-	assert.Assert(t, len(errs) == 0)
-
-	p, err := utils.ApplyPatches(policyRaw, patches)
-	assert.NilError(t, err)
-
-	expectedPolicy := []byte(`{
-		"apiVersion": "kyverno.io/v1",
-		"kind": "ClusterPolicy",
-		"metadata": {
-		  "name": "add-safe-to-evict",
-		  "annotations": {
-			"pod-policies.kyverno.io/autogen-controllers": "DaemonSet,Deployment,Job,StatefulSet,CronJob",
-			"test": "annotation"
-		  }
-		}
-	  }`)
-	compareJSONAsMap(t, p, expectedPolicy)
+	assertNoAutogenPatch(t, policyRaw)
+	assert.DeepEqual(t, expandedAnnotations(t, policyRaw), map[string]string{
+		"pod-policies.kyverno.io/autogen-controllers": "DaemonSet,Deployment,Job,StatefulSet,CronJob",
+		"test": "annotation",
+	})
 }
 
-func TestGeneratePodControllerRule_ValidateAnyPattern(t *testing.T) {
+func TestExpandPolicy_ValidateAnyPattern(t *testing.T) {
 	policyRaw := []byte(`{
 		"apiVersion": "kyverno.io/v1",
 		"kind": "ClusterPolicy",
@@ -373,13 +420,8 @@ func TestGeneratePodControllerRule_ValidateAnyPattern(t *testing.T) {
 		}
 	  }`)
 
-	var policy kyverno.ClusterPolicy
-	assert.Assert(t, json.Unmarshal(policyRaw, &policy))
-	patches, errs := policymutation.GeneratePodControllerRule(policy, log.Log)
-	assert.Assert(t, len(errs) == 0)
-
-	p, err := utils.ApplyPatches(policyRaw, patches)
-	assert.NilError(t, err)
+	assertNoAutogenPatch(t, policyRaw)
+	p := expandPolicy(t, policyRaw)
 
 	expectedPolicy := []byte(`{
 		"apiVersion": "kyverno.io/v1",
@@ -471,10 +513,10 @@ func TestGeneratePodControllerRule_ValidateAnyPattern(t *testing.T) {
 		  ]
 		}
 	  }`)
-	compareJSONAsMap(t, p, expectedPolicy)
+	comparePolicies(t, p, expectedPolicy)
 }
 
-func TestGeneratePodControllerRule_ValidatePattern(t *testing.T) {
+func TestExpandPolicy_ValidatePattern(t *testing.T) {
 	policyRaw := []byte(`{
 		"apiVersion": "kyverno.io/v1",
 		"kind": "ClusterPolicy",
@@ -511,14 +553,8 @@ func TestGeneratePodControllerRule_ValidatePattern(t *testing.T) {
 		}
 	  }`)
 
-	var policy kyverno.ClusterPolicy
-	// var policy, generatePolicy unstructured.Unstructured
-	assert.Assert(t, json.Unmarshal(policyRaw, &policy))
-	patches, errs := policymutation.GeneratePodControllerRule(policy, log.Log)
-	assert.Assert(t, len(errs) == 0)
-
-	p, err := utils.ApplyPatches(policyRaw, patches)
-	assert.NilError(t, err)
+	assertNoAutogenPatch(t, policyRaw)
+	p := expandPolicy(t, policyRaw)
 
 	expectedPolicy := []byte(`{
 		"apiVersion": "kyverno.io/v1",
@@ -622,5 +658,5 @@ func TestGeneratePodControllerRule_ValidatePattern(t *testing.T) {
 		}
 	  }`)
 
-	compareJSONAsMap(t, expectedPolicy, p)
+	comparePolicies(t, expectedPolicy, p)
 }
@@ -1,16 +1,21 @@
 package webhooks
 
 import (
+	"strings"
+
 	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	kyvernov1alpha1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1alpha1"
 	"github.com/kyverno/kyverno/pkg/engine/response"
-	"strings"
-
 	"github.com/kyverno/kyverno/pkg/event"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 //generateEvents generates event info for the engine responses
-func generateEvents(engineResponses []*response.EngineResponse, blocked, onUpdate bool, log logr.Logger) []event.Info {
+// successReason is the reason of the events for the policies that applied successfully, a mutate policy that
+// did not change the resource is reported with PolicyApplied instead of ResourceMutated.
+// The events are filtered by the event generator with the event annotations of each policy.
+func generateEvents(engineResponses []*response.EngineResponse, blocked, onUpdate bool, successReason event.Reason, log logr.Logger) []event.Info {
 	var events []event.Info
 
 	// - Admission-Response is SUCCESS
@@ -20,44 +25,28 @@ func generateEvents(engineResponses []*response.EngineResponse, blocked, onUpdat
 	//   - Some/All policies succeeded
 	//     - report success event on policy
 	//     - report success event on resource
+	//   - No rule of the policy applied
+	//     - report skip event on policy
+	//     - report skip event on resource
 
 	for _, er := range engineResponses {
+		if len(er.PolicyResponse.Rules) == 0 {
+			pe := newPolicyEvent(log, er, event.PolicySkipped, event.SkipPolicyApply, er.PolicyResponse.Resource.GetKey())
+			re := newResourceEvent(log, er, event.PolicySkipped, event.SkipResourcePolicyApply, er.PolicyResponse.Policy.Name)
+			events = append(events, pe, re)
+			continue
+		}
+
 		if !er.IsSuccessful() {
 			// Rules that failed
 			failedRules := er.GetFailedRules()
 			failedRulesStr := strings.Join(failedRules, ";")
 
 			// Event on the policy
-			kind := "ClusterPolicy"
-			if er.PolicyResponse.Policy.Namespace != "" {
-				kind = "Policy"
-			}
-			pe := event.NewEvent(
-				log,
-				kind,
-				kyvernov1alpha1.SchemeGroupVersion.String(),
-				er.PolicyResponse.Policy.Namespace,
-				er.PolicyResponse.Policy.Name,
-				event.PolicyViolation.String(),
-				event.AdmissionController,
-				event.FPolicyApply,
-				failedRulesStr,
-				er.PolicyResponse.Resource.GetKey(),
-			)
+			pe := newPolicyEvent(log, er, event.PolicyViolation, event.FPolicyApply, failedRulesStr, er.PolicyResponse.Resource.GetKey())
 
 			// Event on the resource
-			re := event.NewEvent(
-				log,
-				er.PolicyResponse.Resource.Kind,
-				er.PolicyResponse.Resource.APIVersion,
-				er.PolicyResponse.Resource.Namespace,
-				er.PolicyResponse.Resource.Name,
-				event.PolicyViolation.String(),
-				event.AdmissionController,
-				event.FResourcePolicyApply,
-				failedRulesStr,
-				er.PolicyResponse.Policy.Name,
-			)
+			re := newResourceEvent(log, er, event.PolicyViolation, event.FResourcePolicyApply, failedRulesStr, er.PolicyResponse.Policy.Name)
 			events = append(events, pe, re)
 		}
 
@@ -65,25 +54,73 @@ func generateEvents(engineResponses []*response.EngineResponse, blocked, onUpdat
 			successRules := er.GetSuccessRules()
 			successRulesStr := strings.Join(successRules, ";")
 
-			// Event on the policy
-			kind := "ClusterPolicy"
-			if er.PolicyResponse.Policy.Namespace != "" {
-				kind = "Policy"
+			reason := successReason
+			if reason == event.ResourceMutated && len(er.GetPatches()) == 0 {
+				reason = event.PolicyApplied
 			}
-			e := event.NewEvent(
-				log,
-				kind,
-				kyvernov1alpha1.SchemeGroupVersion.String(),
-				er.PolicyResponse.Policy.Namespace,
-				er.PolicyResponse.Policy.Name,
-				event.PolicyApplied.String(),
-				event.AdmissionController,
-				event.SPolicyApply,
-				successRulesStr,
-				er.PolicyResponse.Resource.GetKey(),
-			)
-			events = append(events, e)
+
+			// Event on the policy
+			pe := newPolicyEvent(log, er, reason, event.SPolicyApply, successRulesStr, er.PolicyResponse.Resource.GetKey())
+
+			// Event on the resource
+			re := newResourceEvent(log, er, reason, event.SResourcePolicyApply, successRulesStr, er.PolicyResponse.Policy.Name)
+			events = append(events, pe, re)
 		}
 	}
 	return events
 }
+
+// generateErrorEvents generates the events for a policy that could not be applied to the resource
+func generateErrorEvents(policy *kyverno.ClusterPolicy, resource unstructured.Unstructured, err error, log logr.Logger) []event.Info {
+	er := &response.EngineResponse{
+		PolicyResponse: response.PolicyResponse{
+			Policy: response.PolicySpec{Name: policy.GetName(), Namespace: policy.GetNamespace()},
+			Resource: response.ResourceSpec{
+				Kind:       resource.GetKind(),
+				APIVersion: resource.GetAPIVersion(),
+				Namespace:  resource.GetNamespace(),
+				Name:       resource.GetName(),
+			},
+		},
+	}
+
+	pe := newPolicyEvent(log, er, event.PolicyError, event.EPolicyApply, er.PolicyResponse.Resource.GetKey(), err)
+	re := newResourceEvent(log, er, event.PolicyError, event.EResourcePolicyApply, er.PolicyResponse.Policy.Name, err)
+	return []event.Info{pe, re}
+}
+
+func newPolicyEvent(log logr.Logger, er *response.EngineResponse, reason event.Reason, message event.MsgKey, args ...interface{}) event.Info {
+	kind := "ClusterPolicy"
+	if er.PolicyResponse.Policy.Namespace != "" {
+		kind = "Policy"
+	}
+
+	return event.NewEvent(
+		log,
+		kind,
+		kyvernov1alpha1.SchemeGroupVersion.String(),
+		er.PolicyResponse.Policy.Namespace,
+		er.PolicyResponse.Policy.Name,
+		reason.String(),
+		event.AdmissionController,
+		message,
+		args...,
+	)
+}
+
+func newResourceEvent(log logr.Logger, er *response.EngineResponse, reason event.Reason, message event.MsgKey, args ...interface{}) event.Info {
+	e := event.NewEvent(
+		log,
+		er.PolicyResponse.Resource.Kind,
+		er.PolicyResponse.Resource.APIVersion,
+		er.PolicyResponse.Resource.Namespace,
+		er.PolicyResponse.Resource.Name,
+		reason.String(),
+		event.AdmissionController,
+		message,
+		args...,
+	)
+	e.PolicyName = er.PolicyResponse.Policy.Name
+	e.PolicyNamespace = er.PolicyResponse.Policy.Namespace
+	return e
+}
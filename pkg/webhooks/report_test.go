@@ -0,0 +1,51 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/event"
+	"gotest.tools/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func newTestEngineResponse(policy string, rules ...response.RuleResponse) *response.EngineResponse {
+	return &response.EngineResponse{
+		PolicyResponse: response.PolicyResponse{
+			Policy:   response.PolicySpec{Name: policy},
+			Resource: response.ResourceSpec{Kind: "Pod", APIVersion: "v1", Namespace: "default", Name: "nginx"},
+			Rules:    rules,
+		},
+	}
+}
+
+func Test_GenerateEvents(t *testing.T) {
+	engineResponses := []*response.EngineResponse{
+		newTestEngineResponse("add-labels", response.RuleResponse{Name: "add-team", Success: true, Patches: [][]byte{[]byte(`{"op":"add","path":"/metadata/labels/team","value":"dev"}`)}}),
+		newTestEngineResponse("add-defaults", response.RuleResponse{Name: "add-sidecar", Success: true}),
+		newTestEngineResponse("require-labels", response.RuleResponse{Name: "check-team", Success: false}),
+		newTestEngineResponse("no-match"),
+	}
+
+	events := generateEvents(engineResponses, false, false, event.ResourceMutated, log.Log)
+	assert.Equal(t, len(events), 8)
+
+	reasons := map[string]string{}
+	for _, e := range events {
+		if e.Kind == "ClusterPolicy" {
+			reasons[e.Name] = e.Reason
+			continue
+		}
+
+		assert.Equal(t, e.Name, "nginx")
+		assert.Assert(t, e.PolicyName != "")
+		assert.Equal(t, e.Reason, reasons[e.PolicyName])
+	}
+
+	assert.DeepEqual(t, reasons, map[string]string{
+		"add-labels":     event.ResourceMutated.String(),
+		"add-defaults":   event.PolicyApplied.String(),
+		"require-labels": event.PolicyViolation.String(),
+		"no-match":       event.PolicySkipped.String(),
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/julienschmidt/httprouter"
 	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/auditlog"
 	kyvernoclient "github.com/kyverno/kyverno/pkg/client/clientset/versioned"
 	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v1"
 	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
@@ -30,12 +31,13 @@ import (
 	"github.com/kyverno/kyverno/pkg/policycache"
 	"github.com/kyverno/kyverno/pkg/policyreport"
 	"github.com/kyverno/kyverno/pkg/resourcecache"
-	tlsutils "github.com/kyverno/kyverno/pkg/tls"
+	"github.com/kyverno/kyverno/pkg/tracing"
 	userinfo "github.com/kyverno/kyverno/pkg/userinfo"
 	"github.com/kyverno/kyverno/pkg/utils"
 	"github.com/kyverno/kyverno/pkg/webhookconfig"
 	webhookgenerate "github.com/kyverno/kyverno/pkg/webhooks/generate"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	v1beta1 "k8s.io/api/admission/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	informers "k8s.io/client-go/informers/core/v1"
@@ -128,6 +130,12 @@ type WebhookServer struct {
 	grController *generate.Controller
 
 	promConfig *metrics.PromConfig
+
+	// validationWorkers is the maximum number of policies evaluated concurrently for a validation request
+	validationWorkers int
+
+	// auditLog records the admission decisions, it is nil when the audit log is disabled
+	auditLog auditlog.Interface
 }
 
 // NewWebhookServer creates new instance of WebhookServer accordingly to given configuration
@@ -135,7 +143,7 @@ type WebhookServer struct {
 func NewWebhookServer(
 	kyvernoClient *kyvernoclient.Clientset,
 	client *client.Client,
-	tlsPair *tlsutils.PemPair,
+	getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error),
 	grInformer kyvernoinformer.GenerateRequestInformer,
 	pInformer kyvernoinformer.ClusterPolicyInformer,
 	rbInformer rbacinformer.RoleBindingInformer,
@@ -157,18 +165,18 @@ func NewWebhookServer(
 	resCache resourcecache.ResourceCache,
 	grc *generate.Controller,
 	promConfig *metrics.PromConfig,
+	validationWorkers int,
+	auditLog auditlog.Interface,
 ) (*WebhookServer, error) {
 
-	if tlsPair == nil {
+	if getCertificate == nil {
 		return nil, errors.New("NewWebhookServer is not initialized properly")
 	}
 
-	var tlsConfig tls.Config
-	pair, err := tls.X509KeyPair(tlsPair.Certificate, tlsPair.PrivateKey)
-	if err != nil {
-		return nil, err
+	// the certificate is read on each TLS handshake, so that a renewed certificate is served without a restart
+	tlsConfig := tls.Config{
+		GetCertificate: getCertificate,
 	}
-	tlsConfig.Certificates = []tls.Certificate{pair}
 
 	ws := &WebhookServer{
 		client:         client,
@@ -202,11 +210,15 @@ func NewWebhookServer(
 		openAPIController: openAPIController,
 		resCache:          resCache,
 		promConfig:        promConfig,
+		validationWorkers: validationWorkers,
+		auditLog:          auditLog,
 	}
 
 	mux := httprouter.New()
 	mux.HandlerFunc("POST", config.MutatingWebhookServicePath, ws.handlerFunc(ws.resourceMutation, true))
 	mux.HandlerFunc("POST", config.ValidatingWebhookServicePath, ws.handlerFunc(ws.resourceValidation, true))
+	mux.HandlerFunc("POST", config.MutatingWebhookServicePath+"/:failurePolicy/:timeoutSeconds", ws.handlerFunc(ws.resourceMutation, true))
+	mux.HandlerFunc("POST", config.ValidatingWebhookServicePath+"/:failurePolicy/:timeoutSeconds", ws.handlerFunc(ws.resourceValidation, true))
 	mux.HandlerFunc("POST", config.PolicyMutatingWebhookServicePath, ws.handlerFunc(ws.policyMutation, true))
 	mux.HandlerFunc("POST", config.PolicyValidatingWebhookServicePath, ws.handlerFunc(ws.policyValidation, true))
 	mux.HandlerFunc("POST", config.VerifyMutatingWebhookServicePath, ws.handlerFunc(ws.verifyHandler, false))
@@ -241,18 +253,32 @@ func NewWebhookServer(
 	return ws, nil
 }
 
-func (ws *WebhookServer) handlerFunc(handler func(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse, filter bool) http.HandlerFunc {
+func (ws *WebhookServer) handlerFunc(handler func(ctx context.Context, request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse, filter bool) http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
 		startTime := time.Now()
 		ws.webhookMonitor.SetTime(startTime)
 
+		groupCtx, err := withWebhookGroup(r.Context())
+		if err != nil {
+			ws.log.Info("invalid webhook path", "path", r.URL.Path, "reason", err.Error())
+			http.Error(rw, err.Error(), http.StatusNotFound)
+			return
+		}
+
 		admissionReview := ws.bodyToAdmissionReview(r, rw)
 		if admissionReview == nil {
 			ws.log.Info("failed to parse admission review request", "request", r)
 			return
 		}
 
-		logger := ws.log.WithName("handlerFunc").WithValues("kind", admissionReview.Request.Kind, "namespace", admissionReview.Request.Namespace,
+		request := admissionReview.Request
+		ctx, span := tracing.StartSpan(groupCtx, "webhook "+r.URL.Path,
+			append(tracing.ResourceAttributes(request.Kind.Kind, request.Namespace, request.Name),
+				tracing.OperationKey.String(string(request.Operation)),
+				tracing.RequestUIDKey.String(string(request.UID)))...)
+		defer span.End()
+
+		logger := tracing.Logger(ctx, ws.log.WithName("handlerFunc")).WithValues("kind", admissionReview.Request.Kind, "namespace", admissionReview.Request.Namespace,
 			"name", admissionReview.Request.Name, "operation", admissionReview.Request.Operation, "uid", admissionReview.Request.UID)
 
 		admissionReview.Response = &v1beta1.AdmissionResponse{
@@ -261,13 +287,14 @@ func (ws *WebhookServer) handlerFunc(handler func(request *v1beta1.AdmissionRequ
 		}
 
 		// Do not process the admission requests for kinds that are in filterKinds for filtering
-		request := admissionReview.Request
 		if filter && ws.configHandler.ToFilter(request.Kind.Kind, request.Namespace, request.Name) {
+			span.SetAttributes(tracing.ResultKey.String("filtered"))
 			writeResponse(rw, admissionReview)
 			return
 		}
 
-		admissionReview.Response = handler(request)
+		admissionReview.Response = handler(ctx, request)
+		span.SetAttributes(attribute.Bool("kyverno.response.allowed", admissionReview.Response.Allowed))
 		writeResponse(rw, admissionReview)
 		logger.V(4).Info("admission review request processed", "time", time.Since(startTime).String())
 
@@ -289,23 +316,32 @@ func writeResponse(rw http.ResponseWriter, admissionReview *v1beta1.AdmissionRev
 }
 
 // resourceMutation mutates resource
-func (ws *WebhookServer) resourceMutation(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
-	logger := ws.log.WithName("MutateWebhook").WithValues("uid", request.UID, "kind", request.Kind.Kind, "namespace", request.Namespace, "name", request.Name, "operation", request.Operation, "gvk", request.Kind.String())
+func (ws *WebhookServer) resourceMutation(ctx context.Context, request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+	logger := tracing.Logger(ctx, ws.log.WithName("MutateWebhook")).WithValues("uid", request.UID, "kind", request.Kind.Kind, "namespace", request.Namespace, "name", request.Name, "operation", request.Operation, "gvk", request.Kind.String())
 
 	if excludeKyvernoResources(request.Kind.Kind) {
 		return successResponse(nil)
 	}
 
 	logger.V(4).Info("received an admission request in mutating webhook")
-	requestTime := time.Now().Unix()
+	startTime := time.Now()
+	requestTime := startTime.Unix()
 
-	mutatePolicies := ws.pCache.GetPolicies(policycache.Mutate, request.Kind.Kind, request.Namespace)
-	generatePolicies := ws.pCache.GetPolicies(policycache.Generate, request.Kind.Kind, request.Namespace)
-	verifyImagesPolicies := ws.pCache.GetPolicies(policycache.VerifyImages, request.Kind.Kind, request.Namespace)
+	subresource := getSubresource(request, ws.client, logger)
+	kind := getPolicyCacheKind(request, subresource)
+	webhookGroups := ws.webhookRegister.MutateWebhookGroups()
+	mutatePolicies := ws.filterWebhookGroup(ctx, ws.pCache.GetPolicies(policycache.Mutate, kind, request.Namespace), webhookGroups)
+	verifyImagesPolicies := ws.filterWebhookGroup(ctx, ws.pCache.GetPolicies(policycache.VerifyImages, kind, request.Namespace), webhookGroups)
+
+	// generate requests are created asynchronously, only once by the default webhook
+	var generatePolicies []*v1.ClusterPolicy
+	if isDefaultWebhookGroup(ctx) {
+		generatePolicies = ws.pCache.GetPolicies(policycache.Generate, kind, request.Namespace)
+	}
 
 	if len(mutatePolicies) == 0 && len(generatePolicies) == 0 && len(verifyImagesPolicies) == 0 {
 		logger.V(4).Info("no policies matched admission request")
-		if request.Operation == v1beta1.Update {
+		if request.Operation == v1beta1.Update && isDefaultWebhookGroup(ctx) {
 			// handle generate source resource updates
 			go ws.handleUpdatesForGenerateRules(request, []*v1.ClusterPolicy{})
 		}
@@ -314,26 +350,39 @@ func (ws *WebhookServer) resourceMutation(request *v1beta1.AdmissionRequest) *v1
 	}
 
 	addRoles := containsRBACInfo(mutatePolicies, generatePolicies)
-	policyContext, err := ws.buildPolicyContext(request, addRoles)
+	policyContext, err := ws.buildPolicyContext(ctx, request, addRoles)
 	if err != nil {
 		logger.Error(err, "failed to build policy context")
 		return failureResponse(err.Error())
 	}
 
-	mutatePatches := ws.applyMutatePolicies(request, policyContext, mutatePolicies, requestTime, logger)
+	policyContext.Subresource = subresource
+
+	mutatePatches, mutateEngineResponses, warnings := ws.applyMutatePolicies(request, policyContext, mutatePolicies, requestTime, logger)
 
 	newRequest := patchRequest(mutatePatches, request, logger)
-	imagePatches, err := ws.applyImageVerifyPolicies(newRequest, policyContext, verifyImagesPolicies, logger)
+	imagePatches, imageEngineResponses, err := ws.applyImageVerifyPolicies(newRequest, policyContext, verifyImagesPolicies, logger)
+	engineResponses := append(mutateEngineResponses, imageEngineResponses...)
 	if err != nil {
 		logger.Error(err, "image verification failed")
-		return failureResponse(err.Error())
+		return ws.recordAdmission("mutate", request, failureResponse(err.Error()), engineResponses, startTime)
 	}
 
 	newRequest = patchRequest(imagePatches, newRequest, logger)
 	ws.applyGeneratePolicies(newRequest, policyContext, generatePolicies, requestTime, logger)
 
 	var patches = append(mutatePatches, imagePatches...)
-	return successResponse(patches)
+	return ws.recordAdmission("mutate", request, ws.successResponseWithWarnings(patches, warnings), engineResponses, startTime)
+}
+
+// recordAdmission adds the admission decision to the audit log, if enabled, and returns the admission response
+func (ws *WebhookServer) recordAdmission(webhook string, request *v1beta1.AdmissionRequest, admissionResponse *v1beta1.AdmissionResponse,
+	engineResponses []*response.EngineResponse, startTime time.Time) *v1beta1.AdmissionResponse {
+	if ws.auditLog != nil && len(engineResponses) != 0 {
+		ws.auditLog.Add(auditlog.NewRecord(webhook, request, admissionResponse, engineResponses, startTime))
+	}
+
+	return admissionResponse
 }
 
 // patchRequest applies patches to the request.Object and returns a new copy of the request
@@ -344,13 +393,13 @@ func patchRequest(patches []byte, request *v1beta1.AdmissionRequest, logger logr
 	return newRequest
 }
 
-func (ws *WebhookServer) buildPolicyContext(request *v1beta1.AdmissionRequest, addRoles bool) (*engine.PolicyContext, error) {
+func (ws *WebhookServer) buildPolicyContext(traceCtx context.Context, request *v1beta1.AdmissionRequest, addRoles bool) (*engine.PolicyContext, error) {
 	userRequestInfo := v1.RequestInfo{
 		AdmissionUserInfo: *request.UserInfo.DeepCopy(),
 	}
 
 	if addRoles {
-		if roles, clusterRoles, err := userinfo.GetRoleRef(ws.rbLister, ws.crbLister, request, ws.configHandler); err != nil {
+		if roles, clusterRoles, err := ws.getRoleRef(traceCtx, request); err != nil {
 			return nil, errors.Wrap(err, "failed to fetch RBAC information for request")
 		} else {
 			userRequestInfo.Roles = roles
@@ -385,6 +434,7 @@ func (ws *WebhookServer) buildPolicyContext(request *v1beta1.AdmissionRequest, a
 		ResourceCache:       ws.resCache,
 		JSONContext:         ctx,
 		Client:              ws.client,
+		TraceContext:        traceCtx,
 	}
 
 	if request.Operation == v1beta1.Update {
@@ -394,6 +444,16 @@ func (ws *WebhookServer) buildPolicyContext(request *v1beta1.AdmissionRequest, a
 	return policyContext, nil
 }
 
+// getRoleRef fetches the roles and cluster roles bound to the requesting user
+func (ws *WebhookServer) getRoleRef(ctx context.Context, request *v1beta1.AdmissionRequest) (roles, clusterRoles []string, err error) {
+	_, span := tracing.StartSpan(ctx, "userinfo.GetRoleRef")
+	defer span.End()
+
+	roles, clusterRoles, err = userinfo.GetRoleRef(ws.rbLister, ws.crbLister, request, ws.configHandler)
+	tracing.SetError(span, err)
+	return roles, clusterRoles, err
+}
+
 func successResponse(patch []byte) *v1beta1.AdmissionResponse {
 	r := &v1beta1.AdmissionResponse{
 		Allowed: true,
@@ -411,6 +471,14 @@ func successResponse(patch []byte) *v1beta1.AdmissionResponse {
 	return r
 }
 
+// successResponseWithWarnings returns a success response with the admission warnings,
+// limited by the number and size of warnings set in the configuration
+func (ws *WebhookServer) successResponseWithWarnings(patch []byte, warnings []string) *v1beta1.AdmissionResponse {
+	r := successResponse(patch)
+	r.Warnings = limitWarnings(warnings, ws.configHandler.GetMaxAdmissionWarnings(), ws.configHandler.GetMaxAdmissionWarningSize())
+	return r
+}
+
 func errorResponse(logger logr.Logger, err error, message string) *v1beta1.AdmissionResponse {
 	logger.Error(err, message)
 	return &v1beta1.AdmissionResponse{
@@ -482,9 +550,9 @@ func registerAdmissionRequestsMetricGenerate(logger logr.Logger, promMetrics met
 	}
 }
 
-func (ws *WebhookServer) resourceValidation(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
-	logger := ws.log.WithName("ValidateWebhook").WithValues("uid", request.UID, "kind", request.Kind.Kind, "namespace", request.Namespace, "name", request.Name, "operation", request.Operation)
-	if request.Operation == v1beta1.Delete {
+func (ws *WebhookServer) resourceValidation(traceCtx context.Context, request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+	logger := tracing.Logger(traceCtx, ws.log.WithName("ValidateWebhook")).WithValues("uid", request.UID, "kind", request.Kind.Kind, "namespace", request.Namespace, "name", request.Name, "operation", request.Operation)
+	if request.Operation == v1beta1.Delete && isDefaultWebhookGroup(traceCtx) {
 		ws.handleDelete(request)
 	}
 
@@ -494,17 +562,27 @@ func (ws *WebhookServer) resourceValidation(request *v1beta1.AdmissionRequest) *
 
 	logger.V(6).Info("received an admission request in validating webhook")
 	// timestamp at which this admission request got triggered
-	admissionRequestTimestamp := time.Now().Unix()
+	startTime := time.Now()
+	admissionRequestTimestamp := startTime.Unix()
 
-	policies := ws.pCache.GetPolicies(policycache.ValidateEnforce, request.Kind.Kind, "")
+	subresource := getSubresource(request, ws.client, logger)
+	kind := getPolicyCacheKind(request, subresource)
+	policies := ws.pCache.GetPolicies(policycache.ValidateEnforce, kind, "")
 	// Get namespace policies from the cache for the requested resource namespace
-	nsPolicies := ws.pCache.GetPolicies(policycache.ValidateEnforce, request.Kind.Kind, request.Namespace)
-	policies = append(policies, nsPolicies...)
+	nsPolicies := ws.pCache.GetPolicies(policycache.ValidateEnforce, kind, request.Namespace)
+	webhookGroups := ws.webhookRegister.ValidateWebhookGroups()
+	policies = ws.filterWebhookGroup(traceCtx, append(policies, nsPolicies...), webhookGroups)
+
+	// audit policies that emit warnings are evaluated before responding, the other audit policies are
+	// evaluated in the background by the audit handler which skips the policies with warnings
+	warnPolicies := ws.pCache.GetPolicies(policycache.ValidateAudit, kind, "")
+	warnPolicies = append(warnPolicies, ws.pCache.GetPolicies(policycache.ValidateAudit, kind, request.Namespace)...)
+	warnPolicies = filterPoliciesWithWarnings(ws.filterWebhookGroup(traceCtx, warnPolicies, webhookGroups))
 
 	var roles, clusterRoles []string
-	if containsRBACInfo(policies) {
+	if containsRBACInfo(policies, warnPolicies) {
 		var err error
-		roles, clusterRoles, err = userinfo.GetRoleRef(ws.rbLister, ws.crbLister, request, ws.configHandler)
+		roles, clusterRoles, err = ws.getRoleRef(traceCtx, request)
 		if err != nil {
 			return errorResponse(logger, err, "failed to fetch RBAC data")
 		}
@@ -531,6 +609,9 @@ func (ws *WebhookServer) resourceValidation(request *v1beta1.AdmissionRequest) *
 		return errorResponse(logger, err, "failed create parse resource")
 	}
 
+	policies = filterPoliciesByValidationFailureAction(policies, common.Enforce, resourceForRequest(newResource, oldResource), namespaceLabels)
+	warnPolicies = filterPoliciesByValidationFailureAction(warnPolicies, common.Audit, resourceForRequest(newResource, oldResource), namespaceLabels)
+
 	if err := ctx.AddImageInfo(&newResource); err != nil {
 		return errorResponse(logger, err, "failed add image information to policy rule context")
 	}
@@ -544,24 +625,31 @@ func (ws *WebhookServer) resourceValidation(request *v1beta1.AdmissionRequest) *
 		ResourceCache:       ws.resCache,
 		JSONContext:         ctx,
 		Client:              ws.client,
+		Subresource:         subresource,
+		TraceContext:        traceCtx,
 	}
 
 	vh := &validationHandler{
 		log:         ws.log,
 		eventGen:    ws.eventGen,
 		prGenerator: ws.prGenerator,
+		workers:     ws.validationWorkers,
 	}
 
-	ok, msg := vh.handleValidation(ws.promConfig, request, policies, policyContext, namespaceLabels, admissionRequestTimestamp)
+	ok, msg, engineResponses := vh.handleValidation(ws.promConfig, request, policies, policyContext, namespaceLabels, admissionRequestTimestamp)
 	if !ok {
 		logger.Info("admission request denied")
-		return failureResponse(msg)
+		return ws.recordAdmission("validate", request, failureResponse(msg), engineResponses, startTime)
 	}
 
+	warnings := vh.getAuditWarnings(ws.promConfig, request, warnPolicies, policyContext, namespaceLabels)
+
 	// push admission request to audit handler, this won't block the admission request
-	ws.auditHandler.Add(request.DeepCopy())
+	if isDefaultWebhookGroup(traceCtx) {
+		ws.auditHandler.Add(request.DeepCopy())
+	}
 
-	return successResponse(nil)
+	return ws.recordAdmission("validate", request, ws.successResponseWithWarnings(nil, warnings), engineResponses, startTime)
 }
 
 // RunAsync TLS server in separate thread and returns control immediately
@@ -1,6 +1,8 @@
 package webhooks
 
 import (
+	"context"
+
 	"github.com/kyverno/kyverno/pkg/engine"
 	"github.com/kyverno/kyverno/pkg/utils"
 	"strings"
@@ -19,6 +21,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/policycache"
 	"github.com/kyverno/kyverno/pkg/policyreport"
 	"github.com/kyverno/kyverno/pkg/resourcecache"
+	"github.com/kyverno/kyverno/pkg/tracing"
 	"github.com/kyverno/kyverno/pkg/userinfo"
 	"k8s.io/api/admission/v1beta1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -147,18 +150,35 @@ func (h *auditHandler) processNextWorkItem() bool {
 	return true
 }
 
-func (h *auditHandler) process(request *v1beta1.AdmissionRequest) error {
+func (h *auditHandler) process(request *v1beta1.AdmissionRequest) (err error) {
 	var roles, clusterRoles []string
-	var err error
 	// time at which the corresponding the admission request's processing got initiated
 	admissionRequestTimestamp := time.Now().Unix()
-	logger := h.log.WithName("process")
+	traceCtx, span := tracing.StartSpan(context.Background(), "audit.process",
+		append(tracing.ResourceAttributes(request.Kind.Kind, request.Namespace, request.Name),
+			tracing.OperationKey.String(string(request.Operation)),
+			tracing.RequestUIDKey.String(string(request.UID)))...)
+	defer func() {
+		tracing.SetError(span, err)
+		span.End()
+	}()
 
-	policies := h.pCache.GetPolicies(policycache.ValidateAudit, request.Kind.Kind, request.Namespace)
+	logger := tracing.Logger(traceCtx, h.log.WithName("process"))
+
+	subresource := getSubresource(request, h.client, logger)
+	policies := h.pCache.GetPolicies(policycache.ValidateAudit, getPolicyCacheKind(request, subresource), request.Namespace)
+	// the policies that emit warnings are evaluated by the validating webhook before it responds,
+	// the deleted resources are removed from the reports of all the audit policies
+	if request.Operation != v1beta1.Delete {
+		policies = filterPoliciesWithoutWarnings(policies)
+	}
 
 	// getRoleRef only if policy has roles/clusterroles defined
 	if containsRBACInfo(policies) {
+		_, rbacSpan := tracing.StartSpan(traceCtx, "userinfo.GetRoleRef")
 		roles, clusterRoles, err = userinfo.GetRoleRef(h.rbLister, h.crbLister, request, h.configHandler)
+		tracing.SetError(rbacSpan, err)
+		rbacSpan.End()
 		if err != nil {
 			logger.Error(err, "failed to get RBAC information for request")
 		}
@@ -184,6 +204,8 @@ func (h *auditHandler) process(request *v1beta1.AdmissionRequest) error {
 		return errors.Wrap(err, "failed create parse resource")
 	}
 
+	policies = filterPoliciesByValidationFailureAction(policies, common.Audit, resourceForRequest(newResource, oldResource), namespaceLabels)
+
 	if err := ctx.AddImageInfo(&newResource); err != nil {
 		return errors.Wrap(err, "failed add image information to policy rule context\"")
 	}
@@ -197,6 +219,8 @@ func (h *auditHandler) process(request *v1beta1.AdmissionRequest) error {
 		ResourceCache:       h.resCache,
 		JSONContext:         ctx,
 		Client:              h.client,
+		Subresource:         subresource,
+		TraceContext:        traceCtx,
 	}
 
 	vh := &validationHandler{
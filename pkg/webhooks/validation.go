@@ -2,6 +2,7 @@ package webhooks
 
 import (
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/kyverno/kyverno/pkg/event"
@@ -25,64 +26,35 @@ type validationHandler struct {
 	log         logr.Logger
 	eventGen    event.Interface
 	prGenerator policyreport.GeneratorInterface
+
+	// workers is the maximum number of policies evaluated concurrently
+	workers int
 }
 
 // handleValidation handles validating webhook admission request
 // If there are no errors in validating rule we apply generation rules
 // patchedResource is the (resource + patches) after applying mutation rules
+// return value: allowed, denial message, engine responses of the evaluated policies
 func (v *validationHandler) handleValidation(
 	promConfig *metrics.PromConfig,
 	request *v1beta1.AdmissionRequest,
 	policies []*v1.ClusterPolicy,
 	policyContext *engine.PolicyContext,
 	namespaceLabels map[string]string,
-	admissionRequestTimestamp int64) (bool, string) {
+	admissionRequestTimestamp int64) (bool, string, []*response.EngineResponse) {
 
 	if len(policies) == 0 {
-		return true, ""
+		return true, "", nil
 	}
 
 	resourceName := getResourceName(request)
 	logger := v.log.WithValues("action", "validate", "resource", resourceName, "operation", request.Operation, "gvk", request.Kind.String())
 
-	var deletionTimeStamp *metav1.Time
-	if reflect.DeepEqual(policyContext.NewResource, unstructured.Unstructured{}) {
-		deletionTimeStamp = policyContext.NewResource.GetDeletionTimestamp()
-	} else {
-		deletionTimeStamp = policyContext.OldResource.GetDeletionTimestamp()
+	if isDeletingOnUpdate(request, policyContext) {
+		return true, "", nil
 	}
 
-	if deletionTimeStamp != nil && request.Operation == v1beta1.Update {
-		return true, ""
-	}
-
-	var engineResponses []*response.EngineResponse
-	for _, policy := range policies {
-		logger.V(3).Info("evaluating policy", "policy", policy.Name)
-		policyContext.Policy = *policy
-		policyContext.NamespaceLabels = namespaceLabels
-		engineResponse := engine.Validate(policyContext)
-		if reflect.DeepEqual(engineResponse, response.EngineResponse{}) {
-			// we get an empty response if old and new resources created the same response
-			// allow updates if resource update doesnt change the policy evaluation
-			continue
-		}
-
-		// registering the kyverno_policy_results_total metric concurrently
-		go registerPolicyResultsMetricValidation(promConfig, logger, string(request.Operation), policyContext.Policy, *engineResponse)
-		// registering the kyverno_policy_execution_duration_seconds metric concurrently
-		go registerPolicyExecutionDurationMetricValidate(promConfig, logger, string(request.Operation), policyContext.Policy, *engineResponse)
-
-		engineResponses = append(engineResponses, engineResponse)
-		if !engineResponse.IsSuccessful() {
-			logger.V(2).Info("validation failed", "policy", policy.Name, "failed rules", engineResponse.GetFailedRules())
-			continue
-		}
-
-		if len(engineResponse.GetSuccessRules()) > 0 {
-			logger.V(2).Info("validation passed", "policy", policy.Name)
-		}
-	}
+	engineResponses := v.validatePolicies(promConfig, request, policies, policyContext, namespaceLabels, logger)
 
 	// If Validation fails then reject the request
 	// no violations will be created on "enforce"
@@ -98,7 +70,7 @@ func (v *validationHandler) handleValidation(
 	// Scenario 3:
 	//   all policies were applied successfully.
 	//   create an event on the resource
-	events := generateEvents(engineResponses, blocked, (request.Operation == v1beta1.Update), logger)
+	events := generateEvents(engineResponses, blocked, (request.Operation == v1beta1.Update), event.PolicyApplied, logger)
 	v.eventGen.Add(events...)
 	if blocked {
 		logger.V(4).Info("resource blocked")
@@ -107,16 +79,19 @@ func (v *validationHandler) handleValidation(
 		go registerAdmissionReviewDurationMetricValidate(promConfig, logger, string(request.Operation), engineResponses, admissionReviewLatencyDuration)
 		//registering the kyverno_admission_requests_total metric concurrently
 		go registerAdmissionRequestsMetricValidate(promConfig, logger, string(request.Operation), engineResponses)
-		return false, getEnforceFailureErrorMsg(engineResponses)
+		return false, getEnforceFailureErrorMsg(engineResponses), engineResponses
 	}
 
 	if request.Operation == v1beta1.Delete {
 		v.prGenerator.Add(buildDeletionPrInfo(policyContext.OldResource))
-		return true, ""
+		return true, "", engineResponses
 	}
 
-	prInfos := policyreport.GeneratePRsFromEngineResponse(engineResponses, logger)
-	v.prGenerator.Add(prInfos...)
+	// policy reports contain the results for resources, requests for subresources are not reported
+	if request.SubResource == "" {
+		prInfos := policyreport.GeneratePRsFromEngineResponse(engineResponses, logger)
+		v.prGenerator.Add(prInfos...)
+	}
 
 	//registering the kyverno_admission_review_duration_seconds metric concurrently
 	admissionReviewLatencyDuration := int64(time.Since(time.Unix(admissionRequestTimestamp, 0)))
@@ -124,7 +99,140 @@ func (v *validationHandler) handleValidation(
 
 	//registering the kyverno_admission_requests_total metric concurrently
 	go registerAdmissionRequestsMetricValidate(promConfig, logger, string(request.Operation), engineResponses)
-	return true, ""
+	return true, "", engineResponses
+}
+
+// getAuditWarnings evaluates the audit policies that emit admission warnings and returns the messages
+// of the failed rules. The events and the policy reports of these policies are generated from the same
+// engine responses, the audit handler does not evaluate them again in the background. The admission
+// request metrics are recorded by handleValidation, and the deleted resources are removed from the
+// policy reports by the audit handler, so no warning is returned on delete.
+func (v *validationHandler) getAuditWarnings(
+	promConfig *metrics.PromConfig,
+	request *v1beta1.AdmissionRequest,
+	policies []*v1.ClusterPolicy,
+	policyContext *engine.PolicyContext,
+	namespaceLabels map[string]string) []string {
+
+	if len(policies) == 0 || request.Operation == v1beta1.Delete || isDeletingOnUpdate(request, policyContext) {
+		return nil
+	}
+
+	resourceName := getResourceName(request)
+	logger := v.log.WithValues("action", "warn", "resource", resourceName, "operation", request.Operation, "gvk", request.Kind.String())
+
+	engineResponses := v.validatePolicies(promConfig, request, policies, policyContext, namespaceLabels, logger)
+
+	events := generateEvents(engineResponses, false, (request.Operation == v1beta1.Update), event.PolicyApplied, logger)
+	v.eventGen.Add(events...)
+
+	// policy reports contain the results for resources, requests for subresources are not reported
+	if request.SubResource == "" {
+		prInfos := policyreport.GeneratePRsFromEngineResponse(engineResponses, logger)
+		v.prGenerator.Add(prInfos...)
+	}
+
+	var warnings []string
+	for _, engineResponse := range engineResponses {
+		if !engineResponse.IsSuccessful() {
+			warnings = append(warnings, getWarningMessages(engineResponse)...)
+		}
+	}
+
+	return warnings
+}
+
+// validatePolicies evaluates the policies and records the policy metrics, the empty engine responses
+// are skipped
+func (v *validationHandler) validatePolicies(
+	promConfig *metrics.PromConfig,
+	request *v1beta1.AdmissionRequest,
+	policies []*v1.ClusterPolicy,
+	policyContext *engine.PolicyContext,
+	namespaceLabels map[string]string,
+	logger logr.Logger) []*response.EngineResponse {
+
+	var engineResponses []*response.EngineResponse
+	for i, engineResponse := range v.evaluatePolicies(policies, policyContext, namespaceLabels, logger) {
+		policy := policies[i]
+		if reflect.DeepEqual(engineResponse, response.EngineResponse{}) {
+			// we get an empty response if old and new resources created the same response
+			// allow updates if resource update doesnt change the policy evaluation
+			continue
+		}
+
+		// registering the kyverno_policy_results_total metric concurrently
+		go registerPolicyResultsMetricValidation(promConfig, logger, string(request.Operation), *policy, *engineResponse)
+		// registering the kyverno_policy_execution_duration_seconds metric concurrently
+		go registerPolicyExecutionDurationMetricValidate(promConfig, logger, string(request.Operation), *policy, *engineResponse)
+
+		engineResponses = append(engineResponses, engineResponse)
+		if !engineResponse.IsSuccessful() {
+			logger.V(2).Info("validation failed", "policy", policy.Name, "failed rules", engineResponse.GetFailedRules())
+			continue
+		}
+
+		if len(engineResponse.GetSuccessRules()) > 0 {
+			logger.V(2).Info("validation passed", "policy", policy.Name)
+		}
+	}
+
+	return engineResponses
+}
+
+// isDeletingOnUpdate returns true for the updates of a resource being deleted, these are not validated
+func isDeletingOnUpdate(request *v1beta1.AdmissionRequest, policyContext *engine.PolicyContext) bool {
+	var deletionTimeStamp *metav1.Time
+	if reflect.DeepEqual(policyContext.NewResource, unstructured.Unstructured{}) {
+		deletionTimeStamp = policyContext.NewResource.GetDeletionTimestamp()
+	} else {
+		deletionTimeStamp = policyContext.OldResource.GetDeletionTimestamp()
+	}
+
+	return deletionTimeStamp != nil && request.Operation == v1beta1.Update
+}
+
+// evaluatePolicies evaluates the validate rules of the policies concurrently, with at most v.workers
+// policies evaluated at a time. Each policy is evaluated with its own copy of the policy context and
+// the engine responses are returned in the order of the policies.
+func (v *validationHandler) evaluatePolicies(
+	policies []*v1.ClusterPolicy,
+	policyContext *engine.PolicyContext,
+	namespaceLabels map[string]string,
+	logger logr.Logger) []*response.EngineResponse {
+
+	engineResponses := make([]*response.EngineResponse, len(policies))
+	workers := v.workers
+	if workers > len(policies) {
+		workers = len(policies)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				logger.V(3).Info("evaluating policy", "policy", policies[i].Name)
+				ctx := policyContext.Copy()
+				ctx.Policy = *policies[i]
+				ctx.NamespaceLabels = namespaceLabels
+				engineResponses[i] = engine.Validate(ctx)
+			}
+		}()
+	}
+
+	for i := range policies {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return engineResponses
 }
 
 func getResourceName(request *v1beta1.AdmissionRequest) string {
@@ -0,0 +1,200 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine"
+	enginectx "github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/event"
+	"github.com/kyverno/kyverno/pkg/metrics"
+	"github.com/kyverno/kyverno/pkg/policyreport"
+	"gotest.tools/assert"
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var benchmarkResource = []byte(`{
+	"apiVersion": "v1",
+	"kind": "Pod",
+	"metadata": {
+		"name": "nginx",
+		"namespace": "default",
+		"labels": {"app": "nginx"}
+	},
+	"spec": {
+		"containers": [
+			{"name": "nginx", "image": "nginx:1.21", "resources": {"limits": {"memory": "256Mi"}}},
+			{"name": "sidecar", "image": "busybox:latest"}
+		]
+	}
+}`)
+
+// newValidatePolicy returns a policy that checks the given label, policies with odd indexes fail
+func newValidatePolicy(t testing.TB, index int) *v1.ClusterPolicy {
+	label := "app"
+	if index%2 == 1 {
+		label = "team"
+	}
+
+	rawPolicy := []byte(fmt.Sprintf(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "policy-%d"},
+		"spec": {
+			"validationFailureAction": "enforce",
+			"rules": [
+				{
+					"name": "check-label",
+					"match": {"resources": {"kinds": ["Pod"]}},
+					"validate": {
+						"message": "label '%s' is required",
+						"pattern": {"metadata": {"labels": {"%s": "?*"}}}
+					}
+				},
+				{
+					"name": "check-images",
+					"match": {"resources": {"kinds": ["Pod"]}},
+					"validate": {
+						"message": "images must not use the latest tag",
+						"deny": {
+							"conditions": [{
+								"key": "{{ request.object.spec.containers[?ends_with(image, ':latest')] | length(@) }}",
+								"operator": "GreaterThan",
+								"value": %d
+							}]
+						}
+					}
+				}
+			]
+		}
+	}`, index, label, label, 1-index%2))
+
+	var policy v1.ClusterPolicy
+	err := json.Unmarshal(rawPolicy, &policy)
+	assert.NilError(t, err)
+	return &policy
+}
+
+func newValidatePolicyContext(t testing.TB) *engine.PolicyContext {
+	resource, err := utils.ConvertToUnstructured(benchmarkResource)
+	assert.NilError(t, err)
+
+	ctx := enginectx.NewContext()
+	err = ctx.AddResource(benchmarkResource)
+	assert.NilError(t, err)
+
+	return &engine.PolicyContext{
+		NewResource: *resource,
+		JSONContext: ctx,
+	}
+}
+
+func Test_evaluatePolicies(t *testing.T) {
+	var policies []*v1.ClusterPolicy
+	for i := 0; i < 20; i++ {
+		policies = append(policies, newValidatePolicy(t, i))
+	}
+
+	policyContext := newValidatePolicyContext(t)
+	vh := &validationHandler{log: log.Log, workers: 4}
+	engineResponses := vh.evaluatePolicies(policies, policyContext, nil, log.Log)
+
+	assert.Equal(t, len(engineResponses), len(policies))
+	for i, engineResponse := range engineResponses {
+		assert.Equal(t, engineResponse.PolicyResponse.Policy.Name, policies[i].Name)
+		assert.Equal(t, engineResponse.IsSuccessful(), i%2 == 0, policies[i].Name)
+	}
+
+	// the policy context of the request is not modified by the evaluation
+	assert.Equal(t, policyContext.Policy.Name, "")
+	_, err := policyContext.JSONContext.Query("request.object.metadata.name")
+	assert.NilError(t, err)
+}
+
+type fakeEventGenerator struct {
+	events []event.Info
+}
+
+func (f *fakeEventGenerator) Add(infos ...event.Info) {
+	f.events = append(f.events, infos...)
+}
+
+type fakeReportGenerator struct {
+	infos []policyreport.Info
+}
+
+func (f *fakeReportGenerator) Add(infos ...policyreport.Info) {
+	f.infos = append(f.infos, infos...)
+}
+
+func Test_getAuditWarnings(t *testing.T) {
+	var policies []*v1.ClusterPolicy
+	for i := 0; i < 4; i++ {
+		policy := newValidatePolicy(t, i)
+		policy.Spec.ValidationFailureAction = "audit"
+		policies = append(policies, policy)
+	}
+
+	promConfig := metrics.NewPromConfig()
+	eventGen := &fakeEventGenerator{}
+	prGenerator := &fakeReportGenerator{}
+	vh := &validationHandler{log: log.Log, eventGen: eventGen, prGenerator: prGenerator, workers: 2}
+
+	request := &v1beta1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		Name:      "nginx",
+		Namespace: "default",
+		Operation: v1beta1.Create,
+	}
+
+	warnings := vh.getAuditWarnings(promConfig, request, policies, newValidatePolicyContext(t), nil)
+	assert.DeepEqual(t, warnings, []string{
+		"policy policy-1.check-label: validation error: label 'team' is required. Rule check-label failed at path /metadata/labels/team/",
+		"policy policy-1.check-images: images must not use the latest tag",
+		"policy policy-3.check-label: validation error: label 'team' is required. Rule check-label failed at path /metadata/labels/team/",
+		"policy policy-3.check-images: images must not use the latest tag",
+	})
+
+	// the report of each policy is queued once
+	assert.Equal(t, len(prGenerator.infos), len(policies))
+	assert.Assert(t, len(eventGen.events) > 0)
+
+	// the admission request metrics are recorded by handleValidation only
+	families, err := promConfig.MetricsRegistry.Gather()
+	assert.NilError(t, err)
+	for _, family := range families {
+		assert.Assert(t, family.GetName() != "kyverno_admission_requests_total")
+		assert.Assert(t, family.GetName() != "kyverno_admission_review_duration_seconds")
+	}
+
+	// the deleted resources are removed from the reports by the audit handler
+	prGenerator.infos = nil
+	request.Operation = v1beta1.Delete
+	warnings = vh.getAuditWarnings(promConfig, request, policies, newValidatePolicyContext(t), nil)
+	assert.Assert(t, warnings == nil)
+	assert.Equal(t, len(prGenerator.infos), 0)
+}
+
+func BenchmarkEvaluatePolicies(b *testing.B) {
+	var policies []*v1.ClusterPolicy
+	for i := 0; i < 20; i++ {
+		policies = append(policies, newValidatePolicy(b, i))
+	}
+
+	for _, workers := range []int{1, 4, 10} {
+		b.Run(fmt.Sprintf("workers-%d", workers), func(b *testing.B) {
+			policyContext := newValidatePolicyContext(b)
+			vh := &validationHandler{log: log.Log, workers: workers}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				vh.evaluatePolicies(policies, policyContext, nil, log.Log)
+			}
+		})
+	}
+}
@@ -7,25 +7,27 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine"
 	"github.com/kyverno/kyverno/pkg/engine/response"
 	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/event"
+	"github.com/kyverno/kyverno/pkg/policyreport"
 	"k8s.io/api/admission/v1beta1"
 )
 
-func (ws *WebhookServer) applyImageVerifyPolicies(request *v1beta1.AdmissionRequest, policyContext *engine.PolicyContext, policies []*v1.ClusterPolicy, logger logr.Logger) ([]byte, error) {
-	ok, message, imagePatches := ws.handleVerifyImages(request, policyContext, policies)
+func (ws *WebhookServer) applyImageVerifyPolicies(request *v1beta1.AdmissionRequest, policyContext *engine.PolicyContext, policies []*v1.ClusterPolicy, logger logr.Logger) ([]byte, []*response.EngineResponse, error) {
+	ok, message, imagePatches, engineResponses := ws.handleVerifyImages(request, policyContext, policies)
 	if !ok {
-		return nil, errors.New(message)
+		return nil, engineResponses, errors.New(message)
 	}
 
 	logger.V(6).Info("images verified", "patches", string(imagePatches))
-	return imagePatches, nil
+	return imagePatches, engineResponses, nil
 }
 
 func (ws *WebhookServer) handleVerifyImages(request *v1beta1.AdmissionRequest,
 	policyContext *engine.PolicyContext,
-	policies []*v1.ClusterPolicy) (bool, string, []byte) {
+	policies []*v1.ClusterPolicy) (bool, string, []byte, []*response.EngineResponse) {
 
 	if len(policies) == 0 {
-		return true, "", nil
+		return true, "", nil, nil
 	}
 
 	resourceName := getResourceName(request)
@@ -41,10 +43,16 @@ func (ws *WebhookServer) handleVerifyImages(request *v1beta1.AdmissionRequest,
 	}
 
 	blocked := toBlockResource(engineResponses, logger)
+	events := generateEvents(engineResponses, blocked, (request.Operation == v1beta1.Update), event.ImageVerified, logger)
+	ws.eventGen.Add(events...)
 	if blocked {
 		logger.V(4).Info("resource blocked")
-		return false, getEnforceFailureErrorMsg(engineResponses), nil
+		return false, getEnforceFailureErrorMsg(engineResponses), nil, engineResponses
 	}
 
-	return true, "", engineutils.JoinPatches(patches)
+	// failures of policies in audit mode are reported in policy reports
+	prInfos := policyreport.GeneratePRsFromEngineResponse(engineResponses, logger)
+	ws.prGenerator.Add(prInfos...)
+
+	return true, "", engineutils.JoinPatches(patches), engineResponses
 }
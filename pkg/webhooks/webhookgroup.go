@@ -0,0 +1,67 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/julienschmidt/httprouter"
+	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/webhookconfig"
+)
+
+// webhookGroupKey is the context key of the webhook group that received an admission request
+type webhookGroupKey struct{}
+
+// withWebhookGroup adds the webhook group of the request path parameters to the context,
+// the context is unchanged for the paths of the default webhooks
+func withWebhookGroup(ctx context.Context) (context.Context, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	failurePolicy, timeoutSeconds := params.ByName("failurePolicy"), params.ByName("timeoutSeconds")
+	if failurePolicy == "" && timeoutSeconds == "" {
+		return ctx, nil
+	}
+
+	group, err := webhookconfig.ParseWebhookGroup(failurePolicy, timeoutSeconds)
+	if err != nil {
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, webhookGroupKey{}, group), nil
+}
+
+// isDefaultWebhookGroup returns true if the request was received by a default resource webhook. Besides the
+// policies without webhook settings, the default webhooks apply the generate rules, the background audit
+// of the validation rules and the clean up of deleted generate resources.
+func isDefaultWebhookGroup(ctx context.Context) bool {
+	_, ok := ctx.Value(webhookGroupKey{}).(webhookconfig.WebhookGroup)
+	return !ok
+}
+
+// filterWebhookGroup returns the policies applied by the webhook group that received the request,
+// registered are the groups of the resource webhook configuration that received the request
+func (ws *WebhookServer) filterWebhookGroup(ctx context.Context, policies []*v1.ClusterPolicy, registered map[webhookconfig.WebhookGroup]bool) []*v1.ClusterPolicy {
+	return webhookGroupPolicies(ctx, policies, ws.webhookRegister.DefaultWebhookGroup(ws.configHandler), registered)
+}
+
+// webhookGroupPolicies returns the policies of the webhook group of the request. The default webhook also applies
+// the policies whose group webhook is not registered yet. No policy is applied on the path of the default group,
+// e.g. by a group webhook registered before the defaults changed, as the default webhook applies these policies.
+func webhookGroupPolicies(ctx context.Context, policies []*v1.ClusterPolicy, defaultGroup webhookconfig.WebhookGroup, registered map[webhookconfig.WebhookGroup]bool) []*v1.ClusterPolicy {
+	group, ok := ctx.Value(webhookGroupKey{}).(webhookconfig.WebhookGroup)
+	if ok && group == defaultGroup {
+		return []*v1.ClusterPolicy{}
+	}
+
+	filtered := make([]*v1.ClusterPolicy, 0, len(policies))
+	for _, policy := range policies {
+		policyGroup := webhookconfig.PolicyWebhookGroup(policy, defaultGroup.FailurePolicy, defaultGroup.TimeoutSeconds)
+		if ok && policyGroup == group {
+			filtered = append(filtered, policy)
+		}
+
+		if !ok && (policyGroup == defaultGroup || !registered[policyGroup]) {
+			filtered = append(filtered, policy)
+		}
+	}
+
+	return filtered
+}
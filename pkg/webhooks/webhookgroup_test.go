@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/webhookconfig"
+	"gotest.tools/assert"
+)
+
+func newWebhookGroupPolicy(t *testing.T, name, spec string) *v1.ClusterPolicy {
+	policy := &v1.ClusterPolicy{}
+	policy.SetName(name)
+	assert.NilError(t, json.Unmarshal([]byte(spec), &policy.Spec))
+	return policy
+}
+
+func policyNames(policies []*v1.ClusterPolicy) []string {
+	names := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		names = append(names, policy.GetName())
+	}
+
+	return names
+}
+
+func Test_WebhookGroupPolicies(t *testing.T) {
+	defaultGroup := webhookconfig.WebhookGroup{FailurePolicy: "Ignore", TimeoutSeconds: 10}
+	failGroup := webhookconfig.WebhookGroup{FailurePolicy: "Fail", TimeoutSeconds: 15}
+
+	policies := []*v1.ClusterPolicy{
+		newWebhookGroupPolicy(t, "default", `{}`),
+		newWebhookGroupPolicy(t, "default-settings", `{"failurePolicy": "Ignore", "webhookTimeoutSeconds": 10}`),
+		newWebhookGroupPolicy(t, "fail", `{"failurePolicy": "Fail", "webhookTimeoutSeconds": 15}`),
+		newWebhookGroupPolicy(t, "slow", `{"webhookTimeoutSeconds": 30}`),
+	}
+
+	registered := map[webhookconfig.WebhookGroup]bool{failGroup: true}
+
+	// the default webhook applies the policies whose group webhook is not registered yet
+	filtered := webhookGroupPolicies(context.Background(), policies, defaultGroup, registered)
+	assert.DeepEqual(t, policyNames(filtered), []string{"default", "default-settings", "slow"})
+
+	// the group webhook applies the policies of its group
+	ctx := context.WithValue(context.Background(), webhookGroupKey{}, failGroup)
+	filtered = webhookGroupPolicies(ctx, policies, defaultGroup, registered)
+	assert.DeepEqual(t, policyNames(filtered), []string{"fail"})
+
+	// the path of the default group applies no policy, the default webhook applies them
+	ctx = context.WithValue(context.Background(), webhookGroupKey{}, defaultGroup)
+	filtered = webhookGroupPolicies(ctx, policies, defaultGroup, map[webhookconfig.WebhookGroup]bool{defaultGroup: true})
+	assert.Equal(t, len(filtered), 0)
+	assert.Assert(t, !isDefaultWebhookGroup(ctx))
+}
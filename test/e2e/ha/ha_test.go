@@ -0,0 +1,223 @@
+package ha
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/kyverno/test/e2e"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var (
+	// Cluster Policy GVR
+	clPolGVR = e2e.GetGVR("kyverno.io", "v1", "clusterpolicies")
+
+	// Namespace GVR
+	nsGVR = e2e.GetGVR("", "v1", "namespaces")
+
+	// ConfigMap GVR
+	cmGVR = e2e.GetGVR("", "v1", "configmaps")
+
+	// Deployment GVR
+	deployGVR = e2e.GetGVR("apps", "v1", "deployments")
+
+	// Pod GVR
+	podGVR = e2e.GetGVR("", "v1", "pods")
+
+	// Lease GVR
+	leaseGVR = e2e.GetGVR("coordination.k8s.io", "v1", "leases")
+
+	// Generate Request GVR
+	grGVR = e2e.GetGVR("kyverno.io", "v1", "generaterequests")
+
+	// Kyverno Namespace
+	kyvernoNS = "kyverno"
+
+	// Kyverno leader election Lease
+	leaseName = "kyverno"
+
+	// name of the generate policy
+	policyName = "gen-ha-configmap"
+
+	// number of Namespaces created while the leader is killed
+	namespaceCount = 10
+)
+
+func Test_Generate_Leader_Failover(t *testing.T) {
+	RegisterTestingT(t)
+	if os.Getenv("E2E") == "" {
+		t.Skip("Skipping E2E Test")
+	}
+	// Generate E2E Client ==================
+	e2eClient, err := e2e.NewE2EClient()
+	Expect(err).To(BeNil())
+	// ======================================
+
+	deploy, err := e2eClient.GetNamespacedResource(deployGVR, kyvernoNS, "kyverno")
+	Expect(err).NotTo(HaveOccurred())
+	replicas, _, _ := unstructured.NestedInt64(deploy.UnstructuredContent(), "spec", "replicas")
+	if replicas < 2 {
+		t.Skip("Skipping HA E2E Test, Kyverno runs with less than 2 replicas")
+	}
+
+	namespaces := make([]string, namespaceCount)
+	for i := range namespaces {
+		namespaces[i] = fmt.Sprintf("ha-test-%d", i)
+	}
+
+	// ======= CleanUp Resources =====
+	By("Cleaning Cluster Policies")
+	_ = e2eClient.CleanClusterPolicies(clPolGVR)
+	cleanNamespaces(e2eClient, namespaces)
+	// =====================================================
+
+	// ======== Create Generate Policy =============
+	By("Creating Generate ConfigMap Policy")
+	_, err = e2eClient.CreateNamespacedResourceYaml(clPolGVR, "", genConfigMapPolicyYaml)
+	Expect(err).NotTo(HaveOccurred())
+
+	// the policy is cached by every replica from its informer
+	time.Sleep(5 * time.Second)
+	// =====================================================
+
+	// ======== Find the Leader =============
+	leader, err := leaderPod(e2eClient)
+	Expect(err).NotTo(HaveOccurred())
+	By(fmt.Sprintf("Current leader : %s", leader))
+	// =====================================================
+
+	// ======= Create Namespaces and kill the Leader ==================
+	By("Creating Namespaces which trigger generate")
+	for _, ns := range namespaces {
+		_, err = e2eClient.CreateClusteredResourceYaml(nsGVR, namespaceYaml(ns))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	By("Waiting for the generate requests to be created")
+	var pending []string
+	err = e2e.GetWithRetry(100*time.Millisecond, 100, func() error {
+		pending, err = pendingGenerateRequests(e2eClient)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return fmt.Errorf("no pending generate request for policy %s", policyName)
+		}
+		return nil
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	By(fmt.Sprintf("Deleting the leader Pod %s while %d generate requests are pending", leader, len(pending)))
+	err = e2eClient.DeleteNamespacedResource(podGVR, kyvernoNS, leader)
+	Expect(err).NotTo(HaveOccurred())
+	// =====================================================
+
+	// ======== Verify Failover =====
+	By("Verifying a new leader is elected")
+	err = e2e.GetWithRetry(1*time.Second, 60, func() error {
+		newLeader, err := leaderPod(e2eClient)
+		if err != nil {
+			return err
+		}
+		if newLeader == leader {
+			return fmt.Errorf("leader %s not replaced yet", leader)
+		}
+		return nil
+	})
+	Expect(err).NotTo(HaveOccurred())
+	// ============================================
+
+	// ======== Verify Generate Requests Completion =====
+	By("Verifying the new leader completes the pending generate requests")
+	for _, name := range pending {
+		err = e2e.GetWithRetry(1*time.Second, 60, func() error {
+			gr, err := e2eClient.GetNamespacedResource(grGVR, kyvernoNS, name)
+			if err != nil {
+				return err
+			}
+			state, _, _ := unstructured.NestedString(gr.UnstructuredContent(), "status", "state")
+			if state != "Completed" {
+				return fmt.Errorf("generate request %s is in state %q", name, state)
+			}
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+	// ============================================
+
+	// ======== Verify ConfigMap Creation =====
+	By("Verifying the ConfigMaps are generated in each Namespace")
+	for _, ns := range namespaces {
+		err = e2e.GetWithRetry(1*time.Second, 60, func() error {
+			_, err := e2eClient.GetNamespacedResource(cmGVR, ns, "ha-configmap")
+			return err
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+	// ============================================
+
+	// ======= CleanUp Resources =====
+	_ = e2eClient.CleanClusterPolicies(clPolGVR)
+	cleanNamespaces(e2eClient, namespaces)
+
+	By("Test Generate Leader Failover Completed \n\n\n")
+}
+
+// leaderPod returns the name of the Pod holding the leader election Lease,
+// the holder identity is the Pod host name followed by a unique identifier
+func leaderPod(e2eClient *e2e.E2EClient) (string, error) {
+	lease, err := e2eClient.GetNamespacedResource(leaseGVR, kyvernoNS, leaseName)
+	if err != nil {
+		return "", err
+	}
+
+	holder, _, _ := unstructured.NestedString(lease.UnstructuredContent(), "spec", "holderIdentity")
+	if holder == "" {
+		return "", fmt.Errorf("lease %s/%s has no holder", kyvernoNS, leaseName)
+	}
+
+	return strings.Split(holder, "_")[0], nil
+}
+
+// pendingGenerateRequests returns the names of the generate requests of the policy which are not completed yet
+func pendingGenerateRequests(e2eClient *e2e.E2EClient) ([]string, error) {
+	grs, err := e2eClient.ListNamespacedResources(grGVR, kyvernoNS)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, gr := range grs.Items {
+		policy, _, _ := unstructured.NestedString(gr.UnstructuredContent(), "spec", "policy")
+		state, _, _ := unstructured.NestedString(gr.UnstructuredContent(), "status", "state")
+		if policy == policyName && state != "Completed" {
+			pending = append(pending, gr.GetName())
+		}
+	}
+
+	return pending, nil
+}
+
+func cleanNamespaces(e2eClient *e2e.E2EClient, namespaces []string) {
+	for _, ns := range namespaces {
+		_ = e2eClient.DeleteClusteredResource(nsGVR, ns)
+	}
+
+	// Wait Till Deletion of Namespaces
+	for _, ns := range namespaces {
+		err := e2e.GetWithRetry(1*time.Second, 30, func() error {
+			_, err := e2eClient.GetClusteredResource(nsGVR, ns)
+			if err != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to delete namespace: %s", ns)
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+}
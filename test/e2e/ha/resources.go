@@ -0,0 +1,40 @@
+package ha
+
+// Cluster Policy to generate a ConfigMap in each new Namespace
+var genConfigMapPolicyYaml = []byte(`
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: "gen-ha-configmap"
+spec:
+  background: false
+  rules:
+  - name: "gen-configmap"
+    match:
+      resources:
+        kinds:
+        - Namespace
+        selector:
+          matchLabels:
+            kyverno-e2e: ha
+    generate:
+      kind: ConfigMap
+      name: "ha-configmap"
+      namespace: "{{request.object.metadata.name}}"
+      synchronize: false
+      data:
+        data:
+          leader-failover: "true"
+`)
+
+// namespaceYaml returns a Namespace matched by the generate policy
+func namespaceYaml(name string) []byte {
+	return []byte(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ` + name + `
+  labels:
+    kyverno-e2e: ha
+`)
+}